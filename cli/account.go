@@ -26,8 +26,9 @@ func (cli *CLI) buildAccountCmd() *cobra.Command {
 		Use:   use,
 		Short: fmt.Sprintf("Manage %s accounts", cli.bc.String()),
 		Args:  cobra.MinimumNArgs(1),
-		Run: func(cmd *cobra.Command, args []string) {
-			return
+		RunE: func(cmd *cobra.Command, args []string) error {
+			fmt.Fprint(os.Stderr, cmd.UsageString())
+			return newErrorf(ErrCodeInvalidArgument, "Error: unknown command %q for %q", args[0], cmd.CommandPath())
 		},
 	}
 
@@ -47,7 +48,7 @@ func (cli *CLI) buildAccountNewCmd() *cobra.Command {
 		Short:                 "create a new account",
 		Args:                  cobra.MinimumNArgs(0),
 		DisableFlagsInUseLine: true,
-		Run: func(cmd *cobra.Command, args []string) {
+		RunE: func(cmd *cobra.Command, args []string) error {
 			var err error
 			walletPath := cli.walletPath
 			if cmd.Flags().Changed("light") {
@@ -66,8 +67,7 @@ func (cli *CLI) buildAccountNewCmd() *cobra.Command {
 			if cli.walletPassword == "" {
 				cli.walletPassword, err = getPassPhrase("Your new account is locked with a password. Please give a password. Do not forget this password.", true)
 				if err != nil {
-					fmt.Println("Error: ", err)
-					return
+					return promptError(err)
 				}
 			}
 
@@ -85,8 +85,7 @@ func (cli *CLI) buildAccountNewCmd() *cobra.Command {
 			for i := 0; i < numOfNew; i++ {
 				account, err := cli.wallet.NewAccount(cli.walletPassword)
 				if err != nil {
-					fmt.Println("Account error:", err)
-					return
+					return newErrorf(ErrCodeWallet, "Account error: %v", err)
 				}
 				fmt.Println(account.Address.Hex())
 				if faucet {
@@ -96,6 +95,8 @@ func (cli *CLI) buildAccountNewCmd() *cobra.Command {
 					cli.address = account.Address.String()
 				}
 			}
+
+			return nil
 		},
 	}
 
@@ -111,18 +112,19 @@ func (cli *CLI) buildAccountListCmd() *cobra.Command {
 		Use:   "list",
 		Short: "list all accounts in the wallet path",
 		Args:  cobra.MinimumNArgs(0),
-		Run: func(cmd *cobra.Command, args []string) {
+		RunE: func(cmd *cobra.Command, args []string) error {
 			walletPath := cli.walletPath
 			wallet := keystore.NewKeyStore(walletPath,
 				keystore.LightScryptN, keystore.LightScryptP)
 			if len(wallet.Accounts()) == 0 {
-				fmt.Println("Empty wallet, create account first.")
-				return
+				return newErrorf(ErrCodeWallet, "Empty wallet, create account first.")
 			}
 
 			for _, account := range wallet.Accounts() {
 				fmt.Println(account.Address.Hex())
 			}
+
+			return nil
 		},
 	}
 
@@ -135,21 +137,19 @@ func (cli *CLI) buildBalanceCmd() *cobra.Command {
 		Short:                 "Get balance of address",
 		Args:                  cobra.MinimumNArgs(0),
 		DisableFlagsInUseLine: true,
-		Run: func(cmd *cobra.Command, args []string) {
+		RunE: func(cmd *cobra.Command, args []string) error {
 
 			unit, _ := cmd.Flags().GetString("unit")
 			if unit != "" && !stringInSlice(unit, UnitList) {
-				fmt.Printf("Unit(%s) for invalid. %s.\n", unit, fmt.Sprintf("Available unit: %s", strings.Join(UnitList, ",")))
 				fmt.Fprint(os.Stderr, cmd.UsageString())
-				return
+				return newErrorf(ErrCodeInvalidArgument, "Unit(%s) for invalid. Available unit: %s.", unit, strings.Join(UnitList, ","))
 			}
 
 			var addressList []common.Address
 
 			if len(args) <= 0 {
 				if err := cli.openWallet(true); err != nil {
-					fmt.Println(err)
-					return
+					return newError(ErrCodeWallet, err)
 				}
 
 				for _, account := range cli.wallet.Accounts() {
@@ -162,16 +162,27 @@ func (cli *CLI) buildBalanceCmd() *cobra.Command {
 				}
 			}
 
+			type balanceOutput struct {
+				Address common.Address `json:"address"`
+				Balance *big.Int       `json:"balance"`
+			}
+			outputs := make([]balanceOutput, 0, len(addressList))
 			for _, address := range addressList {
 				balance, err := cli.getBalance(address)
 				if err != nil {
-					fmt.Println("Balance error:", err)
-					return
+					return newErrorf(ErrCodeRPC, "Balance error: %v", err)
+				}
+				if cli.isJSON() {
+					outputs = append(outputs, balanceOutput{Address: address, Balance: balance})
+					continue
 				}
 				fmt.Printf("Address[%s] Balance[%s]\n", address.Hex(), getWeiAmountTextUnitByUnit(balance, unit))
 			}
+			if cli.isJSON() {
+				cli.printJSON(outputs)
+			}
 
-			return
+			return nil
 		},
 	}
 
@@ -193,17 +204,15 @@ func (cli *CLI) buildAccountConvertCmd() *cobra.Command {
 		Short:                 "convert address to NewChainAddress",
 		Args:                  cobra.MinimumNArgs(1),
 		DisableFlagsInUseLine: true,
-		Run: func(cmd *cobra.Command, args []string) {
+		RunE: func(cmd *cobra.Command, args []string) error {
 
 			err := cli.BuildClient()
 			if err != nil {
-				fmt.Printf("Error: build client error(%v)\n", err)
-				return
+				return newErrorf(ErrCodeRPC, "Error: build client error(%v)", err)
 			}
 			chainID, err := cli.client.NetworkID(context.Background())
 			if err != nil {
-				fmt.Printf("Error: get chainID error(%v), use chainID as %s\n", err, chainID.String())
-				return
+				return newErrorf(ErrCodeRPC, "Error: get chainID error(%v)", err)
 			}
 
 			for _, addressStr := range args {
@@ -221,6 +230,7 @@ func (cli *CLI) buildAccountConvertCmd() *cobra.Command {
 				fmt.Println(address.String(), addressStr)
 			}
 
+			return nil
 		},
 	}
 
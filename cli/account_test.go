@@ -12,3 +12,11 @@ func TestAccount(t *testing.T) {
 	cli.TestCommand("account list")
 
 }
+
+func TestAccountUnknown(t *testing.T) {
+	cli := NewCLI()
+	cli.rootCmd.SetArgs([]string{"account", "bogus"})
+	if err := cli.rootCmd.Execute(); ExitCode(err) != ExitInvalidArgument {
+		t.Fatalf("account with unknown command got error %v", err)
+	}
+}
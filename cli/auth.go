@@ -16,10 +16,6 @@ func NewKeyedTransactorByAccount(wallet *keystore.KeyStore, account accounts.Acc
 	return &bind.TransactOpts{
 		From: account.Address,
 		Signer: func(signer types.Signer, address common.Address, tx *types.Transaction) (*types.Transaction, error) {
-			// force update gas to 10 * gas
-			if tx.To() != nil {
-				tx = types.NewTransaction(tx.Nonce(), *tx.To(), tx.Value(), tx.Gas()*10, tx.GasPrice(), tx.Data())
-			}
 			fmt.Println("The tx is as follow: ")
 			fmt.Println("\tFrom:", account.Address.String())
 			if tx.To() == nil {
@@ -32,7 +28,7 @@ func NewKeyedTransactorByAccount(wallet *keystore.KeyStore, account accounts.Acc
 			fmt.Println("\tNonce:", tx.Nonce())
 			fmt.Println("\tGasPrice:", getWeiAmountTextByUnit(tx.GasPrice(), UnitETH))
 			fmt.Println("\tGasLimit:", tx.Gas())
-			fmt.Println("\tGasFee:", getWeiAmountTextByUnit(big.NewInt(0).Mul(tx.GasPrice(), big.NewInt(0).SetUint64(tx.Gas())), UnitETH))
+			printMaxFee(tx.Gas(), tx.GasPrice(), tx.Value())
 
 			for trials := 0; trials <= 1; trials++ {
 				err := wallet.Unlock(account, passphrase)
@@ -44,7 +40,10 @@ func NewKeyedTransactorByAccount(wallet *keystore.KeyStore, account accounts.Acc
 
 				}
 				prompt := fmt.Sprintf("Unlocking account %s", account.Address.String())
-				passphrase, _ = getPassPhrase(prompt, false)
+				passphrase, err = getPassPhrase(prompt, false)
+				if err = promptError(err); errorCode(err) == ErrCodeUserAbort {
+					return nil, err
+				}
 			}
 
 			return wallet.SignTx(account, tx, networkID)
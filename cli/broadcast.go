@@ -1,140 +1,98 @@
 package cli
 
 import (
-	"bufio"
 	"context"
 	"fmt"
-	"math/big"
-	"os"
 	"time"
 
-	ethereum "github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
-	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/ethereum/go-ethereum/rpc"
 	"github.com/spf13/cobra"
 )
 
 func (cli *CLI) buildBroadcastCmd() *cobra.Command {
 	signMesgCmd := &cobra.Command{
-		Use:                   "broadcast <signTxFilePath>",
-		Short:                 "Broadcast sign transacion hex in the signTxFilePath to blockchain",
+		Use:                   "broadcast <signTxFilePath> [--force]",
+		Short:                 "Broadcast signed transaction or signed bundle in the signTxFilePath to blockchain",
 		Args:                  cobra.MinimumNArgs(1),
 		DisableFlagsInUseLine: true,
-		Run: func(cmd *cobra.Command, args []string) {
+		RunE: func(cmd *cobra.Command, args []string) error {
 			infileStr := args[0]
 
-			signTxStr, err := readLineFromFile(infileStr)
+			signed, bundle, err := readSignedFile(infileStr)
 			if err != nil {
-				fmt.Println(err)
-				return
+				return err
 			}
-			fmt.Println(string(signTxStr))
+			force, _ := cmd.Flags().GetBool("force")
+			if bundle {
+				return cli.broadcastBundle(signed, force)
+			}
+			st := signed[0]
+			st.print()
+			fmt.Println(st.Raw)
 
-			signTxByte := common.FromHex(string(signTxStr))
-			signTx := new(types.Transaction)
-			if err := rlp.DecodeBytes(signTxByte, signTx); err != nil {
-				fmt.Println("DecodeBytes signTxHex error: ", err)
-				return
+			// cross-check the hash and signer recorded before sending
+			tx, from, err := st.decode()
+			if err != nil {
+				return err
 			}
 
 			ctx := context.Background()
+			if err := cli.checkBeforeBroadcast(ctx, tx, from, force); err != nil {
+				return err
+			}
 			client, err := rpc.DialContext(ctx, cli.rpcURL)
 			if err != nil {
-				fmt.Println("DialContext: ", err)
-				return
+				return newErrorf(ErrCodeRPC, "DialContext: %v", err)
 			}
-			if err := client.CallContext(ctx, nil, "eth_sendRawTransaction", signTxStr); err != nil {
-				fmt.Println("CallContext Error: ", err)
-				return
-			}
-			fmt.Println("Waiting for transaction receipt...")
-			txp, err := waitMined(ctx, client, signTx.Hash())
+			result, err := cli.sendRawTx(ctx, client, st.Raw, tx, from)
 			if err != nil {
-				fmt.Printf("Error: wait tx mined error(%v)\n", err)
-				return
+				return err
 			}
-			showTransactionReceipt(cli.rpcURL, signTx.Hash().String())
-			cli.showSubmitID(txp)
+			return cli.printSentTx(tx, from, result)
 		},
 	}
+	signMesgCmd.Flags().Bool("force", false, "broadcast even if the pre-flight checks against the chain state fail")
+
 	return signMesgCmd
 }
 
-func readLineFromFile(filepath string) (string, error) {
-	f, err := os.Open(filepath)
-	if err != nil {
-		return "", err
-	}
-	defer f.Close()
-	scanner := bufio.NewScanner(f)
-	scanner.Split(bufio.ScanLines)
-
-	for scanner.Scan() {
-		text := scanner.Text()
-		if len(text) > 0 {
-			return text, nil
-		}
-	}
+// broadcastWaitTimeout is how long broadcast waits for the transaction sent
+// to be mined
+const broadcastWaitTimeout = 10 * time.Minute
 
-	if err := scanner.Err(); err != nil {
-		return "", err
+// sendRawTx sends the signed transaction hex and waits for its receipt,
+// signTx and from are decoded from the hex by SignedTx.decode
+func (cli *CLI) sendRawTx(ctx context.Context, client *rpc.Client, signTxStr string, signTx *types.Transaction, from common.Address) (*receiptResult, error) {
+	if err := client.CallContext(ctx, nil, "eth_sendRawTransaction", signTxStr); err != nil {
+		return nil, newErrorf(ErrCodeRPC, "CallContext Error: %v", err)
 	}
-
-	return "", nil
-}
-
-func waitMined(ctx context.Context, client *rpc.Client, hash common.Hash) (*types.Receipt, error) {
-	transactionReceipt := func() (*types.Receipt, error) {
-		var r *types.Receipt
-		err := client.CallContext(ctx, &r, "eth_getTransactionReceipt", hash)
-		if err == nil {
-			if r == nil {
-				return nil, ethereum.NotFound
-			}
+	cli.journalTx(signTx, from)
+	fmt.Println("Waiting for transaction receipt...")
+	waitCtx, cancel := context.WithTimeout(ctx, broadcastWaitTimeout)
+	defer cancel()
+	_, txp, err := waitFirstMined(waitCtx, ethclient.NewClient(client), from, signTx)
+	if err == errNonceReplaced {
+		if err := cli.confirmNonce(from, signTx.Nonce()); err != nil {
+			fmt.Println("Warning: record nonce in ledger error:", err)
 		}
-		return r, err
+		return nil, newErrorf(ErrCodeNonceReplaced, "Error: nonce %d of %s is replaced by another transaction, %s is not mined",
+			signTx.Nonce(), from.String(), signTx.Hash().String())
+	} else if err == context.DeadlineExceeded {
+		return nil, newErrorf(ErrCodeRPC, "Error: transaction %s is not mined in %v, check it with tx status", signTx.Hash().String(), broadcastWaitTimeout)
+	} else if err != nil {
+		return nil, newErrorf(ErrCodeRPC, "Error: wait tx mined error(%v)", err)
 	}
-
-	queryTicker := time.NewTicker(time.Second)
-	defer queryTicker.Stop()
-
-	for {
-		receipt, err := transactionReceipt()
-		if receipt != nil {
-			return receipt, nil
-		}
-		if err != nil {
-			// logger.Trace("Receipt retrieval failed", "err", err)
-		} else {
-			// logger.Trace("Transaction not yet mined")
-		}
-		// Wait for the next round.
-		select {
-		case <-ctx.Done():
-			return nil, ctx.Err()
-		case <-queryTicker.C:
-		}
+	if err := cli.confirmNonce(from, signTx.Nonce()); err != nil {
+		fmt.Println("Warning: record nonce in ledger error:", err)
 	}
-}
-
-func (cli *CLI) showSubmitID(txp *types.Receipt) bool {
-	if txp != nil && len(txp.Logs) > 0 {
-		log := txp.Logs[0]
-		if log != nil {
-			topics := log.Topics
-			if topics != nil && len(topics) > 1 {
-				// event Submission(uint indexed transactionId)
-				// sha3('Submission(uint256)'): 0xc0ba8fe4b176c1714197d43b9cc6bcf797a4a7461c5fe8d0ef6e184ae7601e51
-				if topics[0] == common.HexToHash("0xc0ba8fe4b176c1714197d43b9cc6bcf797a4a7461c5fe8d0ef6e184ae7601e51") {
-					data := topics[1]
-					transferID := new(big.Int).SetBytes(data.Bytes())
-					fmt.Println("TransferID: ", transferID)
-					return true
-				}
-			}
-		}
+	showTransactionReceipt(cli.rpcURL, signTx.Hash().String())
+	result := &receiptResult{Receipt: txp, Success: txp.Status == types.ReceiptStatusSuccessful}
+	if signTx.To() != nil {
+		result = cli.showReceipt(txp, *signTx.To())
 	}
-	return false
+
+	return result, nil
 }
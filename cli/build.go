@@ -1,16 +1,12 @@
 package cli
 
 import (
-	"context"
-	"errors"
 	"fmt"
 	"math/big"
-	"time"
+	"os"
+	"strings"
 
-	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
-	"github.com/ethereum/go-ethereum/console"
-	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/spf13/cobra"
 )
 
@@ -23,12 +19,13 @@ type PayTransaction struct {
 }
 
 func (cli *CLI) buildBuildCmd() *cobra.Command {
-	signTxCmd := &cobra.Command{
-		Use:                   "build [--out outfile]",
+	buildCmd := &cobra.Command{
+		Use:                   "build [--out outfile] [submit|confirm|revoke|execute|owner|dailylimit|required|token-transfer]",
 		Short:                 "Build transaction",
-		Long:                  "Build transaction in guide",
+		Long:                  "Build transaction in guide, or with the subcommand of the action without prompt",
+		Args:                  cobra.NoArgs,
 		DisableFlagsInUseLine: true,
-		Run: func(cmd *cobra.Command, args []string) {
+		RunE: func(cmd *cobra.Command, args []string) error {
 			var err error
 			var inStr string
 
@@ -37,218 +34,314 @@ func (cli *CLI) buildBuildCmd() *cobra.Command {
 				cli.applyTranDefault()
 			}
 
+			state, err := loadStateFlag(cmd)
+			if err != nil {
+				return err
+			}
+			if state != nil {
+				state.apply(cli.tran)
+				cli.tran.GasPrice, _ = cli.gasPolicy().gasPrice(cli.tran.GasPrice)
+			}
+
 			if cmd.Flags().Changed("in") {
 				inStr, err = cmd.Flags().GetString("in")
 				if err != nil {
-					fmt.Println("Error:", err)
-					return
+					return newError(ErrCodeInvalidArgument, err)
 				}
 				if err := cli.applyTxFile(inStr); err != nil {
-					fmt.Printf("Error: apply infile(%s): %v\n", inStr, err)
-					return
+					return newErrorf(ErrCodeFile, "Error: apply infile(%s): %v", inStr, err)
 				}
 			}
+			if err := cli.applyTxFlags(cmd); err != nil {
+				fmt.Fprint(os.Stderr, cmd.UsageString())
+				return newError(ErrCodeInvalidArgument, err)
+			}
 
 			offline, _ := cmd.Flags().GetBool("offline")
+			if offline {
+				if _, err := cli.applyLedgerNonce(cmd); err != nil {
+					return newError(ErrCodeFile, err)
+				}
+			}
 
 			if cmd.Flags().Changed("noguide") {
 				if ok, _ := cmd.Flags().GetBool("noguide"); !ok {
-					fmt.Println("Error: flag noguide changed but is false")
-					return
+					return newErrorf(ErrCodeInvalidArgument, "Error: flag noguide changed but is false")
+				}
+				if cli.tran.Intent == nil && len(cli.tran.Data) == 0 {
+					return newErrorf(ErrCodeInvalidArgument, "Error: nothing to build, use a subcommand or --in")
 				}
 			} else {
 				if err := cli.applyTxGuide(offline); err != nil {
-					fmt.Println("Error:", err)
-					return
+					return newError(ErrCodeInvalidArgument, promptError(err))
 				}
 			}
 
-			// update nonce, gasPrice, gasLimit, networkID from node
-			if !offline {
-				opts, err := cli.getNoSignTransactOpts()
-				if err != nil {
-					fmt.Println("Error:", err)
-					return
-				}
+			return cli.buildAndSaveTx(cmd, offline, true, state)
+		},
+	}
 
-				simpleRegistry, err := cli.GetSimpleRegistry()
-				if err != nil {
-					fmt.Println("Error: GetSimpleRegistry Error: ", err)
-					return
-				}
-				switch cli.tran.action {
-				case Submit:
-					//payTran := cli.tran.params.(*PayTransaction)
-					if len(cli.tran.params) < 3 {
-						err = errors.New("submitTransaction params length error")
-					} else {
-						_, err = simpleRegistry.SubmitTransaction(opts, cli.tran.params[0].(common.Address), cli.tran.params[1].(*big.Int), cli.tran.params[2].([]byte))
-					}
-				case Confirm:
-					if len(cli.tran.params) < 1 {
-						err = errors.New("confirmTransaction params length error")
-					} else {
-						_, err = simpleRegistry.ConfirmTransaction(opts, cli.tran.params[0].(*big.Int))
-					}
-				case Revoke:
-					if len(cli.tran.params) < 1 {
-						err = errors.New("revokeConfirmation params length error")
-					} else {
-						_, err = simpleRegistry.RevokeConfirmation(opts, cli.tran.params[0].(*big.Int))
-					}
-				case Execute:
-					if len(cli.tran.params) < 1 {
-						err = errors.New("executeTransaction params length error")
-					} else {
-						_, err = simpleRegistry.ExecuteTransaction(opts, cli.tran.params[0].(*big.Int))
-					}
-				// case OwnerAdd:
-				// 	if len(cli.tran.params) < 1 {
-				// 		err = errors.New("add owner params length error")
-				// 	} else {
-				// 		_, err = simpleRegistry.AddOwner(opts, cli.tran.params[0].(common.Address))
-				// 	}
-				// case OwnerRemove:
-				// 	if len(cli.tran.params) < 1 {
-				// 		err = errors.New("remove owner params length error")
-				// 	} else {
-				// 		_, err = simpleRegistry.RemoveOwner(opts, cli.tran.params[0].(common.Address))
-				// 	}
-				// case OwnerReplace:
-				// 	if len(cli.tran.params) < 2 {
-				// 		err = errors.New("replace owner params length error")
-				// 	} else {
-				// 		_, err = simpleRegistry.ReplaceOwner(opts, cli.tran.params[0].(common.Address), cli.tran.params[1].(common.Address))
-				// 	}
-				// case DailyLimit:
-				// 	if len(cli.tran.params) < 1 {
-				// 		err = errors.New("change daily limit params length error")
-				// 	} else {
-				// 		_, err = simpleRegistry.ChangeDailyLimit(opts, cli.tran.params[0].(*big.Int))
-				// 	}
-				// case Required:
-				// 	if len(cli.tran.params) < 1 {
-				// 		err = errors.New("change required params length error")
-				// 	} else {
-				// 		fmt.Println(cli.tran.params[0].(*big.Int).String())
-				// 		_, err = simpleRegistry.ChangeRequirement(opts, cli.tran.params[0].(*big.Int))
-				// 	}
-				default:
-					err = errors.New("unsupported function")
-				}
+	buildCmd.PersistentFlags().String("out", "", "file `path` to save built transaction")
+	buildCmd.PersistentFlags().Bool("offline", false, "build offline transaction, without connecting to node")
+	buildCmd.PersistentFlags().Uint64("nonce", 0, "the `nonce` of from address, required by offline subcommands")
+	buildCmd.PersistentFlags().String("gasPrice", "", "the gas `price` in WEI of offline transaction")
+	buildCmd.PersistentFlags().Uint64("gasLimit", 0, "the gas `limit` of offline transaction, required by offline subcommands")
+	buildCmd.PersistentFlags().String("chainID", "", "the chain `ID` of offline transaction")
+	buildCmd.PersistentFlags().Bool("force", false, "build even if the from address can not make the call on the current chain state, ignored by offline build")
+	buildCmd.PersistentFlags().String("state", "", "the state `file` exported by export-state to fill and check offline transaction")
+	addQRFlags(buildCmd.PersistentFlags())
 
-				if err != nil {
-					if err == errNoSignTransactor {
-						// ok
-					} else {
-						fmt.Println("Error: ", err)
-						return
-					}
-				} else {
-					fmt.Println("ERROR")
-					return
-				}
-			}
+	buildCmd.Flags().String("in", "", "file `path` to load transaction to be built")
+	buildCmd.Flags().Bool("noguide", false, "disable guide to build transaction")
 
-			tByte, err := cli.tran.MarshalJSON(true)
-			if err != nil {
-				fmt.Println(err)
-				return
-			}
-			fmt.Println("Transaction details are as follows:")
-			fmt.Println(string(tByte))
+	buildCmd.AddCommand(cli.buildBuildSubmitCmd())
+	buildCmd.AddCommand(cli.buildBuildIDCmd("confirm", "Build transaction to confirm transaction ID", IntentConfirm))
+	buildCmd.AddCommand(cli.buildBuildIDCmd("revoke", "Build transaction to revoke a confirmation for transaction ID", IntentRevoke))
+	buildCmd.AddCommand(cli.buildBuildIDCmd("execute", "Build transaction to execute a confirmed transaction ID", IntentExecute))
+	buildCmd.AddCommand(cli.buildBuildOwnerCmd())
+	buildCmd.AddCommand(cli.buildBuildDailyLimitCmd())
+	buildCmd.AddCommand(cli.buildBuildRequiredCmd())
+	buildCmd.AddCommand(cli.buildBuildTokenTransferCmd())
 
-			var outStr string
-			defaultOutStr := time.Now().Format("20060102150405") + ".tx" // bitcoin 2009-01-03 18:15:05
-			if cmd.Flags().Changed("out") {
-				outStr, err = cmd.Flags().GetString("out")
-			} else {
-				prompt := fmt.Sprintf("Enter file to save transaction (default: %s): ", defaultOutStr)
-				outStr, err = console.Stdin.PromptInput(prompt)
+	return buildCmd
+}
+
+func (cli *CLI) buildBuildSubmitCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:                   fmt.Sprintf("submit <--to target> <--amount amount> [--unit %s] [--data text | --abi file --method name [--args arg]...]", strings.Join(UnitList, ",")),
+		Short:                 "Build transaction to submit a transaction, pay amount in unit to target address",
+		Args:                  cobra.NoArgs,
+		DisableFlagsInUseLine: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			toStr, _ := cmd.Flags().GetString("to")
+			if !common.IsHexAddress(toStr) {
+				fmt.Fprint(os.Stderr, cmd.UsageString())
+				return newErrorf(ErrCodeInvalidArgument, "Error: %v: %s", errToAddressIllegal, toStr)
+			}
+			unit, _ := cmd.Flags().GetString("unit")
+			if !stringInSlice(unit, UnitList) {
+				fmt.Fprint(os.Stderr, cmd.UsageString())
+				return newErrorf(ErrCodeInvalidArgument, "Unit(%s) for amount error. Available unit: %s.", unit, strings.Join(UnitList, ","))
 			}
+			amountStr, _ := cmd.Flags().GetString("amount")
+			value, err := getAmountWei(amountStr, unit)
 			if err != nil {
-				fmt.Println("Error:", err)
+				fmt.Fprint(os.Stderr, cmd.UsageString())
+				return newErrorf(ErrCodeInvalidArgument, "Get amount error: %v", err)
 			}
-			if outStr == "" {
-				outStr = defaultOutStr
+			if value.Cmp(maxValue) > 0 {
+				return newError(ErrCodeInvalidArgument, errValueExceeds)
 			}
-			if err := cli.saveTranToFile(outStr); err != nil {
-				fmt.Println(err)
-			} else {
-				fmt.Println("Successfully save transaction to file", outStr)
+			call, err := loadCallFlags(cmd, value)
+			if err != nil {
+				fmt.Fprint(os.Stderr, cmd.UsageString())
+				return err
 			}
+			dataStr, _ := cmd.Flags().GetString("data")
 
-			// if sign, _ := cmd.Flags().GetBool("sign"); !sign && !offline {
-			// 	return
-			// }
-
-			// if inStr == "" {
-			// 	inStr = "tx"
-			// }
-			// cli.signTxAndSave(inStr + ".sign")
+			return cli.buildTxWithFlags(cmd, func(t *Transaction) error {
+				if call != nil {
+					t.setCall(common.HexToAddress(toStr), value, unit, call)
+					return nil
+				}
+				t.setSubmit(common.HexToAddress(toStr), value, unit, []byte(dataStr))
+				return nil
+			})
 		},
 	}
 
-	signTxCmd.Flags().String("out", "", "file `path` to save built transaction")
-	signTxCmd.Flags().String("in", "", "file `path` to load transaction to be built")
-	signTxCmd.Flags().Bool("noguide", false, "disable guide to build transaction")
-	// signTxCmd.Flags().Bool("sign", false, "sign transaction after build")
-	signTxCmd.Flags().Bool("offline", false, "build offline transaction")
+	cmd.Flags().StringP("to", "t", "", "target account `address`")
+	cmd.Flags().String("amount", "", "the `amount` to pay")
+	cmd.Flags().StringP("unit", "u", UnitETH, fmt.Sprintf("unit for pay amount. %s.", fmt.Sprintf("Available unit: %s", strings.Join(UnitList, ","))))
+	cmd.Flags().String("data", "", "custom data message (use quotes if there are spaces)")
+	addCallFlags(cmd.Flags())
+	cmd.MarkFlagRequired("to")
+	cmd.MarkFlagRequired("amount")
 
-	return signTxCmd
+	return cmd
 }
 
-var (
-	errNoSignTransactor = errors.New("no sign transactor")
-)
-
-func (cli *CLI) getNoSignTransactOpts() (*bind.TransactOpts, error) {
+func (cli *CLI) buildBuildIDCmd(use, short, action string) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:                   use + " <transactionId>",
+		Short:                 short,
+		Args:                  cobra.ExactArgs(1),
+		DisableFlagsInUseLine: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			id, ok := new(big.Int).SetString(args[0], 10)
+			if !ok || id.Sign() < 0 {
+				fmt.Fprint(os.Stderr, cmd.UsageString())
+				return newErrorf(ErrCodeInvalidArgument, "Error: transaction ID(%s) invalid", args[0])
+			}
 
-	if cli.tran == nil {
-		return nil, errCliTranNil
+			return cli.buildTxWithFlags(cmd, func(t *Transaction) error {
+				t.setID(action, id)
+				return nil
+			})
+		},
 	}
 
-	if err := cli.BuildClient(); err != nil {
-		fmt.Println("NetworkID Error: ", err)
-		return nil, err
-	}
-	networkID, err := cli.client.NetworkID(context.Background())
-	if err != nil {
-		fmt.Println("NetworkID Error: ", err)
-		return nil, err
+	return cmd
+}
+
+func (cli *CLI) buildBuildOwnerCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "owner [add|remove|replace]",
+		Short: "Build transaction to manage contract owners",
+		Args:  cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			fmt.Fprint(os.Stderr, cmd.UsageString())
+			return newErrorf(ErrCodeInvalidArgument, "Error: unknown command %q for %q", args[0], cmd.CommandPath())
+		},
 	}
 
-	keyAddr := cli.tran.From
-	opts := &bind.TransactOpts{
-		From: keyAddr,
-		Signer: func(signer types.Signer, address common.Address, tx *types.Transaction) (*types.Transaction, error) {
-			if address != keyAddr {
-				return nil, errors.New("not authorized to sign this account")
+	cmd.AddCommand(cli.buildBuildOwnerAddressCmd("add", "Build transaction to add a new owner", IntentOwnerAdd))
+	cmd.AddCommand(cli.buildBuildOwnerAddressCmd("remove", "Build transaction to remove an owner", IntentOwnerRemove))
+	cmd.AddCommand(&cobra.Command{
+		Use:                   "replace <owner> <newOwner>",
+		Short:                 "Build transaction to replace an owner with a new owner",
+		Args:                  cobra.ExactArgs(2),
+		DisableFlagsInUseLine: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if !common.IsHexAddress(args[0]) || !common.IsHexAddress(args[1]) {
+				fmt.Fprint(os.Stderr, cmd.UsageString())
+				return newErrorf(ErrCodeInvalidArgument, "Error: owner address invalid")
 			}
 
-			cli.copyTx(tx)
-			cli.tran.From = address
-			cli.tran.NetworkID = networkID
+			return cli.buildTxWithFlags(cmd, func(t *Transaction) error {
+				t.setOwnerReplace(common.HexToAddress(args[0]), common.HexToAddress(args[1]))
+				return nil
+			})
+		},
+	})
 
-			return nil, errNoSignTransactor
+	return cmd
+}
+
+func (cli *CLI) buildBuildOwnerAddressCmd(use, short, action string) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:                   use + " <owner>",
+		Short:                 short,
+		Args:                  cobra.ExactArgs(1),
+		DisableFlagsInUseLine: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if !common.IsHexAddress(args[0]) {
+				fmt.Fprint(os.Stderr, cmd.UsageString())
+				return newErrorf(ErrCodeInvalidArgument, "Error: owner address(%s) invalid", args[0])
+			}
+
+			return cli.buildTxWithFlags(cmd, func(t *Transaction) error {
+				t.setOwner(action, common.HexToAddress(args[0]))
+				return nil
+			})
 		},
 	}
 
-	return opts, err
+	return cmd
 }
 
-func (cli *CLI) copyTx(tx *types.Transaction) {
-	if cli.tran == nil {
-		cli.tran = new(Transaction)
+func (cli *CLI) buildBuildDailyLimitCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:                   fmt.Sprintf("dailylimit <number> [-u %s]", strings.Join(UnitList, ",")),
+		Short:                 "Build transaction to change the daily limit",
+		Args:                  cobra.ExactArgs(1),
+		DisableFlagsInUseLine: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			unit, _ := cmd.Flags().GetString("unit")
+			if !stringInSlice(unit, UnitList) {
+				fmt.Fprint(os.Stderr, cmd.UsageString())
+				return newErrorf(ErrCodeInvalidArgument, "Unit(%s) for amount error. Available unit: %s.", unit, strings.Join(UnitList, ","))
+			}
+			value, err := getAmountWei(args[0], unit)
+			if err != nil {
+				fmt.Fprint(os.Stderr, cmd.UsageString())
+				return newErrorf(ErrCodeInvalidArgument, "Get amount error: %v", err)
+			}
+
+			return cli.buildTxWithFlags(cmd, func(t *Transaction) error {
+				t.setDailyLimit(value, unit)
+				return nil
+			})
+		},
 	}
-	if tx.To() != nil {
-		cli.tran.To = *tx.To()
+
+	cmd.Flags().StringP("unit", "u", UnitETH, fmt.Sprintf("unit for daily limit. %s.", fmt.Sprintf("Available unit: %s", strings.Join(UnitList, ","))))
+
+	return cmd
+}
+
+func (cli *CLI) buildBuildRequiredCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:                   "required <number>",
+		Short:                 "Build transaction to change the number of required confirmations",
+		Args:                  cobra.ExactArgs(1),
+		DisableFlagsInUseLine: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			required, ok := new(big.Int).SetString(args[0], 10)
+			if !ok || required.Sign() <= 0 {
+				fmt.Fprint(os.Stderr, cmd.UsageString())
+				return newErrorf(ErrCodeInvalidArgument, "Error: required number(%s) invalid", args[0])
+			}
+
+			return cli.buildTxWithFlags(cmd, func(t *Transaction) error {
+				t.setRequired(required)
+				return nil
+			})
+		},
+	}
+
+	return cmd
+}
+
+func (cli *CLI) buildBuildTokenTransferCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:                   "token-transfer <--token token> <--to recipient> <--amount amount> [--decimals decimals]",
+		Short:                 "Build transaction to transfer token from the contract wallet",
+		Args:                  cobra.NoArgs,
+		DisableFlagsInUseLine: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			tokenStr, _ := cmd.Flags().GetString("token")
+			toStr, _ := cmd.Flags().GetString("to")
+			if !common.IsHexAddress(tokenStr) || !common.IsHexAddress(toStr) {
+				fmt.Fprint(os.Stderr, cmd.UsageString())
+				return newErrorf(ErrCodeInvalidArgument, "Error: token or recipient address invalid")
+			}
+			amountStr, _ := cmd.Flags().GetString("amount")
+			token, recipient := common.HexToAddress(tokenStr), common.HexToAddress(toStr)
+
+			// the decimals can not be read from node offline
+			if offline, _ := cmd.Flags().GetBool("offline"); offline && !cmd.Flags().Changed("decimals") {
+				fmt.Fprint(os.Stderr, cmd.UsageString())
+				return newErrorf(ErrCodeInvalidArgument, "Error: flag decimals is required by offline token-transfer")
+			}
+
+			return cli.buildTxWithFlags(cmd, func(t *Transaction) error {
+				var decimals uint8
+				if cmd.Flags().Changed("decimals") {
+					decimals, _ = cmd.Flags().GetUint8("decimals")
+				} else {
+					var err error
+					if decimals, err = cli.getTokenDecimals(token); err != nil {
+						return newErrorf(ErrCodeRPC, "Get decimals of token error: %v", err)
+					}
+				}
+				amount, err := GetAmountISAACFromTextWithDecimals(amountStr, decimals)
+				if err != nil {
+					return newErrorf(ErrCodeInvalidArgument, "Get amount error: %v", err)
+				}
+				t.setTokenTransfer(token, recipient, amount, decimals)
+				return nil
+			})
+		},
 	}
-	cli.tran.Value = tx.Value()
-	cli.tran.Unit = UnitWEI
-	cli.tran.Data = tx.Data()
-	cli.tran.Nonce = tx.Nonce()
-	cli.tran.GasPrice = tx.GasPrice()
-	cli.tran.GasLimit = tx.Gas()
-	cli.tran.NetworkID = tx.ChainId()
+
+	cmd.Flags().String("token", "", "the `address` of token")
+	cmd.Flags().StringP("to", "t", "", "the `address` of recipient")
+	cmd.Flags().String("amount", "", "the `amount` of token to transfer")
+	cmd.Flags().Uint8("decimals", 0, "the `decimals` of token, read from node if not set, required by offline")
+	cmd.MarkFlagRequired("token")
+	cmd.MarkFlagRequired("to")
+	cmd.MarkFlagRequired("amount")
+
+	return cmd
 }
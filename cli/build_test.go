@@ -1,9 +1,114 @@
 package cli
 
-import "testing"
+import (
+	"context"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind/backends"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/newtonproject/MultiSignatureWallet/msw"
+)
 
 func TestBuild(t *testing.T) {
 	cli := NewCLI()
 
 	cli.TestCommand("build")
 }
+
+func TestBuildOffline(t *testing.T) {
+	dir, err := ioutil.TempDir("", "build")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	out := filepath.Join(dir, "confirm.tx")
+
+	cli := NewCLI()
+	cli.TestCommand("build confirm 3 --offline --nonce 7 --gasLimit 100000 --chainID 1007 -w " + dir + " " +
+		"-a 0xf09E6759c2588eE8435902d16350E321CBD27af3 -f 0x9B3deA9C636BA262f870f98a1c64d444BF0f6544 --out " + out)
+
+	tran := new(Transaction)
+	b, err := ioutil.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := tran.UnmarshalJSON(b); err != nil {
+		t.Fatal(err)
+	}
+	if tran.Nonce != 7 || tran.GasLimit != 100000 || tran.NetworkID.Int64() != 1007 {
+		t.Fatalf("got transaction %+v", tran)
+	}
+
+	want, _ := (&Transaction{Intent: &Intent{Action: IntentConfirm, TxID: big.NewInt(3)}}).packData()
+	if string(tran.Data) != string(want) {
+		t.Fatalf("got data %x, want %x", tran.Data, want)
+	}
+}
+
+func TestBuildNothing(t *testing.T) {
+	cli := NewCLI()
+	cli.rootCmd.SetArgs([]string{"build", "--noguide", "--offline", "--nonce", "1", "--gasLimit", "100000",
+		"-a", "0xf09E6759c2588eE8435902d16350E321CBD27af3", "-f", "0x9B3deA9C636BA262f870f98a1c64d444BF0f6544"})
+	if err := cli.rootCmd.Execute(); errorCode(err) != ErrCodeInvalidArgument {
+		t.Fatalf("build without intent or data got error %v", err)
+	}
+}
+
+func TestBuildOwnerUnknown(t *testing.T) {
+	cli := NewCLI()
+	cli.rootCmd.SetArgs([]string{"build", "owner", "ad", "0x9B3deA9C636BA262f870f98a1c64d444BF0f6544"})
+	if err := cli.rootCmd.Execute(); errorCode(err) != ErrCodeInvalidArgument {
+		t.Fatalf("build owner with unknown command got error %v", err)
+	}
+}
+
+func TestBuildTokenTransferOffline(t *testing.T) {
+	cli := NewCLI()
+	cli.rootCmd.SetArgs([]string{"build", "token-transfer", "--offline", "--nonce", "1", "--gasLimit", "100000",
+		"-i", "http://127.0.0.1:1", "-a", "0xf09E6759c2588eE8435902d16350E321CBD27af3", "-f", "0x9B3deA9C636BA262f870f98a1c64d444BF0f6544",
+		"--token", "0x7c1d845a0CC7E24352A59FEF437eB27b504769DE", "--to", "0x9B3deA9C636BA262f870f98a1c64d444BF0f6544", "--amount", "1"})
+	if err := cli.rootCmd.Execute(); errorCode(err) != ErrCodeInvalidArgument {
+		t.Fatalf("offline token-transfer without decimals got error %v", err)
+	}
+}
+
+func TestCheckWalletCall(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	owner := bind.NewKeyedTransactor(key)
+	other := common.HexToAddress("0x9B3deA9C636BA262f870f98a1c64d444BF0f6544")
+	backend := backends.NewSimulatedBackend(core.GenesisAlloc{
+		owner.From: {Balance: new(big.Int).Exp(big.NewInt(10), big.NewInt(20), nil)},
+	}, 8000000)
+	address, _, _, err := msw.DeployMultiSigWalletWithDailyLimit(owner, backend, []common.Address{owner.From}, big.NewInt(1), new(big.Int))
+	if err != nil {
+		t.Fatal(err)
+	}
+	backend.Commit()
+	w, err := msw.NewWallet(address, backend, big.NewInt(1337))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	confirm, _ := (&Transaction{To: address, Intent: &Intent{Action: IntentConfirm, TxID: big.NewInt(3)}}).packData()
+	if err := checkWalletCall(ctx, w, owner.From, confirm); errorCode(walletError(err)) != ErrCodeTxNotFound {
+		t.Fatalf("check confirm of unknown ID got %v", err)
+	}
+	addOwner, _ := (&Transaction{To: address, Intent: &Intent{Action: IntentOwnerAdd, Owner: &other}}).packData()
+	if err := checkWalletCall(ctx, w, other, addOwner); errorCode(walletError(err)) != ErrCodeNotOwner {
+		t.Fatalf("check submit by not owner got %v", err)
+	}
+	if err := checkWalletCall(ctx, w, owner.From, addOwner); err != nil {
+		t.Fatalf("check submit by owner got %v", err)
+	}
+}
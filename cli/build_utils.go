@@ -1,6 +1,7 @@
 package cli
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -8,10 +9,12 @@ import (
 	"math/big"
 	"os"
 	"strconv"
+	"time"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/console"
+	"github.com/spf13/cobra"
 )
 
 // method or action
@@ -41,12 +44,10 @@ type Transaction struct {
 	GasLimit  uint64         `json:"gas"`
 	NetworkID *big.Int       `json:"networkID"`
 	Password  string         `json:"password,omitempty"`
-
-	action int
-	params []interface{}
+	Intent    *Intent        `json:"intent,omitempty"`
 }
 
-func (t Transaction) MarshalJSON(indent bool) ([]byte, error) {
+func (t Transaction) MarshalJSON() ([]byte, error) {
 	type transaction struct {
 		From      common.Address  `json:"from"`
 		To        *common.Address `json:"to"`
@@ -58,6 +59,7 @@ func (t Transaction) MarshalJSON(indent bool) ([]byte, error) {
 		GasLimit  uint64          `json:"gas"`
 		NetworkID *big.Int        `json:"networkID"`
 		// Password  string          `json:"password,omitempty"`
+		Intent *Intent `json:"intent,omitempty"`
 	}
 	var tran transaction
 	tran.From = t.From
@@ -70,14 +72,16 @@ func (t Transaction) MarshalJSON(indent bool) ([]byte, error) {
 	tran.GasLimit = t.GasLimit
 	tran.NetworkID = t.NetworkID
 	// tran.Password = t.Password
-
-	if indent {
-		return json.MarshalIndent(tran, "", " ")
-	}
+	tran.Intent = t.Intent
 
 	return json.Marshal(&tran)
 }
 
+// marshalIndent returns the indented JSON of t
+func (t *Transaction) marshalIndent() ([]byte, error) {
+	return json.MarshalIndent(t, "", " ")
+}
+
 func (t *Transaction) UnmarshalJSON(input []byte) error {
 	type transaction struct {
 		From      common.Address  `json:"from"`
@@ -90,6 +94,7 @@ func (t *Transaction) UnmarshalJSON(input []byte) error {
 		GasLimit  uint64          `json:"gas"`
 		NetworkID *big.Int        `json:"networkID"`
 		Password  string          `json:"password,omitempty"`
+		Intent    *Intent         `json:"intent,omitempty"`
 	}
 	var tran transaction
 	if err := json.Unmarshal(input, &tran); err != nil {
@@ -122,10 +127,83 @@ func (t *Transaction) UnmarshalJSON(input []byte) error {
 	if tran.Password != "" {
 		t.Password = tran.Password
 	}
+	t.Intent = tran.Intent
 
 	return nil
 }
 
+// packData returns the data encoded from the intent, and records the wallet
+// it is sent to in the intent, the data loaded from file is kept if no
+// intent is built
+func (t *Transaction) packData() ([]byte, error) {
+	if t.Intent == nil {
+		return t.Data, nil
+	}
+	wallet := t.To
+	t.Intent.Wallet = &wallet
+	return t.Intent.pack(wallet)
+}
+
+// setSubmit sets the intent to submit a transaction paying value in unit
+// to destination with data
+func (t *Transaction) setSubmit(destination common.Address, value *big.Int, unit string, data []byte) {
+	t.Intent = &Intent{
+		Action:      IntentSubmit,
+		Destination: &destination,
+		Amount:      getWeiAmountTextByUnit(value, unit),
+		Unit:        unit,
+		Data:        data,
+	}
+}
+
+// setID sets the intent to confirm, revoke or execute the transaction id
+func (t *Transaction) setID(action string, id *big.Int) {
+	t.Intent = &Intent{Action: action, TxID: id}
+}
+
+// setOwner sets the intent to add or remove the owner
+func (t *Transaction) setOwner(action string, owner common.Address) {
+	t.Intent = &Intent{Action: action, Owner: &owner}
+}
+
+// setOwnerReplace sets the intent to replace the owner with newOwner
+func (t *Transaction) setOwnerReplace(owner, newOwner common.Address) {
+	t.Intent = &Intent{Action: IntentOwnerReplace, Owner: &owner, NewOwner: &newOwner}
+}
+
+// setDailyLimit sets the intent to change the daily limit to value in unit
+func (t *Transaction) setDailyLimit(value *big.Int, unit string) {
+	t.Intent = &Intent{Action: IntentDailyLimit, Amount: getWeiAmountTextByUnit(value, unit), Unit: unit}
+}
+
+// setRequired sets the intent to change the number of required
+func (t *Transaction) setRequired(required *big.Int) {
+	t.Intent = &Intent{Action: IntentRequired, Required: required}
+}
+
+// setCall sets the intent to submit a transaction calling contract
+// destination with call and paying value in unit
+func (t *Transaction) setCall(destination common.Address, value *big.Int, unit string, call *ContractCall) {
+	t.Intent = &Intent{
+		Action:      IntentCall,
+		Destination: &destination,
+		Amount:      getWeiAmountTextByUnit(value, unit),
+		Unit:        unit,
+		Call:        call,
+	}
+}
+
+// setTokenTransfer sets the intent to transfer amount of token to recipient
+func (t *Transaction) setTokenTransfer(token, recipient common.Address, amount *big.Int, decimals uint8) {
+	t.Intent = &Intent{
+		Action:      IntentTokenTransfer,
+		Token:       &token,
+		Destination: &recipient,
+		Amount:      getAmountTextByWeiWithDecimals(amount, decimals),
+		Decimals:    &decimals,
+	}
+}
+
 func (cli *CLI) applyTranDefault() error {
 	if common.IsHexAddress(cli.contractAddress) {
 		cli.tran.To = common.HexToAddress(cli.contractAddress)
@@ -138,6 +216,9 @@ func (cli *CLI) applyTranDefault() error {
 	cli.tran.Value = new(big.Int)
 	cli.tran.Unit = UnitETH
 	cli.tran.GasPrice = big.NewInt(1)
+	if price := cli.gasPolicy().Price; price != nil {
+		cli.tran.GasPrice = new(big.Int).Set(price)
+	}
 	cli.tran.NetworkID = big.NewInt(16888)
 
 	return nil
@@ -154,6 +235,190 @@ func (cli *CLI) applyTxFile(path string) error {
 	return cli.tran.UnmarshalJSON(f)
 }
 
+// applyTxFlags applies the nonce, gasPrice, gasLimit and chainID flags
+// of build to the transaction
+func (cli *CLI) applyTxFlags(cmd *cobra.Command) error {
+	if cli.tran == nil {
+		return errCliTranNil
+	}
+
+	if cmd.Flags().Changed("nonce") {
+		cli.tran.Nonce, _ = cmd.Flags().GetUint64("nonce")
+	}
+	if cmd.Flags().Changed("gasPrice") {
+		gasPriceStr, _ := cmd.Flags().GetString("gasPrice")
+		gasPrice, ok := new(big.Int).SetString(gasPriceStr, 10)
+		if !ok || gasPrice.Sign() < 0 {
+			return errors.New("gasPrice invalid")
+		}
+		cli.tran.GasPrice = gasPrice
+	}
+	if cmd.Flags().Changed("gasLimit") {
+		cli.tran.GasLimit, _ = cmd.Flags().GetUint64("gasLimit")
+	}
+	if cmd.Flags().Changed("chainID") {
+		chainIDStr, _ := cmd.Flags().GetString("chainID")
+		chainID, ok := new(big.Int).SetString(chainIDStr, 10)
+		if !ok || chainID.Sign() <= 0 {
+			return errors.New("chainID invalid")
+		}
+		cli.tran.NetworkID = chainID
+	}
+
+	return nil
+}
+
+// buildTxWithFlags builds the transaction of the build subcommand without
+// prompt, apply sets the action of the transaction
+func (cli *CLI) buildTxWithFlags(cmd *cobra.Command, apply func(t *Transaction) error) error {
+	cli.tran = new(Transaction)
+	cli.applyTranDefault()
+	state, err := loadStateFlag(cmd)
+	if err != nil {
+		return err
+	}
+	if state != nil {
+		state.apply(cli.tran)
+		cli.tran.GasPrice, _ = cli.gasPolicy().gasPrice(cli.tran.GasPrice)
+	}
+	if err := cli.applyTxFlags(cmd); err != nil {
+		fmt.Fprint(os.Stderr, cmd.UsageString())
+		return newError(ErrCodeInvalidArgument, err)
+	}
+
+	if cli.tran.To == (common.Address{}) {
+		fmt.Fprint(os.Stderr, cmd.UsageString())
+		return newErrorf(ErrCodeInvalidArgument, "Error: %v", errRequiredContractAddress)
+	}
+	if cli.tran.From == (common.Address{}) {
+		fmt.Fprint(os.Stderr, cmd.UsageString())
+		return newErrorf(ErrCodeInvalidArgument, "Error: not set from address of owner")
+	}
+
+	offline, _ := cmd.Flags().GetBool("offline")
+	var ledger bool
+	if offline {
+		if ledger, err = cli.applyLedgerNonce(cmd); err != nil {
+			return newError(ErrCodeFile, err)
+		}
+	}
+
+	if err := apply(cli.tran); err != nil {
+		return newError(ErrCodeInvalidArgument, err)
+	}
+
+	fixedLimit, fixed := cli.gasPolicy().fixedLimit(gasAction(cli.tran.Intent))
+	if offline && !((cmd.Flags().Changed("nonce") || state != nil || ledger) && (cmd.Flags().Changed("gasLimit") || fixed)) {
+		fmt.Fprint(os.Stderr, cmd.UsageString())
+		return newErrorf(ErrCodeInvalidArgument, "Error: flags nonce (or state, nonce ledger) and gasLimit (or fixed gas limit) are required by offline transaction")
+	}
+	if offline && fixed && !cmd.Flags().Changed("gasLimit") {
+		cli.tran.GasLimit = fixedLimit
+	}
+
+	return cli.buildAndSaveTx(cmd, offline, false, state)
+}
+
+// buildAndSaveTx fills the transaction from node if not offline, or checks
+// it against the state if set, and saves it to the out file, the file is
+// prompted if guide and out not set
+func (cli *CLI) buildAndSaveTx(cmd *cobra.Command, offline, guide bool, state *State) error {
+	data, err := cli.tran.packData()
+	if err != nil {
+		return newError(ErrCodeInvalidArgument, err)
+	}
+	cli.tran.Data = data
+
+	if offline && state != nil {
+		if err := state.check(cli.tran); err != nil {
+			return newErrorf(errorCode(err), "Error: check with state: %w", err)
+		}
+	}
+
+	// update nonce, gasPrice, gasLimit, networkID from node
+	if !offline {
+		w, err := cli.GetWallet()
+		if err != nil {
+			return newErrorf(ErrCodeRPC, "Error: GetWallet Error: %v", err)
+		}
+		// refuse to build the call certain to revert, as sending it does
+		if err := checkWalletCall(context.Background(), w, cli.tran.From, data); err != nil {
+			force, _ := cmd.Flags().GetBool("force")
+			if !force {
+				return newErrorf(errorCode(walletError(err)), "Error: %v, use --force to build anyway", err)
+			}
+			fmt.Printf("Warning: %v, build anyway with --force\n", err)
+		}
+		t, err := w.BuildOffline(context.Background(), cli.tran.From, data)
+		if err != nil {
+			return walletError(err)
+		}
+		cli.tran.To = t.To
+		cli.tran.Value = t.Value
+		cli.tran.Unit = UnitWEI
+		cli.tran.Nonce = t.Nonce
+		cli.tran.NetworkID = t.ChainID
+
+		// the gas flags override the gas policy
+		p := cli.gasPolicy()
+		if !cmd.Flags().Changed("gasPrice") {
+			var how string
+			cli.tran.GasPrice, how = p.gasPrice(t.GasPrice)
+			fmt.Printf("Gas policy: gasPrice %s WEI (%s)\n", cli.tran.GasPrice.String(), how)
+		}
+		if !cmd.Flags().Changed("gasLimit") {
+			var how string
+			cli.tran.GasLimit, how = p.gasLimit(gasAction(cli.tran.Intent), t.GasLimit, cli.blockGasLimit(context.Background()))
+			fmt.Printf("Gas policy: gasLimit %d (%s)\n", cli.tran.GasLimit, how)
+		}
+
+		// embed the proposal to review on the offline computer
+		if cli.tran.Intent != nil && cli.tran.Intent.hasTxID() {
+			status, err := w.Status(context.Background(), cli.tran.Intent.TxID)
+			if err != nil {
+				return walletError(err)
+			}
+			cli.tran.Intent.Proposal = newProposal(status)
+		}
+	}
+
+	tByte, err := cli.tran.marshalIndent()
+	if err != nil {
+		return err
+	}
+	fmt.Println("Transaction details are as follows:")
+	fmt.Println(string(tByte))
+	printMaxFee(cli.tran.GasLimit, cli.tran.GasPrice, cli.tran.Value)
+
+	var outStr string
+	defaultOutStr := time.Now().Format("20060102150405") + ".tx" // bitcoin 2009-01-03 18:15:05
+	if cmd.Flags().Changed("out") {
+		outStr, err = cmd.Flags().GetString("out")
+	} else if guide {
+		prompt := fmt.Sprintf("Enter file to save transaction (default: %s): ", defaultOutStr)
+		outStr, err = console.Stdin.PromptInput(prompt)
+	}
+	if err != nil {
+		return promptError(err)
+	}
+	if outStr == "" {
+		outStr = defaultOutStr
+	}
+	if err := cli.saveTranToFile(outStr); err != nil {
+		return newError(ErrCodeFile, err)
+	}
+	fmt.Println("Successfully save transaction to file", outStr)
+
+	// hand out the nonce, the next offline build of from uses the next one
+	if offline {
+		if err := cli.useNonce(cli.tran.From, cli.tran.Nonce); err != nil {
+			fmt.Println("Warning: record nonce in ledger error:", err)
+		}
+	}
+
+	return cli.exportQR(cmd, outStr)
+}
+
 func (cli *CLI) applyTxGuide(offline bool) error {
 	var prompt string
 
@@ -308,17 +573,17 @@ func (cli *CLI) applyTxGuide(offline bool) error {
 		}
 	case Confirm:
 		// Confirm - Confirm transaction ID
-		if err := cli.applyTxGuideID("Enter the ID to confirm", Confirm); err != nil {
+		if err := cli.applyTxGuideID("Enter the ID to confirm", IntentConfirm); err != nil {
 			return err
 		}
 	case Revoke:
 		// Revoke - Revoke a confirmation for a transaction
-		if err := cli.applyTxGuideID("Enter the ID to revoke", Revoke); err != nil {
+		if err := cli.applyTxGuideID("Enter the ID to revoke", IntentRevoke); err != nil {
 			return err
 		}
 	case Execute:
 		// Execute - Execute a confirmed transaction
-		if err := cli.applyTxGuideID("Enter the ID to execute", Execute); err != nil {
+		if err := cli.applyTxGuideID("Enter the ID to execute", IntentExecute); err != nil {
 			return err
 		}
 	case OwnerAdd:
@@ -412,6 +677,9 @@ func (cli *CLI) applyTxGuideNode() error {
 	// get GasLimit
 	if cli.tran.GasLimit < 21000 {
 		cli.tran.GasLimit = 21000
+		if limit, ok := cli.gasPolicy().fixedLimit(gasAction(cli.tran.Intent)); ok {
+			cli.tran.GasLimit = limit
+		}
 	}
 	prompt = fmt.Sprintf("Enter gasLimit (default: %d): ", cli.tran.GasLimit)
 	gasLimitStr, err := console.Stdin.PromptInput(prompt)
@@ -419,7 +687,7 @@ func (cli *CLI) applyTxGuideNode() error {
 		return fmt.Errorf("get gasLimit error")
 	}
 	if gasLimitStr != "" {
-		gasLimit, err := strconv.ParseUint(nonceStr, 10, 64)
+		gasLimit, err := strconv.ParseUint(gasLimitStr, 10, 64)
 		if err != nil {
 			return fmt.Errorf("conver gasLimit error")
 		}
@@ -447,7 +715,7 @@ func (cli *CLI) applyTxGuideNode() error {
 }
 
 func (cli *CLI) saveTranToFile(filepath string) error {
-	tByte, err := cli.tran.MarshalJSON(true)
+	tByte, err := cli.tran.marshalIndent()
 	if err != nil {
 		return err
 	}
@@ -605,15 +873,12 @@ func (cli *CLI) applyTxGuideSubmit() error {
 		}
 	}
 
-	cli.tran.action = Submit
-	cli.tran.params = append(cli.tran.params, to)
-	cli.tran.params = append(cli.tran.params, value)
-	cli.tran.params = append(cli.tran.params, data)
+	cli.tran.setSubmit(to, value, unit, data)
 
 	return nil
 }
 
-func (cli *CLI) applyTxGuideID(prompt string, action int) error {
+func (cli *CLI) applyTxGuideID(prompt string, action string) error {
 	if cli.tran == nil {
 		return errCliTranNil
 	}
@@ -632,21 +897,20 @@ func (cli *CLI) applyTxGuideID(prompt string, action int) error {
 		return errors.New("convert string to number error")
 	}
 
-	cli.tran.action = action
-	cli.tran.params = append(cli.tran.params, id)
+	cli.tran.setID(action, id)
 
 	return nil
 }
 
 func (cli *CLI) applyTxGuideOwnerAdd() error {
-	return cli.applyTxGuideAddress("Enter the address to add: ", "addOwner")
+	return cli.applyTxGuideAddress("Enter the address to add: ", IntentOwnerAdd)
 }
 
 func (cli *CLI) applyTxGuideOwnerRemove() error {
-	return cli.applyTxGuideAddress("Enter the address to remove: ", "removeOwner")
+	return cli.applyTxGuideAddress("Enter the address to remove: ", IntentOwnerRemove)
 }
 
-func (cli *CLI) applyTxGuideAddress(prompt, method string) error {
+func (cli *CLI) applyTxGuideAddress(prompt, action string) error {
 
 	if cli.tran == nil {
 		return errCliTranNil
@@ -657,15 +921,7 @@ func (cli *CLI) applyTxGuideAddress(prompt, method string) error {
 		return err
 	}
 
-	data, err := cli.GetMethodData(method, address)
-	if err != nil {
-		return err
-	}
-
-	cli.tran.action = Submit
-	cli.tran.params = append(cli.tran.params, cli.tran.To)
-	cli.tran.params = append(cli.tran.params, big.NewInt(0))
-	cli.tran.params = append(cli.tran.params, data)
+	cli.tran.setOwner(action, address)
 
 	return nil
 }
@@ -701,15 +957,7 @@ func (cli *CLI) applyTxGuideOwnerReplace() error {
 		return err
 	}
 
-	data, err := cli.GetMethodData("replaceOwner", address, newAddress)
-	if err != nil {
-		return err
-	}
-
-	cli.tran.action = Submit
-	cli.tran.params = append(cli.tran.params, cli.tran.To)
-	cli.tran.params = append(cli.tran.params, big.NewInt(0))
-	cli.tran.params = append(cli.tran.params, data)
+	cli.tran.setOwnerReplace(address, newAddress)
 
 	return nil
 }
@@ -733,15 +981,7 @@ func (cli *CLI) applyTxGuideRequired() error {
 		return errors.New("convert string to number error")
 	}
 
-	data, err := cli.GetMethodData("changeRequirement", id)
-	if err != nil {
-		return err
-	}
-
-	cli.tran.action = Submit
-	cli.tran.params = append(cli.tran.params, cli.tran.To)
-	cli.tran.params = append(cli.tran.params, big.NewInt(0))
-	cli.tran.params = append(cli.tran.params, data)
+	cli.tran.setRequired(id)
 
 	return nil
 }
@@ -792,15 +1032,7 @@ func (cli *CLI) applyTxGuideDailyLimit() error {
 		}
 	}
 
-	data, err := cli.GetMethodData("changeDailyLimit", value)
-	if err != nil {
-		return err
-	}
-
-	cli.tran.action = Submit
-	cli.tran.params = append(cli.tran.params, cli.tran.To)
-	cli.tran.params = append(cli.tran.params, big.NewInt(0))
-	cli.tran.params = append(cli.tran.params, data)
+	cli.tran.setDailyLimit(value, unit)
 
 	return nil
 }
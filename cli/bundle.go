@@ -0,0 +1,59 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+func (cli *CLI) buildBundleCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:                   "bundle <txfile>... [--out outfile] [--nonce nonce]",
+		Short:                 "Bundle the built transactions files to sign and broadcast at once",
+		Long:                  "Bundle the built transactions files in order, the nonces are renumbered consecutively from the first transaction or the nonce flag",
+		Args:                  cobra.MinimumNArgs(1),
+		DisableFlagsInUseLine: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			bundle := new(Bundle)
+			for _, path := range args {
+				cli.tran = new(Transaction)
+				cli.applyTranDefault()
+				if err := cli.applyTxFile(path); err != nil {
+					return newErrorf(ErrCodeFile, "Error apply infile(%s): %v", path, err)
+				}
+				bundle.Transactions = append(bundle.Transactions, cli.tran)
+			}
+
+			nonce := bundle.Transactions[0].Nonce
+			if cmd.Flags().Changed("nonce") {
+				nonce, _ = cmd.Flags().GetUint64("nonce")
+			}
+			for i, t := range bundle.Transactions {
+				t.Nonce = nonce + uint64(i)
+			}
+			if err := bundle.check(); err != nil {
+				fmt.Fprint(os.Stderr, cmd.UsageString())
+				return newErrorf(ErrCodeInvalidArgument, "Error: bundle: %v", err)
+			}
+
+			outStr, _ := cmd.Flags().GetString("out")
+			if outStr == "" {
+				outStr = time.Now().Format("20060102150405") + ".bundle"
+			}
+			if err := saveJSONToFile(bundle, outStr); err != nil {
+				return newError(ErrCodeFile, err)
+			}
+			fmt.Printf("Successfully save %d transactions with nonce %d to %d to file %s\n",
+				len(bundle.Transactions), nonce, nonce+uint64(len(bundle.Transactions))-1, outStr)
+
+			return nil
+		},
+	}
+
+	cmd.Flags().String("out", "", "file `path` to save the bundle")
+	cmd.Flags().Uint64("nonce", 0, "the `nonce` of the first transaction")
+
+	return cmd
+}
@@ -0,0 +1,96 @@
+package cli
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestBundle(t *testing.T) {
+	dir, err := ioutil.TempDir("", "bundle")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	walletPath := filepath.Join(dir, "wallet")
+	account, err := keystore.NewKeyStore(walletPath, keystore.LightScryptN, keystore.LightScryptP).NewAccount("password")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cli := NewCLI()
+	flags := " --offline --nonce 1 --gasLimit 100000 -w " + walletPath + " -a 0xf09E6759c2588eE8435902d16350E321CBD27af3 -f " + account.Address.String()
+	cli.TestCommand("build confirm 3 --out " + filepath.Join(dir, "1.tx") + flags)
+	cli.TestCommand("build execute 3 --out " + filepath.Join(dir, "2.tx") + flags)
+
+	bundlePath := filepath.Join(dir, "txs.bundle")
+	cli.TestCommand("bundle --nonce 5 --out " + bundlePath + " " + filepath.Join(dir, "1.tx") + " " + filepath.Join(dir, "2.tx"))
+
+	bundle, err := readBundleFile(bundlePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(bundle.Transactions) != 2 || bundle.Transactions[0].Nonce != 5 || bundle.Transactions[1].Nonce != 6 {
+		t.Fatalf("got bundle %+v", bundle.Transactions)
+	}
+	if _, err := readBundleFile(filepath.Join(dir, "1.tx")); err != errNotBundle {
+		t.Fatalf("read transaction file as bundle got %v", err)
+	}
+
+	cli.walletPath = walletPath
+	for _, tran := range bundle.Transactions {
+		tran.Password = "password"
+	}
+	signedPath := bundlePath + ".sign"
+	bundleFileHash, err := fileHash(bundlePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := cli.signBundleAndSave(bundle, bundleFileHash, signedPath, "", false); err != nil {
+		t.Fatal(err)
+	}
+
+	signed, isBundle, err := readSignedFile(signedPath)
+	if err != nil || !isBundle {
+		t.Fatalf("read signed bundle got %v, bundle %v", err, isBundle)
+	}
+	for i, st := range signed {
+		tx, from, err := st.decode()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if from != account.Address || tx.Nonce() != uint64(5+i) || tx.ChainId().Int64() != 16888 || st.TxFileHash != bundleFileHash {
+			t.Fatalf("got signed transaction %d from %s nonce %d, file hash %s", i, from.String(), tx.Nonce(), st.TxFileHash.String())
+		}
+	}
+
+	// the hash recorded is cross-checked before broadcasting anything
+	signed[1].Hash = common.HexToHash("0x01")
+	if err := cli.broadcastBundle(signed, false); errorCode(err) != ErrCodeVerifyFailed {
+		t.Fatalf("broadcast tampered bundle got %v", err)
+	}
+
+	// the legacy signed bundle has only the raw hex
+	legacyPath := filepath.Join(dir, "legacy.sign")
+	b, err := json.Marshal(map[string][]string{"signedTransactions": {signed[0].Raw, signed[1].Raw}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(legacyPath, b, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if legacy, _, err := readSignedFile(legacyPath); err != nil || len(legacy) != 2 || !legacy[1].legacy {
+		t.Fatalf("read legacy signed bundle got %v", err)
+	}
+
+	bundle.Transactions[1].Nonce = 8
+	if err := bundle.check(); err == nil {
+		t.Fatal("bundle with nonce gap checked")
+	}
+}
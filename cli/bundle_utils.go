@@ -0,0 +1,223 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+var errNotBundle = errors.New("not a bundle file")
+
+// Bundle is an ordered list of unsigned transactions from one address with
+// consecutive nonces, signed with one unlock and broadcast in nonce order
+type Bundle struct {
+	Transactions []*Transaction
+}
+
+func (b *Bundle) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Transactions []*Transaction `json:"transactions"`
+	}{b.Transactions})
+}
+
+func (b *Bundle) UnmarshalJSON(input []byte) error {
+	var bundle struct {
+		Transactions []*Transaction `json:"transactions"`
+	}
+	if err := json.Unmarshal(input, &bundle); err != nil {
+		return err
+	}
+	if bundle.Transactions == nil {
+		return errNotBundle
+	}
+	b.Transactions = bundle.Transactions
+	return nil
+}
+
+// check checks the transactions are from one address on one chain with
+// consecutive nonces
+func (b *Bundle) check() error {
+	if len(b.Transactions) == 0 {
+		return errors.New("empty bundle")
+	}
+	first := b.Transactions[0]
+	for i, t := range b.Transactions {
+		if t.From != first.From {
+			return fmt.Errorf("transaction %d is from %s, not %s", i+1, t.From.String(), first.From.String())
+		}
+		if t.NetworkID == nil || first.NetworkID == nil || t.NetworkID.Cmp(first.NetworkID) != 0 {
+			return fmt.Errorf("transaction %d is on chain %v, not %v", i+1, t.NetworkID, first.NetworkID)
+		}
+		if t.Nonce != first.Nonce+uint64(i) {
+			return fmt.Errorf("transaction %d has nonce %d, not %d", i+1, t.Nonce, first.Nonce+uint64(i))
+		}
+	}
+	return nil
+}
+
+// SignedBundle is the signed transactions of a bundle, each signed from the
+// bundle file
+type SignedBundle struct {
+	Transactions []*SignedTx `json:"signedTransactions"`
+}
+
+// readBundleFile reads the unsigned bundle in file, errNotBundle is
+// returned if file is not a bundle
+func readBundleFile(path string) (*Bundle, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	bundle := new(Bundle)
+	if err := json.Unmarshal(b, bundle); err != nil {
+		if _, ok := err.(*json.SyntaxError); ok {
+			return nil, errNotBundle
+		}
+		return nil, err
+	}
+	return bundle, nil
+}
+
+func saveJSONToFile(v interface{}, filepath string) error {
+	b, err := json.MarshalIndent(v, "", " ")
+	if err != nil {
+		return err
+	}
+	return saveByteToFile(b, filepath)
+}
+
+// signedBundleTx is the JSON output of a signed transaction of bundle
+type signedBundleTx struct {
+	Hash  common.Hash    `json:"hash"`
+	From  common.Address `json:"from"`
+	Nonce uint64         `json:"nonce"`
+	Raw   string         `json:"raw"`
+}
+
+// signBundleAndSave shows and signs all the transactions of bundle with one
+// unlock, and saves the signed bundle built from the file of bundleFileHash
+// to filepath
+func (cli *CLI) signBundleAndSave(bundle *Bundle, bundleFileHash common.Hash, filepath, unit string, noIntent bool) error {
+	if err := bundle.check(); err != nil {
+		return newErrorf(ErrCodeInvalidArgument, "Error: bundle: %v", err)
+	}
+
+	count := len(bundle.Transactions)
+	for i, t := range bundle.Transactions {
+		cli.tran = t
+		fmt.Printf("Transaction %d/%d details are as follows:\n", i+1, count)
+		cli.printTxIndent()
+		fmt.Println("The data is as follows:")
+		showDataAuto(t.Data, "", unit)
+		t.showIntent(unit)
+		if err := t.checkIntent(noIntent); err != nil {
+			return newErrorf(errorCode(err), "transaction %d/%d: %w", i+1, count, err)
+		}
+	}
+
+	cli.tran = bundle.Transactions[0]
+	if err := cli.unlockWallet(accounts.Account{Address: cli.tran.From}); err != nil {
+		return newError(ErrCodeWallet, err)
+	}
+
+	var (
+		signed  SignedBundle
+		outputs []*signedBundleTx
+	)
+	for i, t := range bundle.Transactions {
+		cli.tran = t
+		signTx, err := cli.signTx()
+		if err != nil {
+			return newErrorf(ErrCodeWallet, "Error: sign transaction %d/%d: %v", i+1, count, err)
+		}
+		data, err := rlp.EncodeToBytes(signTx)
+		if err != nil {
+			return newError(ErrCodeInvalidArgument, err)
+		}
+		dataHex := common.ToHex(data)
+		fmt.Printf("Signed Transaction %d/%d Hash: %s\n", i+1, count, signTx.Hash().String())
+
+		signed.Transactions = append(signed.Transactions, newSignedTx(signTx, dataHex, t, bundleFileHash))
+		outputs = append(outputs, &signedBundleTx{signTx.Hash(), t.From, signTx.Nonce(), dataHex})
+	}
+
+	if err := saveJSONToFile(&signed, filepath); err != nil {
+		return newError(ErrCodeFile, err)
+	}
+	fmt.Printf("Successfully save %d signed transactions to file %s\n", count, filepath)
+
+	if cli.isJSON() {
+		cli.printJSON(struct {
+			Transactions []*signedBundleTx `json:"transactions"`
+			File         string            `json:"file"`
+		}{outputs, filepath})
+	}
+
+	return nil
+}
+
+// broadcastBundle cross-checks all the signed transactions with the hash and
+// signer recorded, then sends them in nonce order, and stops on the first
+// transaction not known to be successful
+func (cli *CLI) broadcastBundle(signed []*SignedTx, force bool) error {
+	type item struct {
+		raw  string
+		tx   *types.Transaction
+		from common.Address
+	}
+	items := make([]item, 0, len(signed))
+	for i, st := range signed {
+		tx, from, err := st.decode()
+		if err != nil {
+			return newErrorf(errorCode(err), "signed transaction %d: %w", i+1, err)
+		}
+		items = append(items, item{st.Raw, tx, from})
+	}
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].tx.Nonce() < items[j].tx.Nonce()
+	})
+
+	ctx := context.Background()
+	client, err := rpc.DialContext(ctx, cli.rpcURL)
+	if err != nil {
+		return newErrorf(ErrCodeRPC, "DialContext: %v", err)
+	}
+
+	count := len(items)
+	var outputs []*sentTxOutput
+	for i, it := range items {
+		fmt.Printf("[%d/%d] Broadcasting transaction %s (nonce %d)\n", i+1, count, it.tx.Hash().String(), it.tx.Nonce())
+		if err := cli.checkBeforeBroadcast(ctx, it.tx, it.from, force); err != nil {
+			fmt.Printf("[%d/%d] Refused, %d transactions not broadcast\n", i+1, count, count-i)
+			return newErrorf(errorCode(err), "bundle transaction %d/%d: %w", i+1, count, err)
+		}
+		result, err := cli.sendRawTx(ctx, client, it.raw, it.tx, it.from)
+		if err == nil {
+			var output *sentTxOutput
+			output, err = newSentTxOutput(it.tx, it.from, result)
+			outputs = append(outputs, output)
+		}
+		if err != nil {
+			fmt.Printf("[%d/%d] Failed, %d transactions not broadcast\n", i+1, count, count-i-1)
+			return newErrorf(errorCode(err), "bundle transaction %d/%d: %w", i+1, count, err)
+		}
+		fmt.Printf("[%d/%d] Success\n", i+1, count)
+	}
+
+	if cli.isJSON() {
+		cli.printJSON(struct {
+			Transactions []*sentTxOutput `json:"transactions"`
+		}{outputs})
+	}
+
+	return nil
+}
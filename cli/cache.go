@@ -0,0 +1,194 @@
+package cli
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb/util"
+)
+
+const defaultCachePath = "./cache/"
+
+var (
+	errCacheNotSynced = errors.New("contract wallet not synced, run sync first")
+)
+
+// walletTransaction is a transaction ID of contract wallet with its confirmations
+type walletTransaction struct {
+	ID            *big.Int         `json:"id"`
+	Destination   common.Address   `json:"destination"`
+	Value         *big.Int         `json:"value"`
+	Data          hexutil.Bytes    `json:"data"`
+	Executed      bool             `json:"executed"`
+	Confirmations []common.Address `json:"confirmations"`
+}
+
+// cachedWalletInfo is the basic info of contract wallet at the synced block
+type cachedWalletInfo struct {
+	ChainID          *big.Int         `json:"chainID"`
+	Contract         common.Address   `json:"contract"`
+	SyncedBlock      uint64           `json:"syncedBlock"`
+	SyncedTime       time.Time        `json:"syncedTime"`
+	Balance          *big.Int         `json:"balance"`
+	Owners           []common.Address `json:"owners"`
+	Required         *big.Int         `json:"required"`
+	DailyLimit       *big.Int         `json:"dailyLimit"`
+	TransactionCount uint64           `json:"transactionCount"`
+}
+
+// walletCache stores the transactions, confirmations and events of contract
+// wallets in leveldb, the keys are prefixed by chain ID and contract address
+type walletCache struct {
+	db     *leveldb.DB
+	prefix []byte
+}
+
+// openWalletCache opens the cache in path for contract on chain chainID
+func openWalletCache(path string, chainID *big.Int, contract common.Address) (*walletCache, error) {
+	db, err := leveldb.OpenFile(path, nil)
+	if err != nil {
+		return nil, fmt.Errorf("open cache(%s) error: %v", path, err)
+	}
+	return &walletCache{
+		db:     db,
+		prefix: []byte(fmt.Sprintf("%s-%s-", chainID.String(), strings.ToLower(contract.Hex()))),
+	}, nil
+}
+
+// openWalletCacheSynced opens the cache for contract on the chain it was
+// synced last time, so no network is needed
+func openWalletCacheSynced(path string, contract common.Address) (*walletCache, error) {
+	db, err := leveldb.OpenFile(path, nil)
+	if err != nil {
+		return nil, fmt.Errorf("open cache(%s) error: %v", path, err)
+	}
+	chainID, err := db.Get(chainKey(contract), nil)
+	if err == leveldb.ErrNotFound {
+		db.Close()
+		return nil, errCacheNotSynced
+	} else if err != nil {
+		db.Close()
+		return nil, err
+	}
+	return &walletCache{
+		db:     db,
+		prefix: []byte(fmt.Sprintf("%s-%s-", chainID, strings.ToLower(contract.Hex()))),
+	}, nil
+}
+
+func chainKey(contract common.Address) []byte {
+	return []byte("chain-" + strings.ToLower(contract.Hex()))
+}
+
+func (c *walletCache) Close() error {
+	return c.db.Close()
+}
+
+func (c *walletCache) key(parts ...string) []byte {
+	return append(append([]byte{}, c.prefix...), []byte(strings.Join(parts, "-"))...)
+}
+
+// uint64Key returns the big endian key so that leveldb iterates in order
+func uint64Key(n uint64) string {
+	b := make([]byte, 8)
+	binary.BigEndian.PutUint64(b, n)
+	return string(b)
+}
+
+func (c *walletCache) getJSON(key []byte, v interface{}) error {
+	b, err := c.db.Get(key, nil)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, v)
+}
+
+func putJSON(batch *leveldb.Batch, key []byte, v interface{}) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	batch.Put(key, b)
+	return nil
+}
+
+// Info returns the basic info of contract wallet saved by the last sync
+func (c *walletCache) Info() (*cachedWalletInfo, error) {
+	info := new(cachedWalletInfo)
+	if err := c.getJSON(c.key("info"), info); err == leveldb.ErrNotFound {
+		return nil, errCacheNotSynced
+	} else if err != nil {
+		return nil, err
+	}
+	return info, nil
+}
+
+// Transaction returns the cached transaction ID
+func (c *walletCache) Transaction(id uint64) (*walletTransaction, error) {
+	t := new(walletTransaction)
+	if err := c.getJSON(c.key("tx", uint64Key(id)), t); err == leveldb.ErrNotFound {
+		return nil, fmt.Errorf("transaction ID %d not in cache", id)
+	} else if err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// Transactions returns the cached transaction IDs in [from, to)
+func (c *walletCache) Transactions(from, to uint64) ([]*walletTransaction, error) {
+	var txs []*walletTransaction
+	it := c.db.NewIterator(&util.Range{Start: c.key("tx", uint64Key(from)), Limit: c.key("tx", uint64Key(to))}, nil)
+	defer it.Release()
+	for it.Next() {
+		t := new(walletTransaction)
+		if err := json.Unmarshal(it.Value(), t); err != nil {
+			return nil, err
+		}
+		txs = append(txs, t)
+	}
+	return txs, it.Error()
+}
+
+// Events returns the cached events in blocks [start, end]
+func (c *walletCache) Events(start, end uint64) ([]*walletEvent, error) {
+	var events []*walletEvent
+	it := c.db.NewIterator(&util.Range{Start: c.key("event", uint64Key(start)), Limit: c.key("event", uint64Key(end+1))}, nil)
+	defer it.Release()
+	for it.Next() {
+		e := new(walletEvent)
+		if err := json.Unmarshal(it.Value(), e); err != nil {
+			return nil, err
+		}
+		events = append(events, e)
+	}
+	return events, it.Error()
+}
+
+// Update saves the synced info, transactions and events in one batch
+func (c *walletCache) Update(info *cachedWalletInfo, txs []*walletTransaction, events []*walletEvent) error {
+	batch := new(leveldb.Batch)
+	for _, t := range txs {
+		if err := putJSON(batch, c.key("tx", uint64Key(t.ID.Uint64())), t); err != nil {
+			return err
+		}
+	}
+	for _, e := range events {
+		if err := putJSON(batch, c.key("event", uint64Key(e.BlockNumber), uint64Key(uint64(e.Index))), e); err != nil {
+			return err
+		}
+	}
+	if err := putJSON(batch, c.key("info"), info); err != nil {
+		return err
+	}
+	batch.Put(chainKey(info.Contract), []byte(info.ChainID.String()))
+
+	return c.db.Write(batch, nil)
+}
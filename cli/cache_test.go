@@ -0,0 +1,62 @@
+package cli
+
+import (
+	"io/ioutil"
+	"math/big"
+	"os"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestWalletCache(t *testing.T) {
+	dir, err := ioutil.TempDir("", "msw-cache")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	contract := common.HexToAddress("0xeF0b04a14e62434a99C4aF28C6dAb52ba9B1C8F3")
+	cache, err := openWalletCache(dir, big.NewInt(16888), contract)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := cache.Info(); err != errCacheNotSynced {
+		t.Errorf("wrong error of empty cache: want %v, got %v", errCacheNotSynced, err)
+	}
+
+	info := &cachedWalletInfo{ChainID: big.NewInt(16888), Contract: contract, SyncedBlock: 300, Required: big.NewInt(2), TransactionCount: 3}
+	var txs []*walletTransaction
+	for i := int64(0); i < 3; i++ {
+		txs = append(txs, &walletTransaction{ID: big.NewInt(i), Value: big.NewInt(i * 10)})
+	}
+	events := []*walletEvent{
+		{Name: EventSubmission, TransactionID: big.NewInt(0), BlockNumber: 100},
+		{Name: EventConfirmation, TransactionID: big.NewInt(0), BlockNumber: 100, Index: 1},
+		{Name: EventSubmission, TransactionID: big.NewInt(1), BlockNumber: 256},
+	}
+	if err := cache.Update(info, txs, events); err != nil {
+		t.Fatal(err)
+	}
+	cache.Close()
+
+	// open without chainID as the commands with --cached do
+	cache, err = openWalletCacheSynced(dir, contract)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cache.Close()
+
+	got, err := cache.Info()
+	if err != nil || got.SyncedBlock != 300 || got.TransactionCount != 3 {
+		t.Errorf("wrong info: %+v, %v", got, err)
+	}
+	gotTxs, err := cache.Transactions(1, 3)
+	if err != nil || len(gotTxs) != 2 || gotTxs[0].ID.Int64() != 1 || gotTxs[1].Value.Int64() != 20 {
+		t.Errorf("wrong transactions: %v, %v", gotTxs, err)
+	}
+	gotEvents, err := cache.Events(100, 255)
+	if err != nil || len(gotEvents) != 2 || gotEvents[1].Name != EventConfirmation {
+		t.Errorf("wrong events: %v, %v", gotEvents, err)
+	}
+}
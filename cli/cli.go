@@ -13,6 +13,8 @@ import (
 	"github.com/ethereum/go-ethereum/accounts/keystore"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/newtonproject/MultiSignatureWallet/msw"
 	"github.com/spf13/cobra"
 )
 
@@ -28,24 +30,30 @@ type CLI struct {
 	rootCmd    *cobra.Command
 	version    string
 	walletPath string
+	cachePath  string
 	rpcURL     string
+	output     string
+	stdout     *os.File // the stdout replaced by setupOutput
 	config     string
 	//testing    bool
 
 	contractAddress string
 	client          *ethclient.Client
+	rpcClient       *rpc.Client
 	wallet          *keystore.KeyStore
 	account         accounts.Account
 	simpleRegistry  *SimpleRegistry
+	multisig        *msw.Wallet
 	walletPassword  string
 	address         string
 
 	tran *Transaction
+	gas  *GasPolicy
 	bc   BlockChain
 }
 
 type SimpleRegistry struct {
-	*MultiSigWalletWithDailyLimit
+	*msw.MultiSigWalletWithDailyLimit
 }
 
 // NewCLI returns an initialized CLI
@@ -72,6 +80,7 @@ func NewCLI() *CLI {
 		rootCmd:    nil,
 		version:    version,
 		walletPath: "",
+		cachePath:  "",
 		rpcURL:     "",
 		//	testing:         false,
 		config:          "",
@@ -90,16 +99,20 @@ func NewCLI() *CLI {
 func (cli *CLI) BuildClient() error {
 	var err error
 	if cli.client == nil {
-		cli.client, err = ethclient.Dial(cli.rpcURL)
+		cli.rpcClient, err = rpc.Dial(cli.rpcURL)
 		if err != nil {
 			return fmt.Errorf("failed to connect to the %s client: %v", cli.bc.String(), err)
 		}
+		cli.client = ethclient.NewClient(cli.rpcClient)
 	}
 	return nil
 }
 
-// BuildSimpleRegistry BuildClient
-func (cli *CLI) buildSimpleRegistry() (*SimpleRegistry, error) {
+// GetWallet returns the msw client of the contract wallet
+func (cli *CLI) GetWallet() (*msw.Wallet, error) {
+	if cli.multisig != nil {
+		return cli.multisig, nil
+	}
 	if cli.client == nil {
 		if err := cli.BuildClient(); err != nil {
 			return nil, err
@@ -110,8 +123,22 @@ func (cli *CLI) buildSimpleRegistry() (*SimpleRegistry, error) {
 		return nil, fmt.Errorf("contract address is invalid")
 	}
 
-	m, _ := NewMultiSigWalletWithDailyLimit(common.HexToAddress(cli.contractAddress), cli.client)
-	cli.simpleRegistry = &SimpleRegistry{m}
+	w, err := msw.NewWallet(common.HexToAddress(cli.contractAddress), cli.client, nil)
+	if err != nil {
+		return nil, err
+	}
+	cli.multisig = w
+	return cli.multisig, nil
+}
+
+// BuildSimpleRegistry BuildClient
+func (cli *CLI) buildSimpleRegistry() (*SimpleRegistry, error) {
+	w, err := cli.GetWallet()
+	if err != nil {
+		return nil, err
+	}
+
+	cli.simpleRegistry = &SimpleRegistry{w.Contract()}
 	return cli.simpleRegistry, nil
 }
 
@@ -163,7 +190,9 @@ func (cli *CLI) buildAccount(address string) error {
 	return nil
 }
 
-func (cli *CLI) getTransactOpts(address string) (*bind.TransactOpts, error) {
+// getTransactOpts returns the TransactOpts of address, with the gas price
+// and gas limit of action chosen by the gas policy
+func (cli *CLI) getTransactOpts(address, action string) (*bind.TransactOpts, error) {
 	err := cli.buildAccount(address)
 	if err != nil {
 		return nil, err
@@ -177,23 +206,33 @@ func (cli *CLI) getTransactOpts(address string) (*bind.TransactOpts, error) {
 	}
 
 	opts := NewKeyedTransactorByAccount(cli.wallet, cli.account, cli.walletPassword, networkID)
+	if err := cli.applyGasPolicy(context.Background(), opts, action); err != nil {
+		return nil, err
+	}
 	return opts, nil
 }
 
-// Execute parses the command line and processes it.
-func (cli *CLI) Execute() {
-	cli.rootCmd.Execute()
+// Execute parses the command line and processes it. The error is printed
+// and returned, use ExitCode to get the exit code for it.
+func (cli *CLI) Execute() error {
+	defer cli.restoreOutput()
+	err := cli.rootCmd.Execute()
+	if err != nil {
+		cli.printError(err)
+	}
+	return err
 }
 
 // setup turns up the CLI environment, and gets called by Cobra before
 // a command is executed.
-func (cli *CLI) setup(cmd *cobra.Command, args []string) {
+func (cli *CLI) setup(cmd *cobra.Command, args []string) error {
 	err := setupConfig(cli)
 	if err != nil {
-		fmt.Println(err)
 		fmt.Fprint(os.Stderr, cmd.UsageString())
-		os.Exit(1)
+		return newError(ErrCodeInvalidArgument, err)
 	}
+	cli.setupOutput()
+	return nil
 }
 
 func (cli *CLI) help(cmd *cobra.Command, args []string) {
@@ -220,7 +259,10 @@ func (cli *CLI) Run(args ...string) string {
 	os.Stdout = w
 
 	cli.rootCmd.SetArgs(args)
-	cli.rootCmd.Execute()
+	if err := cli.rootCmd.Execute(); err != nil {
+		cli.printError(err)
+	}
+	cli.restoreOutput()
 	cli.buildRootCmd()
 
 	w.Close()
@@ -233,7 +275,7 @@ func (cli *CLI) Run(args ...string) string {
 }
 
 // Embeddable returns a CLI that you can embed into your own Go programs. This
-// is not thread-safe.
+// is not thread-safe, use package msw for a typed Go API instead.
 func (cli *CLI) Embeddable() *CLI {
 
 	return cli
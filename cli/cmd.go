@@ -2,6 +2,8 @@ package cli
 
 import (
 	"fmt"
+	"os"
+	"strings"
 
 	"github.com/spf13/cobra"
 )
@@ -14,19 +16,32 @@ func (cli *CLI) buildRootCmd() {
 	}
 
 	rootCmd := &cobra.Command{
-		Use:              cli.Name,
-		Short:            cli.Name + " is commandline client for users to interact with the MultiSigWallet contract.",
-		Run:              cli.help,
-		PersistentPreRun: cli.setup,
+		Use:               cli.Name,
+		Short:             cli.Name + " is commandline client for users to interact with the MultiSigWallet contract.",
+		Run:               cli.help,
+		PersistentPreRunE: cli.setup,
+		SilenceErrors:     true,
+		SilenceUsage:      true,
 	}
+	rootCmd.SetFlagErrorFunc(func(cmd *cobra.Command, err error) error {
+		fmt.Fprint(os.Stderr, cmd.UsageString())
+		return newError(ErrCodeInvalidArgument, err)
+	})
 	cli.rootCmd = rootCmd
 
 	// Global flags
 	rootCmd.PersistentFlags().StringVarP(&cli.config, "config", "c", defaultConfigFile, "The `path` to config file")
 	rootCmd.PersistentFlags().StringP("walletPath", "w", defaultWalletPath, "Wallet storage `directory`")
+	rootCmd.PersistentFlags().String("cachePath", defaultCachePath, "Local cache `directory` of contract wallet")
 	rootCmd.PersistentFlags().StringP("rpcURL", "i", defaultRPCURL, fmt.Sprintf("%s json rpc or ipc `url`", cli.bc.String()))
 	rootCmd.PersistentFlags().StringP("contractAddress", "a", defaultContractAddress, "Contract `address`")
 	rootCmd.PersistentFlags().StringP("from", "f", "", "the from `address` who pay gas")
+	rootCmd.PersistentFlags().String("output", defaultOutput, fmt.Sprintf("the output `format`. Available format: %s", strings.Join(OutputList, ",")))
+	rootCmd.PersistentFlags().Float64("gasMultiplier", defaultGasMultiplier, "the `multiplier` of the estimated gas to get the gas limit")
+	rootCmd.PersistentFlags().Uint64("gasCap", 0, "the maximum gas `limit` of the multiplied estimated gas, 0 for no cap")
+	rootCmd.PersistentFlags().Uint64("fixedGasLimit", 0, "the fixed gas `limit` of the transaction instead of estimating")
+	rootCmd.PersistentFlags().String("fixedGasPrice", "", "the fixed gas `price` in WEI instead of the gas price of node")
+	rootCmd.PersistentFlags().Float64("gasPriceMultiplier", defaultGasPriceMultiplier, "the `multiplier` of the gas price of node")
 
 	// Basic commands
 	rootCmd.AddCommand(cli.buildInitCmd())    // init
@@ -41,12 +56,20 @@ func (cli *CLI) buildRootCmd() {
 	// info
 	rootCmd.AddCommand(cli.buildInfoCmd())
 
+	// events
+	rootCmd.AddCommand(cli.buildEventsCmd())
+	rootCmd.AddCommand(cli.buildWatchCmd())
+
+	// sync
+	rootCmd.AddCommand(cli.buildSyncCmd())
+
 	// tx
 	rootCmd.AddCommand(cli.buildTxSubmitCmd())
 	rootCmd.AddCommand(cli.buildTxConfirmCmd())
 	rootCmd.AddCommand(cli.buildTxRevokeCmd())
 	rootCmd.AddCommand(cli.buildTxExecuteCmd())
 	rootCmd.AddCommand(cli.buildTxListCmd())
+	rootCmd.AddCommand(cli.buildTxCmd())
 
 	// owner
 	rootCmd.AddCommand(cli.buildOwnerCmd())
@@ -55,8 +78,13 @@ func (cli *CLI) buildRootCmd() {
 	rootCmd.AddCommand(cli.buildUpdateCmd())
 
 	// tx for offline
-	rootCmd.AddCommand(cli.buildBuildCmd())     // build
-	rootCmd.AddCommand(cli.buildSignCmd())      // sign
-	rootCmd.AddCommand(cli.buildBroadcastCmd()) // broadcast
+	rootCmd.AddCommand(cli.buildBuildCmd())       // build
+	rootCmd.AddCommand(cli.buildSignCmd())        // sign
+	rootCmd.AddCommand(cli.buildBroadcastCmd())   // broadcast
+	rootCmd.AddCommand(cli.buildVerifyCmd())      // verify
+	rootCmd.AddCommand(cli.buildBundleCmd())      // bundle
+	rootCmd.AddCommand(cli.buildExportStateCmd()) // export-state
+	rootCmd.AddCommand(cli.buildNonceCmd())       // nonce
+	rootCmd.AddCommand(cli.buildQRImportCmd())    // qr-import
 
 }
@@ -1,7 +1,9 @@
 package cli
 
 import (
+	"fmt"
 	"os"
+	"strings"
 
 	"github.com/spf13/viper"
 )
@@ -12,13 +14,25 @@ const defaultContractAddress = ""
 
 func defaultConfig(cli *CLI) {
 	viper.BindPFlag("walletPath", cli.rootCmd.PersistentFlags().Lookup("walletPath"))
+	viper.BindPFlag("cachePath", cli.rootCmd.PersistentFlags().Lookup("cachePath"))
 	viper.BindPFlag("rpcURL", cli.rootCmd.PersistentFlags().Lookup("rpcURL"))
 	viper.BindPFlag("contractAddress", cli.rootCmd.PersistentFlags().Lookup("contractAddress"))
 	viper.BindPFlag("from", cli.rootCmd.PersistentFlags().Lookup("from"))
+	viper.BindPFlag("output", cli.rootCmd.PersistentFlags().Lookup("output"))
+	viper.BindPFlag("gas.multiplier", cli.rootCmd.PersistentFlags().Lookup("gasMultiplier"))
+	viper.BindPFlag("gas.cap", cli.rootCmd.PersistentFlags().Lookup("gasCap"))
+	viper.BindPFlag("gas.fixedLimit", cli.rootCmd.PersistentFlags().Lookup("fixedGasLimit"))
+	viper.BindPFlag("gas.price", cli.rootCmd.PersistentFlags().Lookup("fixedGasPrice"))
+	viper.BindPFlag("gas.priceMultiplier", cli.rootCmd.PersistentFlags().Lookup("gasPriceMultiplier"))
 
 	viper.SetDefault("walletPath", defaultWalletPath)
+	viper.SetDefault("cachePath", defaultCachePath)
 	viper.SetDefault("rpcURL", defaultRPCURL)
 	viper.SetDefault("contractAddress", defaultContractAddress)
+	viper.SetDefault("output", defaultOutput)
+	viper.SetDefault("gas.multiplier", defaultGasMultiplier)
+	viper.SetDefault("gas.deployMultiplier", defaultDeployGasMultiplier)
+	viper.SetDefault("gas.priceMultiplier", defaultGasPriceMultiplier)
 }
 
 func setupConfig(cli *CLI) error {
@@ -56,12 +70,22 @@ func setupConfig(cli *CLI) error {
 	if walletPath := viper.GetString("walletPath"); walletPath != "" {
 		cli.walletPath = walletPath
 	}
+	if cachePath := viper.GetString("cachePath"); cachePath != "" {
+		cli.cachePath = cachePath
+	}
 	if contractAddress := viper.GetString("contractAddress"); contractAddress != "" {
 		cli.contractAddress = contractAddress
 	}
 	if address := viper.GetString("from"); address != "" {
 		cli.address = address
 	}
+	cli.output = viper.GetString("output")
+	if !stringInSlice(cli.output, OutputList) {
+		return fmt.Errorf("output format(%s) invalid. Available format: %s", cli.output, strings.Join(OutputList, ","))
+	}
+	if cli.gas, err = loadGasPolicy(); err != nil {
+		return err
+	}
 
 	return nil
 }
@@ -0,0 +1,434 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+var errNotPayable = errors.New("method is not payable, the value must be 0")
+
+// ContractCall is the call of a method of any contract, with the ABI entry
+// of the method so that the call can be packed and decoded without the ABI
+// file, such as on the offline computer
+type ContractCall struct {
+	ABI    json.RawMessage `json:"abi"`
+	Method string          `json:"method"`
+	Args   []string        `json:"args"`
+}
+
+// abiFunction is a function entry of the ABI file
+type abiFunction struct {
+	abi.Method
+	raw     json.RawMessage
+	payable *bool
+}
+
+func parseABIFunction(raw json.RawMessage) (*abiFunction, error) {
+	var field struct {
+		Type            string
+		Name            string
+		Constant        bool
+		Payable         *bool
+		StateMutability string
+		Inputs          []abi.Argument
+	}
+	if err := json.Unmarshal(raw, &field); err != nil {
+		return nil, err
+	}
+	if field.Type != "function" && field.Type != "" {
+		return nil, fmt.Errorf("%s is not a function", field.Type)
+	}
+
+	f := &abiFunction{
+		Method: abi.Method{Name: field.Name, Const: field.Constant, Inputs: field.Inputs},
+		raw:    raw,
+	}
+	if field.StateMutability != "" {
+		payable := field.StateMutability == "payable"
+		f.payable = &payable
+	} else {
+		f.payable = field.Payable
+	}
+	return f, nil
+}
+
+// readABIFile reads the functions of the ABI file, the file is the ABI array
+// or the compiled artifact with the field abi, such as of truffle or hardhat
+func readABIFile(path string) ([]*abiFunction, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []json.RawMessage
+	if err := json.Unmarshal(b, &entries); err != nil {
+		var artifact struct {
+			ABI []json.RawMessage `json:"abi"`
+		}
+		if err := json.Unmarshal(b, &artifact); err != nil || artifact.ABI == nil {
+			return nil, fmt.Errorf("%s is not an ABI file", path)
+		}
+		entries = artifact.ABI
+	}
+
+	var functions []*abiFunction
+	for _, raw := range entries {
+		var field struct {
+			Type string
+		}
+		if err := json.Unmarshal(raw, &field); err != nil {
+			return nil, err
+		}
+		if field.Type != "function" && field.Type != "" {
+			continue
+		}
+		f, err := parseABIFunction(raw)
+		if err != nil {
+			return nil, err
+		}
+		functions = append(functions, f)
+	}
+	return functions, nil
+}
+
+// findABIFunction finds the function by name, or by signature such as
+// transfer(address,uint256) if the name is overloaded
+func findABIFunction(functions []*abiFunction, method string) (*abiFunction, error) {
+	var found []*abiFunction
+	for _, f := range functions {
+		if f.Name == method || f.Sig() == method {
+			found = append(found, f)
+		}
+	}
+	switch len(found) {
+	case 0:
+		return nil, fmt.Errorf("method(%s) not found in ABI", method)
+	case 1:
+		return found[0], nil
+	}
+
+	var sigs []string
+	for _, f := range found {
+		sigs = append(sigs, f.Sig())
+	}
+	return nil, fmt.Errorf("method(%s) is overloaded, use the signature: %s", method, strings.Join(sigs, ", "))
+}
+
+// pack returns the data to call the function with the text args
+func (f *abiFunction) pack(args []string) ([]byte, error) {
+	if len(args) != len(f.Inputs) {
+		return nil, fmt.Errorf("%s needs %d arguments, but got %d", f.Sig(), len(f.Inputs), len(args))
+	}
+	values := make([]interface{}, len(args))
+	for i, input := range f.Inputs {
+		v, err := parseABIArg(input.Type, args[i])
+		if err != nil {
+			return nil, fmt.Errorf("argument %d (%s %s): %v", i+1, input.Type.String(), input.Name, err)
+		}
+		values[i] = v.Interface()
+	}
+	arguments, err := f.Inputs.Pack(values...)
+	if err != nil {
+		return nil, err
+	}
+	return append(f.Id(), arguments...), nil
+}
+
+// newContractCall returns the call of method in the ABI file with args,
+// whose value is checked against the payable of the method
+func newContractCall(abiPath, method string, args []string, value *big.Int) (*ContractCall, error) {
+	functions, err := readABIFile(abiPath)
+	if err != nil {
+		return nil, err
+	}
+	f, err := findABIFunction(functions, method)
+	if err != nil {
+		return nil, err
+	}
+	if value != nil && value.Sign() > 0 && f.payable != nil && !*f.payable {
+		return nil, fmt.Errorf("%s: %v", f.Sig(), errNotPayable)
+	}
+	if _, err := f.pack(args); err != nil {
+		return nil, err
+	}
+
+	var raw bytes.Buffer
+	if err := json.Compact(&raw, f.raw); err != nil {
+		return nil, err
+	}
+	return &ContractCall{ABI: raw.Bytes(), Method: f.Sig(), Args: args}, nil
+}
+
+func (c *ContractCall) function() (*abiFunction, error) {
+	f, err := parseABIFunction(c.ABI)
+	if err != nil {
+		return nil, fmt.Errorf("call: abi: %v", err)
+	}
+	if f.Sig() != c.Method {
+		return nil, fmt.Errorf("call: method(%s) is not the abi(%s)", c.Method, f.Sig())
+	}
+	return f, nil
+}
+
+// pack returns the data of the call
+func (c *ContractCall) pack() ([]byte, error) {
+	f, err := c.function()
+	if err != nil {
+		return nil, err
+	}
+	return f.pack(c.Args)
+}
+
+// show decodes the data packed and shows the arguments, so that what is
+// reviewed is what is sent
+func (c *ContractCall) show(indent string) error {
+	f, err := c.function()
+	if err != nil {
+		return err
+	}
+	data, err := f.pack(c.Args)
+	if err != nil {
+		return err
+	}
+	values, err := f.Inputs.UnpackValues(data[4:])
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s%s\n", indent, f.Method.String())
+	for i, input := range f.Inputs {
+		fmt.Printf("%s\t%s (%s): %s\n", indent, input.Name, input.Type.String(), formatABIValue(values[i]))
+	}
+	fmt.Printf("%sData: %s\n", indent, hexutil.Encode(data))
+	return nil
+}
+
+func formatABIValue(v interface{}) string {
+	switch vv := v.(type) {
+	case common.Address:
+		return vv.String()
+	case []byte:
+		return hexutil.Encode(vv)
+	case string:
+		return strconv.Quote(vv)
+	}
+
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Array, reflect.Slice:
+		if rv.Type().Elem().Kind() == reflect.Uint8 {
+			b := make([]byte, rv.Len())
+			reflect.Copy(reflect.ValueOf(b), rv)
+			return hexutil.Encode(b)
+		}
+		items := make([]string, rv.Len())
+		for i := range items {
+			items[i] = formatABIValue(rv.Index(i).Interface())
+		}
+		return "[" + strings.Join(items, ", ") + "]"
+	}
+	return fmt.Sprintf("%v", v)
+}
+
+// parseABIArg converts the text s to the value of type t to pack, the
+// numbers are decimal or 0x hex, the bytes are 0x hex, the arrays are
+// [a, b, c] with the items quoted if they contain commas
+func parseABIArg(t abi.Type, s string) (reflect.Value, error) {
+	switch t.T {
+	case abi.IntTy, abi.UintTy:
+		n, ok := parseABINumber(strings.TrimSpace(s))
+		if !ok {
+			return reflect.Value{}, fmt.Errorf("invalid number %q", s)
+		}
+		if t.T == abi.UintTy && (n.Sign() < 0 || n.BitLen() > t.Size) {
+			return reflect.Value{}, fmt.Errorf("%s out of range of %s", n.String(), t.String())
+		}
+		if t.T == abi.IntTy {
+			max := new(big.Int).Lsh(big.NewInt(1), uint(t.Size-1))
+			if n.Cmp(new(big.Int).Neg(max)) < 0 || n.Cmp(max) >= 0 {
+				return reflect.Value{}, fmt.Errorf("%s out of range of %s", n.String(), t.String())
+			}
+		}
+		if t.Type == reflect.TypeOf(n) {
+			return reflect.ValueOf(n), nil
+		}
+		v := reflect.New(t.Type).Elem()
+		if t.T == abi.UintTy {
+			v.SetUint(n.Uint64())
+		} else {
+			v.SetInt(n.Int64())
+		}
+		return v, nil
+	case abi.BoolTy:
+		b, err := strconv.ParseBool(strings.TrimSpace(s))
+		if err != nil {
+			return reflect.Value{}, fmt.Errorf("invalid bool %q", s)
+		}
+		return reflect.ValueOf(b), nil
+	case abi.StringTy:
+		return reflect.ValueOf(s), nil
+	case abi.AddressTy:
+		s = strings.TrimSpace(s)
+		if !common.IsHexAddress(s) {
+			return reflect.Value{}, fmt.Errorf("invalid address %q", s)
+		}
+		return reflect.ValueOf(common.HexToAddress(s)), nil
+	case abi.BytesTy:
+		b, err := hexutil.Decode(strings.TrimSpace(s))
+		if err != nil {
+			return reflect.Value{}, fmt.Errorf("invalid hex bytes %q: %v", s, err)
+		}
+		return reflect.ValueOf(b), nil
+	case abi.FixedBytesTy:
+		b, err := hexutil.Decode(strings.TrimSpace(s))
+		if err != nil {
+			return reflect.Value{}, fmt.Errorf("invalid hex bytes %q: %v", s, err)
+		}
+		if len(b) != t.Size {
+			return reflect.Value{}, fmt.Errorf("%s needs %d bytes, but got %d", t.String(), t.Size, len(b))
+		}
+		v := reflect.New(t.Type).Elem()
+		reflect.Copy(v, reflect.ValueOf(b))
+		return v, nil
+	case abi.SliceTy, abi.ArrayTy:
+		items, err := splitABIArray(s)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		var v reflect.Value
+		if t.T == abi.SliceTy {
+			v = reflect.MakeSlice(t.Type, len(items), len(items))
+		} else {
+			if len(items) != t.Size {
+				return reflect.Value{}, fmt.Errorf("%s needs %d items, but got %d", t.String(), t.Size, len(items))
+			}
+			v = reflect.New(t.Type).Elem()
+		}
+		for i, item := range items {
+			e, err := parseABIArg(*t.Elem, item)
+			if err != nil {
+				return reflect.Value{}, fmt.Errorf("item %d: %v", i+1, err)
+			}
+			v.Index(i).Set(e)
+		}
+		return v, nil
+	}
+
+	return reflect.Value{}, fmt.Errorf("type %s not supported", t.String())
+}
+
+// parseABINumber parses the decimal or 0x hex number, with the sign
+func parseABINumber(s string) (*big.Int, bool) {
+	neg := strings.HasPrefix(s, "-")
+	digits := strings.TrimPrefix(s, "-")
+	base := 10
+	if strings.HasPrefix(digits, "0x") || strings.HasPrefix(digits, "0X") {
+		digits, base = digits[2:], 16
+	}
+	n, ok := new(big.Int).SetString(digits, base)
+	if !ok {
+		return nil, false
+	}
+	if neg {
+		n.Neg(n)
+	}
+	return n, true
+}
+
+// splitABIArray splits the text array [a, "b,c", [d, e]] into its items,
+// the quotes of the items are removed
+func splitABIArray(s string) ([]string, error) {
+	s = strings.TrimSpace(s)
+	if len(s) < 2 || s[0] != '[' || s[len(s)-1] != ']' {
+		return nil, fmt.Errorf("invalid array %q, use [a, b, c]", s)
+	}
+	inner := s[1 : len(s)-1]
+	if strings.TrimSpace(inner) == "" {
+		return []string{}, nil
+	}
+
+	var (
+		items  []string
+		item   strings.Builder
+		depth  int
+		quoted bool
+	)
+	for i := 0; i < len(inner); i++ {
+		c := inner[i]
+		switch {
+		case quoted && c == '\\' && i+1 < len(inner):
+			i++
+			if depth > 0 {
+				item.WriteByte(c)
+			}
+			item.WriteByte(inner[i])
+			continue
+		case c == '"':
+			quoted = !quoted
+			if depth > 0 {
+				item.WriteByte(c)
+			}
+			continue
+		case quoted:
+		case c == '[':
+			depth++
+		case c == ']':
+			depth--
+		case c == ',' && depth == 0:
+			items = append(items, strings.TrimSpace(item.String()))
+			item.Reset()
+			continue
+		}
+		item.WriteByte(c)
+	}
+	if quoted || depth != 0 {
+		return nil, fmt.Errorf("invalid array %q, unbalanced quotes or brackets", s)
+	}
+	return append(items, strings.TrimSpace(item.String())), nil
+}
+
+// addCallFlags adds the flags to call a method of contract by its ABI
+func addCallFlags(flags *pflag.FlagSet) {
+	flags.String("abi", "", "the ABI `file` of target contract, to call its method")
+	flags.String("method", "", "the `name` or signature such as transfer(address,uint256) of the method to call, required by abi")
+	flags.StringArray("args", nil, "the `argument` of the method to call, repeat for each argument in order, array as [a,b,c]")
+}
+
+// loadCallFlags returns the call of the flags abi, method and args paying
+// value, nil if abi not set
+func loadCallFlags(cmd *cobra.Command, value *big.Int) (*ContractCall, error) {
+	if !cmd.Flags().Changed("abi") {
+		if cmd.Flags().Changed("method") || cmd.Flags().Changed("args") {
+			return nil, newErrorf(ErrCodeInvalidArgument, "Error: flag method and args are only used with abi")
+		}
+		return nil, nil
+	}
+	if cmd.Flags().Changed("data") {
+		return nil, newErrorf(ErrCodeInvalidArgument, "Error: flag data can not be used with abi")
+	}
+	abiPath, _ := cmd.Flags().GetString("abi")
+	method, _ := cmd.Flags().GetString("method")
+	if method == "" {
+		return nil, newErrorf(ErrCodeInvalidArgument, "Error: flag method is required by abi")
+	}
+	args, _ := cmd.Flags().GetStringArray("args")
+
+	call, err := newContractCall(abiPath, method, args, value)
+	if err != nil {
+		return nil, newErrorf(ErrCodeInvalidArgument, "Error: call %s: %v", method, err)
+	}
+	return call, nil
+}
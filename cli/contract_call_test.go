@@ -0,0 +1,122 @@
+package cli
+
+import (
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+const testCallABI = `{"contractName":"Governed","abi":[
+{"type":"function","name":"set","stateMutability":"nonpayable","inputs":[{"name":"a","type":"uint8"},{"name":"b","type":"int256"},{"name":"c","type":"bool"},{"name":"d","type":"bytes"},{"name":"e","type":"bytes4"},{"name":"f","type":"string"},{"name":"g","type":"address[]"},{"name":"h","type":"uint16[2]"}],"outputs":[]},
+{"type":"function","name":"transfer","stateMutability":"nonpayable","inputs":[{"name":"to","type":"address"},{"name":"value","type":"uint256"}],"outputs":[]},
+{"type":"function","name":"transfer","stateMutability":"nonpayable","inputs":[{"name":"to","type":"address"},{"name":"value","type":"uint256"},{"name":"data","type":"bytes"}],"outputs":[]},
+{"type":"function","name":"deposit","payable":true,"inputs":[],"outputs":[]},
+{"type":"event","name":"Set","inputs":[]}]}`
+
+func writeTestABI(t *testing.T, dir string) string {
+	path := filepath.Join(dir, "Governed.json")
+	if err := ioutil.WriteFile(path, []byte(testCallABI), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestContractCall(t *testing.T) {
+	dir, err := ioutil.TempDir("", "call")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := writeTestABI(t, dir)
+
+	a := common.HexToAddress("0x9B3deA9C636BA262f870f98a1c64d444BF0f6544")
+	b := common.HexToAddress("0x7c1d845a0CC7E24352A59FEF437eB27b504769DE")
+	call, err := newContractCall(path, "set", []string{
+		"255", "-0x10", "true", "0x0102", "0xdeadbeef", "hello, world",
+		`["` + a.String() + `", ` + b.String() + `]`, "[1,65535]",
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := call.pack()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	parsed, err := abi.JSON(strings.NewReader(testCallABI[strings.Index(testCallABI, "[") : strings.LastIndex(testCallABI, "]")+1]))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, err := parsed.Pack("set", uint8(255), big.NewInt(-16), true, []byte{1, 2}, [4]byte{0xde, 0xad, 0xbe, 0xef},
+		"hello, world", []common.Address{a, b}, [2]uint16{1, 65535})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != string(want) {
+		t.Fatalf("got data %x, want %x", got, want)
+	}
+
+	for _, c := range []struct {
+		method string
+		args   []string
+		value  int64
+	}{
+		{"set", []string{"256", "0", "true", "0x", "0x00000000", "", "[]", "[1,2]"}, 0},
+		{"set", []string{"1"}, 0},
+		{"transfer", []string{a.String(), "1"}, 0},
+		{"transfer(address,uint256)", []string{a.String(), "1"}, 1},
+		{"approve", nil, 0},
+	} {
+		if _, err := newContractCall(path, c.method, c.args, big.NewInt(c.value)); err == nil {
+			t.Fatalf("call %s %v with value %d is accepted", c.method, c.args, c.value)
+		}
+	}
+	if _, err := newContractCall(path, "transfer(address,uint256,bytes)", []string{a.String(), "1", "0x"}, nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := newContractCall(path, "deposit", nil, big.NewInt(1)); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestBuildCall(t *testing.T) {
+	dir, err := ioutil.TempDir("", "call")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := writeTestABI(t, dir)
+	out := filepath.Join(dir, "call.tx")
+
+	cli := NewCLI()
+	cli.TestCommand("build submit --offline --nonce 7 --gasLimit 100000 --chainID 1007 -w " + dir +
+		" -a 0xf09E6759c2588eE8435902d16350E321CBD27af3 -f 0x9B3deA9C636BA262f870f98a1c64d444BF0f6544" +
+		" --to 0x7c1d845a0CC7E24352A59FEF437eB27b504769DE --amount 0 --abi " + path +
+		" --method transfer(address,uint256) --args 0x9B3deA9C636BA262f870f98a1c64d444BF0f6544 --args 1000 --out " + out)
+
+	b, err := ioutil.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tran := new(Transaction)
+	if err := tran.UnmarshalJSON(b); err != nil {
+		t.Fatal(err)
+	}
+	if tran.Intent == nil || tran.Intent.Action != IntentCall || tran.Intent.Call.Method != "transfer(address,uint256)" {
+		t.Fatalf("got intent %+v", tran.Intent)
+	}
+	if err := tran.verifyIntent(); err != nil {
+		t.Fatal(err)
+	}
+
+	tran.Intent.Call.Args[1] = "1001"
+	if err := tran.verifyIntent(); err != errIntentMismatch {
+		t.Fatalf("verify tampered call got %v", err)
+	}
+}
@@ -16,43 +16,38 @@ func (cli *CLI) buildDeployCmd() *cobra.Command {
 		Use:                   "deploy <-o addr0,addr1,addr3> <-r number> [-l dailyLimitAmountInUnit] [-u NEW|WEI]",
 		Short:                 fmt.Sprintf("Deploy %s contract", cli.bc.String()),
 		DisableFlagsInUseLine: true,
-		Run: func(cmd *cobra.Command, args []string) {
+		RunE: func(cmd *cobra.Command, args []string) error {
 			var err error
 
 			save, _ := cmd.Flags().GetBool("save")
 
 			unit, err := cmd.Flags().GetString("unit")
 			if err != nil {
-				fmt.Println("Error: required flag(s) \"unit\" not set")
 				fmt.Fprint(os.Stderr, cmd.UsageString())
-				return
+				return newErrorf(ErrCodeInvalidArgument, "Error: required flag(s) \"unit\" not set")
 			}
 			d := stringInSlice(unit, UnitList)
 			if !d {
-				fmt.Printf("Unit(%s) for amount error. %s.\n", unit, fmt.Sprintf("Available unit: %s", strings.Join(UnitList, ",")))
 				fmt.Fprint(os.Stderr, cmd.UsageString())
-				return
+				return newErrorf(ErrCodeInvalidArgument, "Unit(%s) for amount error. Available unit: %s.", unit, strings.Join(UnitList, ","))
 			}
 
 			fromAddress := viper.GetString("from")
 			if fromAddress == "" || !common.IsHexAddress(fromAddress) {
-				fmt.Println("Error: not set from address of owner")
-				fmt.Println(cmd.UsageString())
-				return
+				fmt.Fprint(os.Stderr, cmd.UsageString())
+				return newErrorf(ErrCodeInvalidArgument, "Error: not set from address of owner")
 			}
 
 			owners, _ := cmd.Flags().GetString("owners")
 			if owners == "" {
-				fmt.Println("Error: not set owners")
-				fmt.Println(cmd.UsageString())
-				return
+				fmt.Fprint(os.Stderr, cmd.UsageString())
+				return newErrorf(ErrCodeInvalidArgument, "Error: not set owners")
 			}
 
 			required, _ := cmd.Flags().GetInt64("required")
 			if required <= 0 || required > 50 {
-				fmt.Println("Error: not set required")
-				fmt.Println(cmd.UsageString())
-				return
+				fmt.Fprint(os.Stderr, cmd.UsageString())
+				return newErrorf(ErrCodeInvalidArgument, "Error: not set required")
 			}
 			requiredBig := big.NewInt(required)
 
@@ -60,13 +55,11 @@ func (cli *CLI) buildDeployCmd() *cobra.Command {
 			dailyLimitStr, _ := cmd.Flags().GetString("dailylimit")
 			if dailyLimitStr != "" {
 				if !IsUintString(dailyLimitStr) {
-					fmt.Printf("DailyLimitStr(%v) illegal\n", dailyLimitStr)
-					return
+					return newErrorf(ErrCodeInvalidArgument, "DailyLimitStr(%v) illegal", dailyLimitStr)
 				}
 				dailyLimitWei, err = getAmountWei(dailyLimitStr, unit)
 				if err != nil {
-					fmt.Printf("Get amount error(%v): %v\n", err, dailyLimitStr)
-					return
+					return newErrorf(ErrCodeInvalidArgument, "Get amount error(%v): %v", err, dailyLimitStr)
 				}
 			}
 
@@ -75,32 +68,32 @@ func (cli *CLI) buildDeployCmd() *cobra.Command {
 			ownercheck := make(map[string]bool)
 			for _, owner := range ownerList {
 				if !common.IsHexAddress(owner) {
-					fmt.Printf("Error: address of owner(%v) illegal\n", owner)
-					return
+					return newErrorf(ErrCodeInvalidArgument, "Error: address of owner(%v) illegal", owner)
 				}
 				if ownercheck[owner] {
-					fmt.Printf("Error: repeated owner(%s)\n", owner)
-					return
+					return newErrorf(ErrCodeInvalidArgument, "Error: repeated owner(%s)", owner)
 				}
 				ownercheck[owner] = true
 				ownerlist = append(ownerlist, common.HexToAddress(owner))
 			}
 			if len(ownerlist) < int(required) {
-				fmt.Printf("Required(%v) is greater than the number (%v) of owners\n", required, len(ownerList))
-				return
+				return newErrorf(ErrCodeInvalidArgument, "Required(%v) is greater than the number (%v) of owners", required, len(ownerList))
 			}
 
 			if cli.contractAddress == "" {
 				save = true
 			}
 			if err := cli.Deploy(fromAddress, ownerlist, requiredBig, dailyLimitWei); err != nil {
-				fmt.Println(err)
-				return
+				return err
 			}
 
 			if save {
-				viper.WriteConfigAs(cli.config)
+				if err := viper.WriteConfigAs(cli.config); err != nil {
+					return newError(ErrCodeFile, err)
+				}
 			}
+
+			return nil
 		},
 	}
 
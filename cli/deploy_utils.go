@@ -1,4 +1,3 @@
-//go:generate abigen --sol contract/MultiSigWalletWithDailyLimit.sol --pkg cli --out cli/MultiSigWalletWithDailyLimit.go
 package cli
 
 import (
@@ -9,6 +8,7 @@ import (
 
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/newtonproject/MultiSignatureWallet/msw"
 	"github.com/spf13/viper"
 )
 
@@ -16,9 +16,9 @@ import (
 func (cli *CLI) Deploy(address string, owners []common.Address, required, dailyLimit *big.Int) error {
 	var err error
 
-	opts, err := cli.getTransactOpts(address)
+	opts, err := cli.getTransactOpts(address, GasActionDeploy)
 	if err != nil {
-		return fmt.Errorf("getTransactOpts error(%s)", err)
+		return newErrorf(ErrCodeWallet, "getTransactOpts error(%s)", err)
 	}
 
 	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Minute)
@@ -26,16 +26,17 @@ func (cli *CLI) Deploy(address string, owners []common.Address, required, dailyL
 	opts.Context = ctx
 
 	if err := cli.BuildClient(); err != nil {
-		return fmt.Errorf("Build client error(%s)", err)
+		return newErrorf(ErrCodeRPC, "Build client error(%s)", err)
 	}
 	client := cli.client
-	contractAddress, tx, _, err := DeployMultiSigWalletWithDailyLimit(opts, client, owners, required, dailyLimit)
+	contractAddress, tx, _, err := msw.DeployMultiSigWalletWithDailyLimit(opts, client, owners, required, dailyLimit)
 	if err != nil {
-		return fmt.Errorf("DeployContract error(%s)", err)
+		return newErrorf(ErrCodeTxFailed, "DeployContract error(%s)", err)
 	}
 
 	fmt.Printf("Contract deploy: %s\n", contractAddress.String())
 	fmt.Printf("Transaction waiting to be mined: 0x%x\n", tx.Hash())
+	cli.journalTx(tx, opts.From)
 	cli.contractAddress = contractAddress.String()
 	viper.Set("contractaddress", cli.contractAddress)
 	bind.WaitDeployed(opts.Context, client, tx)
@@ -0,0 +1,153 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/newtonproject/MultiSignatureWallet/msw"
+	"github.com/peterh/liner"
+)
+
+// Error codes of JSON output
+const (
+	ErrCodeGeneral          = "error"
+	ErrCodeInvalidArgument  = "invalid_argument"
+	ErrCodeNotOwner         = "not_owner"
+	ErrCodeTxNotFound       = "tx_not_found"
+	ErrCodeAlreadyExecuted  = "already_executed"
+	ErrCodeAlreadyConfirmed = "already_confirmed"
+	ErrCodeNotConfirmed     = "not_confirmed"
+	ErrCodeRPC              = "rpc_error"
+	ErrCodeWallet           = "wallet_error"
+	ErrCodeFile             = "file_error"
+	ErrCodeCache            = "cache_error"
+	ErrCodeTxFailed         = "tx_failed"
+	ErrCodeUserAbort        = "user_abort"
+	ErrCodeIntentMismatch   = "intent_mismatch"
+	ErrCodeVerifyFailed     = "verify_failed"
+	ErrCodePreflightFailed  = "preflight_failed"
+	ErrCodeSimulationFailed = "simulation_failed"
+	ErrCodeNonceReplaced    = "nonce_replaced"
+)
+
+// Exit codes of the commandline, one for each error code
+const (
+	ExitOK               = 0
+	ExitGeneral          = 1
+	ExitInvalidArgument  = 2
+	ExitNotOwner         = 3
+	ExitTxNotFound       = 4
+	ExitAlreadyExecuted  = 5
+	ExitAlreadyConfirmed = 6
+	ExitNotConfirmed     = 7
+	ExitRPC              = 8
+	ExitWallet           = 9
+	ExitFile             = 10
+	ExitCache            = 11
+	ExitTxFailed         = 12
+	ExitUserAbort        = 13
+	ExitIntentMismatch   = 14
+	ExitVerifyFailed     = 15
+	ExitPreflightFailed  = 16
+	ExitSimulationFailed = 17
+	ExitNonceReplaced    = 18
+)
+
+var exitCodes = map[string]int{
+	ErrCodeGeneral:          ExitGeneral,
+	ErrCodeInvalidArgument:  ExitInvalidArgument,
+	ErrCodeNotOwner:         ExitNotOwner,
+	ErrCodeTxNotFound:       ExitTxNotFound,
+	ErrCodeAlreadyExecuted:  ExitAlreadyExecuted,
+	ErrCodeAlreadyConfirmed: ExitAlreadyConfirmed,
+	ErrCodeNotConfirmed:     ExitNotConfirmed,
+	ErrCodeRPC:              ExitRPC,
+	ErrCodeWallet:           ExitWallet,
+	ErrCodeFile:             ExitFile,
+	ErrCodeCache:            ExitCache,
+	ErrCodeTxFailed:         ExitTxFailed,
+	ErrCodeUserAbort:        ExitUserAbort,
+	ErrCodeIntentMismatch:   ExitIntentMismatch,
+	ErrCodeVerifyFailed:     ExitVerifyFailed,
+	ErrCodePreflightFailed:  ExitPreflightFailed,
+	ErrCodeSimulationFailed: ExitSimulationFailed,
+	ErrCodeNonceReplaced:    ExitNonceReplaced,
+}
+
+// cliError is an error with the code of its kind
+type cliError struct {
+	Code string
+	Err  error
+}
+
+func (e *cliError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *cliError) Unwrap() error {
+	return e.Err
+}
+
+// newError returns err with code, the code of err is kept if it has one
+func newError(code string, err error) error {
+	var e *cliError
+	if errors.As(err, &e) {
+		return err
+	}
+	return &cliError{Code: code, Err: err}
+}
+
+// newErrorf formats the error with code, the code of the error wrapped
+// with %w is kept
+func newErrorf(code, format string, args ...interface{}) error {
+	return newError(code, fmt.Errorf(format, args...))
+}
+
+// errorCode returns the code of err, ErrCodeGeneral if it has none
+func errorCode(err error) string {
+	var e *cliError
+	if errors.As(err, &e) {
+		return e.Code
+	}
+	return ErrCodeGeneral
+}
+
+// ExitCode returns the exit code of the commandline for err
+func ExitCode(err error) int {
+	if err == nil {
+		return ExitOK
+	}
+	if code, ok := exitCodes[errorCode(err)]; ok {
+		return code
+	}
+	return ExitGeneral
+}
+
+// promptError returns the error of prompt, Ctrl-C and EOF are user abort
+func promptError(err error) error {
+	if err == liner.ErrPromptAborted || err == io.EOF {
+		return newErrorf(ErrCodeUserAbort, "aborted by user")
+	}
+	return err
+}
+
+// walletError returns the error of msw with its code
+func walletError(err error) error {
+	var txErr *msw.TxError
+	switch {
+	case errors.Is(err, msw.ErrNotOwner):
+		return newError(ErrCodeNotOwner, err)
+	case errors.Is(err, msw.ErrTxNotFound):
+		return newError(ErrCodeTxNotFound, err)
+	case errors.Is(err, msw.ErrAlreadyExecuted):
+		return newError(ErrCodeAlreadyExecuted, err)
+	case errors.Is(err, msw.ErrAlreadyConfirmed):
+		return newError(ErrCodeAlreadyConfirmed, err)
+	case errors.Is(err, msw.ErrNotConfirmed):
+		return newError(ErrCodeNotConfirmed, err)
+	case errors.As(err, &txErr):
+		return newError(ErrCodeTxFailed, err)
+	}
+	return newError(ErrCodeRPC, err)
+}
@@ -0,0 +1,45 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/newtonproject/MultiSignatureWallet/msw"
+)
+
+func TestExitCode(t *testing.T) {
+	tests := []struct {
+		err  error
+		code string
+		exit int
+	}{
+		{nil, ErrCodeGeneral, ExitOK},
+		{errors.New("plain"), ErrCodeGeneral, ExitGeneral},
+		{newErrorf(ErrCodeNotOwner, "not owner"), ErrCodeNotOwner, ExitNotOwner},
+		{newErrorf(ErrCodeTxFailed, "failed: %w", newErrorf(ErrCodeUserAbort, "aborted")), ErrCodeUserAbort, ExitUserAbort},
+		{fmt.Errorf("wrapped: %w", newErrorf(ErrCodeRPC, "dial")), ErrCodeRPC, ExitRPC},
+		{newError(ErrCodeGeneral, newErrorf(ErrCodeCache, "cache")), ErrCodeCache, ExitCache},
+	}
+
+	for i, test := range tests {
+		if test.err != nil {
+			if code := errorCode(test.err); code != test.code {
+				t.Errorf("%d: errorCode got %s, want %s", i, code, test.code)
+			}
+		}
+		if exit := ExitCode(test.err); exit != test.exit {
+			t.Errorf("%d: ExitCode got %d, want %d", i, exit, test.exit)
+		}
+	}
+}
+
+func TestTxError(t *testing.T) {
+	gasFail := &msw.TxError{Method: "ConfirmTransaction", Err: errors.New("failed to estimate gas needed: gas required exceeds allowance or always failing transaction")}
+	if code := errorCode(txError(gasFail)); code != ErrCodeTxFailed {
+		t.Fatalf("failure of estimating gas got code %s, want %s", code, ErrCodeTxFailed)
+	}
+	if code := errorCode(txError(fmt.Errorf("%w: ID(1)", msw.ErrAlreadyConfirmed))); code != ErrCodeAlreadyConfirmed {
+		t.Fatalf("already confirmed got code %s", code)
+	}
+}
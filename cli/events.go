@@ -0,0 +1,143 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/spf13/cobra"
+)
+
+func (cli *CLI) buildEventsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:                   "events [--start block] [--end block] [--type Submission,Confirmation] [--id transactionID] [--sender address] [-u NEW|WEI]",
+		Short:                 "Show the history events of contract wallet",
+		Long:                  "Scan the block range in chunks and show the decoded events of contract wallet with block time and transaction hash",
+		Args:                  cobra.MinimumNArgs(0),
+		DisableFlagsInUseLine: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			unit, _ := cmd.Flags().GetString("unit")
+			if unit != "" && !stringInSlice(unit, UnitList) {
+				fmt.Fprint(os.Stderr, cmd.UsageString())
+				return newErrorf(ErrCodeInvalidArgument, "Unit(%s) for invalid. Available unit: %s.", unit, strings.Join(UnitList, ","))
+			}
+
+			filter := new(eventFilter)
+
+			types, _ := cmd.Flags().GetStringSlice("type")
+			for _, t := range types {
+				if !stringInSlice(t, EventList) {
+					fmt.Fprint(os.Stderr, cmd.UsageString())
+					return newErrorf(ErrCodeInvalidArgument, "Event type(%s) invalid. Available type: %s.", t, strings.Join(EventList, ","))
+				}
+				filter.Names = append(filter.Names, t)
+			}
+
+			ids, _ := cmd.Flags().GetStringSlice("id")
+			for _, idStr := range ids {
+				id, ok := new(big.Int).SetString(idStr, 10)
+				if !ok {
+					return newErrorf(ErrCodeInvalidArgument, "transactionId(%s) Error", idStr)
+				}
+				filter.TransactionIDs = append(filter.TransactionIDs, id)
+			}
+
+			senders, _ := cmd.Flags().GetStringSlice("sender")
+			for _, sender := range senders {
+				if !common.IsHexAddress(sender) {
+					return newErrorf(ErrCodeInvalidArgument, "Error: sender address(%s) invalid", sender)
+				}
+				filter.Senders = append(filter.Senders, common.HexToAddress(sender))
+			}
+
+			start, _ := cmd.Flags().GetUint64("start")
+			end, _ := cmd.Flags().GetUint64("end")
+
+			if cached, _ := cmd.Flags().GetBool("cached"); cached {
+				return cli.showCachedEvents(start, end, filter, unit)
+			}
+
+			if _, err := cli.GetSimpleRegistry(); err != nil {
+				fmt.Fprint(os.Stderr, cmd.UsageString())
+				return newErrorf(ErrCodeRPC, "GetSimpleRegistry Error: %v", err)
+			}
+
+			ctx := context.Background()
+			if end == 0 {
+				header, err := cli.client.HeaderByNumber(ctx, nil)
+				if err != nil {
+					return newErrorf(ErrCodeRPC, "Get latest block error: %v", err)
+				}
+				end = header.Number.Uint64()
+			}
+			if start > end {
+				return newErrorf(ErrCodeInvalidArgument, "Error: start block(%d) is greater than end block(%d)", start, end)
+			}
+			chunk, _ := cmd.Flags().GetUint64("chunk")
+
+			events, err := cli.filterEvents(ctx, start, end, chunk, filter)
+			if err != nil {
+				return newErrorf(ErrCodeRPC, "Error: %v", err)
+			}
+			if len(events) == 0 {
+				fmt.Printf("NO matching event in blocks [%d, %d]\n", start, end)
+				return nil
+			}
+
+			printEvents(events, unit)
+			return nil
+		},
+	}
+
+	cmd.Flags().Uint64("start", 0, "the `block` number to start scanning from")
+	cmd.Flags().Uint64("end", 0, "the `block` number to stop scanning at, default is the latest block")
+	cmd.Flags().Uint64("chunk", defaultEventsChunkSize, "the `number` of blocks to scan in each request")
+	cmd.Flags().StringSlice("type", nil, fmt.Sprintf("only show events of `type`s, separated by commas(,). Available type: %s", strings.Join(EventList, ",")))
+	cmd.Flags().StringSlice("id", nil, "only show events of transaction `ID`s, separated by commas(,)")
+	cmd.Flags().StringSlice("sender", nil, "only show events sent by `address`es, separated by commas(,)")
+	cmd.Flags().Bool("cached", false, "read from local cache, use sync to update it")
+	cmd.Flags().StringP("unit", "u", "", fmt.Sprintf("unit for value. %s.", fmt.Sprintf("Available unit: %s", strings.Join(UnitList, ","))))
+
+	return cmd
+}
+
+func printEvents(events []*walletEvent, unit string) {
+	for _, e := range events {
+		fmt.Printf("%d %s %s %s\n", e.BlockNumber, e.BlockTime.Format("2006-01-02 15:04:05"), e.TxHash.String(), e.Text(unit))
+	}
+}
+
+func (cli *CLI) showCachedEvents(start, end uint64, filter *eventFilter, unit string) error {
+	cache, err := cli.openCache()
+	if err != nil {
+		return newErrorf(ErrCodeCache, "Error: %v", err)
+	}
+	defer cache.Close()
+
+	info, err := cache.Info()
+	if err != nil {
+		return newErrorf(ErrCodeCache, "Error: %v", err)
+	}
+	if end == 0 || end > info.SyncedBlock {
+		end = info.SyncedBlock
+	}
+	if start > end {
+		return newErrorf(ErrCodeInvalidArgument, "Error: start block(%d) is greater than end block(%d)", start, end)
+	}
+
+	events, err := cache.Events(start, end)
+	if err != nil {
+		return newErrorf(ErrCodeCache, "Error: %v", err)
+	}
+	events = filterEventsLocal(events, filter)
+	if len(events) == 0 {
+		fmt.Printf("NO matching event in blocks [%d, %d]\n", start, end)
+		return nil
+	}
+
+	printEvents(events, unit)
+	return nil
+}
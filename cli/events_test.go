@@ -0,0 +1,59 @@
+package cli
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestEvents(t *testing.T) {
+	cli := NewCLI()
+
+	cli.TestCommand("events")
+	cli.TestCommand("events --start 100 --end 200 --type Submission,Confirmation")
+	cli.TestCommand("events --id 1 --sender 0xdDeB86Dd09F16316B67322199E288d7AF35E0806")
+}
+
+func TestFilterEventsLocalBySender(t *testing.T) {
+	owner := common.HexToAddress("0xdDeB86Dd09F16316B67322199E288d7AF35E0806")
+	other := common.HexToAddress("0x9B3deA9C636BA262f870f98a1c64d444BF0f6544")
+	tx1, tx2 := common.HexToHash("0x01"), common.HexToHash("0x02")
+	events := []*walletEvent{
+		{Name: EventSubmission, TxHash: tx1, TransactionID: big.NewInt(0)},
+		{Name: EventConfirmation, TxHash: tx1, TransactionID: big.NewInt(0), Sender: owner},
+		{Name: EventSubmission, TxHash: tx2, TransactionID: big.NewInt(1)},
+		{Name: EventConfirmation, TxHash: tx2, TransactionID: big.NewInt(1), Sender: other},
+	}
+
+	// the Confirmation selects the Submission, but is not shown
+	filtered := filterEventsLocal(events, &eventFilter{Names: []string{EventSubmission}, Senders: []common.Address{owner}})
+	if len(filtered) != 1 || filtered[0] != events[0] {
+		t.Fatalf("got events %v", filtered)
+	}
+
+	filtered = filterEventsLocal(events, &eventFilter{Senders: []common.Address{owner}})
+	if len(filtered) != 2 || filtered[0] != events[0] || filtered[1] != events[1] {
+		t.Fatalf("got events %v", filtered)
+	}
+}
+
+func TestFilterEventsByTxSender(t *testing.T) {
+	owner := common.HexToAddress("0xdDeB86Dd09F16316B67322199E288d7AF35E0806")
+	other := common.HexToAddress("0x9B3deA9C636BA262f870f98a1c64d444BF0f6544")
+	tx1, tx2 := common.HexToHash("0x01"), common.HexToHash("0x02")
+	// owner only called executeTransaction in tx1, confirmed before
+	events := []*walletEvent{
+		{Name: EventExecution, TxHash: tx1, TransactionID: big.NewInt(0)},
+		{Name: EventExecutionFailure, TxHash: tx2, TransactionID: big.NewInt(1)},
+	}
+	txSenders := map[common.Hash]common.Address{tx1: owner, tx2: other}
+
+	if filtered := filterEventsBySender(events, []common.Address{owner}, nil, nil); len(filtered) != 0 {
+		t.Fatalf("got events %v without senders of transactions", filtered)
+	}
+	filtered := filterEventsBySender(events, []common.Address{owner}, nil, txSenders)
+	if len(filtered) != 1 || filtered[0] != events[0] {
+		t.Fatalf("got events %v", filtered)
+	}
+}
@@ -0,0 +1,380 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+	"time"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/newtonproject/MultiSignatureWallet/msw"
+)
+
+// event names of msw.MultiSigWalletWithDailyLimit
+const (
+	EventSubmission        = "Submission"
+	EventConfirmation      = "Confirmation"
+	EventRevocation        = "Revocation"
+	EventExecution         = "Execution"
+	EventExecutionFailure  = "ExecutionFailure"
+	EventDeposit           = "Deposit"
+	EventOwnerAddition     = "OwnerAddition"
+	EventOwnerRemoval      = "OwnerRemoval"
+	EventRequirementChange = "RequirementChange"
+	EventDailyLimitChange  = "DailyLimitChange"
+)
+
+// EventList is array for all event names of the wallet
+var EventList = []string{
+	EventSubmission,
+	EventConfirmation,
+	EventRevocation,
+	EventExecution,
+	EventExecutionFailure,
+	EventDeposit,
+	EventOwnerAddition,
+	EventOwnerRemoval,
+	EventRequirementChange,
+	EventDailyLimitChange,
+}
+
+const defaultEventsChunkSize = 5000
+
+// walletEvent is a decoded wallet event with the block it was emitted in
+type walletEvent struct {
+	Name          string         `json:"name"`
+	TransactionID *big.Int       `json:"transactionId,omitempty"` // Submission, Confirmation, Revocation, Execution, ExecutionFailure
+	Sender        common.Address `json:"sender"`                  // Confirmation, Revocation, Deposit
+	Owner         common.Address `json:"owner"`                   // OwnerAddition, OwnerRemoval
+	Value         *big.Int       `json:"value,omitempty"`         // Deposit, RequirementChange, DailyLimitChange
+
+	BlockNumber uint64      `json:"blockNumber"`
+	BlockTime   time.Time   `json:"blockTime"`
+	TxHash      common.Hash `json:"txHash"`
+	Index       uint        `json:"index"`
+	Removed     bool        `json:"removed"` // removed due to chain reorganisation
+}
+
+// eventFilter selects which events to return
+type eventFilter struct {
+	Names          []string
+	TransactionIDs []*big.Int
+	Senders        []common.Address
+}
+
+func newWalletEvent(name string, log types.Log) *walletEvent {
+	return &walletEvent{
+		Name:        name,
+		BlockNumber: log.BlockNumber,
+		TxHash:      log.TxHash,
+		Index:       log.Index,
+		Removed:     log.Removed,
+	}
+}
+
+// toWalletEvent converts the event struct of binding to walletEvent
+func toWalletEvent(v interface{}) *walletEvent {
+	var e *walletEvent
+	switch ev := v.(type) {
+	case *msw.MultiSigWalletWithDailyLimitSubmission:
+		e = newWalletEvent(EventSubmission, ev.Raw)
+		e.TransactionID = ev.TransactionId
+	case *msw.MultiSigWalletWithDailyLimitConfirmation:
+		e = newWalletEvent(EventConfirmation, ev.Raw)
+		e.TransactionID = ev.TransactionId
+		e.Sender = ev.Sender
+	case *msw.MultiSigWalletWithDailyLimitRevocation:
+		e = newWalletEvent(EventRevocation, ev.Raw)
+		e.TransactionID = ev.TransactionId
+		e.Sender = ev.Sender
+	case *msw.MultiSigWalletWithDailyLimitExecution:
+		e = newWalletEvent(EventExecution, ev.Raw)
+		e.TransactionID = ev.TransactionId
+	case *msw.MultiSigWalletWithDailyLimitExecutionFailure:
+		e = newWalletEvent(EventExecutionFailure, ev.Raw)
+		e.TransactionID = ev.TransactionId
+	case *msw.MultiSigWalletWithDailyLimitDeposit:
+		e = newWalletEvent(EventDeposit, ev.Raw)
+		e.Sender = ev.Sender
+		e.Value = ev.Value
+	case *msw.MultiSigWalletWithDailyLimitOwnerAddition:
+		e = newWalletEvent(EventOwnerAddition, ev.Raw)
+		e.Owner = ev.Owner
+	case *msw.MultiSigWalletWithDailyLimitOwnerRemoval:
+		e = newWalletEvent(EventOwnerRemoval, ev.Raw)
+		e.Owner = ev.Owner
+	case *msw.MultiSigWalletWithDailyLimitRequirementChange:
+		e = newWalletEvent(EventRequirementChange, ev.Raw)
+		e.Value = ev.Required
+	case *msw.MultiSigWalletWithDailyLimitDailyLimitChange:
+		e = newWalletEvent(EventDailyLimitChange, ev.Raw)
+		e.Value = ev.DailyLimit
+	}
+
+	return e
+}
+
+// Text returns the one line description of the event
+func (e *walletEvent) Text(unit string) string {
+	var args []string
+	if e.TransactionID != nil {
+		args = append(args, fmt.Sprintf("transactionId: %s", e.TransactionID.String()))
+	}
+	if e.Sender != (common.Address{}) {
+		args = append(args, fmt.Sprintf("sender: %s", e.Sender.String()))
+	}
+	if e.Owner != (common.Address{}) {
+		args = append(args, fmt.Sprintf("owner: %s", e.Owner.String()))
+	}
+	if e.Value != nil {
+		switch e.Name {
+		case EventRequirementChange:
+			args = append(args, fmt.Sprintf("required: %s", e.Value.String()))
+		case EventDailyLimitChange:
+			args = append(args, fmt.Sprintf("dailyLimit: %s", getWeiAmountTextUnitByUnit(e.Value, unit)))
+		default:
+			args = append(args, fmt.Sprintf("value: %s", getWeiAmountTextUnitByUnit(e.Value, unit)))
+		}
+	}
+
+	return fmt.Sprintf("%s(%s)", e.Name, strings.Join(args, ", "))
+}
+
+// blockRanges splits [start, end] into ranges with at most size blocks
+func blockRanges(start, end, size uint64) [][2]uint64 {
+	var ranges [][2]uint64
+	if size == 0 {
+		size = defaultEventsChunkSize
+	}
+	for from := start; from <= end; from += size {
+		to := from + size - 1
+		if to > end || to < from {
+			to = end
+		}
+		ranges = append(ranges, [2]uint64{from, to})
+		if to == end {
+			break
+		}
+	}
+	return ranges
+}
+
+// filterEvents gets the wallet events between the blocks start and end,
+// sorted by the order they were emitted. The logs of all the events wanted
+// are got with one eth_getLogs of each chunk of blocks.
+func (cli *CLI) filterEvents(ctx context.Context, start, end, chunkSize uint64, filter *eventFilter) ([]*walletEvent, error) {
+	if _, err := cli.GetSimpleRegistry(); err != nil {
+		return nil, err
+	}
+	parsed, _, err := parsedABIs()
+	if err != nil {
+		return nil, err
+	}
+	wallet := common.HexToAddress(cli.contractAddress)
+
+	names := filter.Names
+	if len(names) == 0 {
+		names = EventList
+	} else if len(filter.Senders) > 0 && !stringInSlice(EventConfirmation, names) {
+		// the Confirmation of senders selects the Submission and Execution
+		names = append(append([]string{}, names...), EventConfirmation)
+	}
+	var topics []common.Hash
+	for _, name := range names {
+		if len(filter.TransactionIDs) > 0 && !eventHasTransactionID(name) {
+			continue
+		}
+		event, ok := parsed.Events[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown event %s", name)
+		}
+		topics = append(topics, event.Id())
+	}
+	if len(topics) == 0 {
+		return nil, nil
+	}
+
+	var events []*walletEvent
+	for _, r := range blockRanges(start, end, chunkSize) {
+		logs, err := cli.client.FilterLogs(ctx, ethereum.FilterQuery{
+			FromBlock: new(big.Int).SetUint64(r[0]),
+			ToBlock:   new(big.Int).SetUint64(r[1]),
+			Addresses: []common.Address{wallet},
+			Topics:    [][]common.Hash{topics},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("filter logs in blocks [%d, %d] error: %v", r[0], r[1], err)
+		}
+		logPtrs := make([]*types.Log, len(logs))
+		for i := range logs {
+			logPtrs[i] = &logs[i]
+		}
+		evs, err := decodeWalletLogs(logPtrs, wallet)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, evs...)
+	}
+
+	events = keepEventsOfIDs(events, filter.TransactionIDs)
+	txSenders, err := cli.getTxSenders(ctx, events, filter.Senders)
+	if err != nil {
+		return nil, err
+	}
+	events = filterEventsBySender(events, filter.Senders, filter.Names, txSenders)
+
+	sort.SliceStable(events, func(i, j int) bool {
+		if events[i].BlockNumber != events[j].BlockNumber {
+			return events[i].BlockNumber < events[j].BlockNumber
+		}
+		return events[i].Index < events[j].Index
+	})
+
+	if err := cli.fillEventsBlockTime(ctx, events); err != nil {
+		return nil, err
+	}
+
+	return events, nil
+}
+
+func eventHasTransactionID(name string) bool {
+	switch name {
+	case EventSubmission, EventConfirmation, EventRevocation, EventExecution, EventExecutionFailure:
+		return true
+	}
+	return false
+}
+
+// getTxSenders recovers the sender of the transactions of the events with
+// no sender, such as the Execution by executeTransaction, which are not
+// selected by an event of senders in the same transaction
+func (cli *CLI) getTxSenders(ctx context.Context, events []*walletEvent, senders []common.Address) (map[common.Hash]common.Address, error) {
+	if len(senders) == 0 {
+		return nil, nil
+	}
+
+	selected := make(map[common.Hash]bool)
+	for _, e := range events {
+		if hasAddress(senders, e.Sender) {
+			selected[e.TxHash] = true
+		}
+	}
+
+	txSenders := make(map[common.Hash]common.Address)
+	for _, e := range events {
+		if e.Sender != (common.Address{}) || selected[e.TxHash] {
+			continue
+		}
+		if _, ok := txSenders[e.TxHash]; ok {
+			continue
+		}
+		tx, _, err := cli.client.TransactionByHash(ctx, e.TxHash)
+		if err != nil {
+			return nil, fmt.Errorf("get transaction %s error: %v", e.TxHash.String(), err)
+		}
+		from, err := types.Sender(types.NewEIP155Signer(tx.ChainId()), tx)
+		if err != nil {
+			return nil, fmt.Errorf("recover sender of transaction %s error: %v", e.TxHash.String(), err)
+		}
+		txSenders[e.TxHash] = from
+	}
+
+	return txSenders, nil
+}
+
+// filterEventsLocal applies filter to the events already fetched. The
+// senders of the transactions are not known, so the Execution and
+// ExecutionFailure are selected by a Confirmation of the senders in the same
+// transaction only.
+func filterEventsLocal(events []*walletEvent, filter *eventFilter) []*walletEvent {
+	return filterEventsBySender(keepEventsOfIDs(events, filter.TransactionIDs), filter.Senders, filter.Names, nil)
+}
+
+// keepEventsOfIDs keeps the events of transaction ids, or all if ids is empty
+func keepEventsOfIDs(events []*walletEvent, ids []*big.Int) []*walletEvent {
+	if len(ids) == 0 {
+		return events
+	}
+	var filtered []*walletEvent
+	for _, e := range events {
+		if e.TransactionID == nil {
+			continue
+		}
+		for _, id := range ids {
+			if id.Cmp(e.TransactionID) == 0 {
+				filtered = append(filtered, e)
+				break
+			}
+		}
+	}
+	return filtered
+}
+
+// filterEventsBySender keeps the events sent by senders, then the events of
+// names if not empty. Submission and Execution carry no sender, so they are
+// kept if a Confirmation of one of the senders is in the same transaction,
+// the Confirmation is needed even if not in names, or if the sender of the
+// transaction in txSenders is one of the senders.
+func filterEventsBySender(events []*walletEvent, senders []common.Address, names []string, txSenders map[common.Hash]common.Address) []*walletEvent {
+	if len(senders) == 0 {
+		return keepEventsOfNames(events, names)
+	}
+
+	txHashes := make(map[common.Hash]bool)
+	for _, e := range events {
+		if hasAddress(senders, e.Sender) {
+			txHashes[e.TxHash] = true
+		}
+	}
+	for hash, from := range txSenders {
+		if hasAddress(senders, from) {
+			txHashes[hash] = true
+		}
+	}
+
+	var filtered []*walletEvent
+	for _, e := range events {
+		if txHashes[e.TxHash] && (e.Sender == (common.Address{}) || hasAddress(senders, e.Sender)) {
+			filtered = append(filtered, e)
+		}
+	}
+
+	return keepEventsOfNames(filtered, names)
+}
+
+// keepEventsOfNames keeps the events of names, or all if names is empty
+func keepEventsOfNames(events []*walletEvent, names []string) []*walletEvent {
+	if len(names) == 0 {
+		return events
+	}
+	var filtered []*walletEvent
+	for _, e := range events {
+		if stringInSlice(e.Name, names) {
+			filtered = append(filtered, e)
+		}
+	}
+	return filtered
+}
+
+// fillEventsBlockTime sets the block time of events, one header per block
+func (cli *CLI) fillEventsBlockTime(ctx context.Context, events []*walletEvent) error {
+	times := make(map[uint64]time.Time)
+	for _, e := range events {
+		t, ok := times[e.BlockNumber]
+		if !ok {
+			header, err := cli.client.HeaderByNumber(ctx, new(big.Int).SetUint64(e.BlockNumber))
+			if err != nil {
+				return fmt.Errorf("get header of block %d error: %v", e.BlockNumber, err)
+			}
+			t = time.Unix(int64(header.Time), 0)
+			times[e.BlockNumber] = t
+		}
+		e.BlockTime = t
+	}
+
+	return nil
+}
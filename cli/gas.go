@@ -0,0 +1,227 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/spf13/viper"
+)
+
+// the actions of the gas policy, the fixed gas limit is set by action
+const (
+	GasActionDeploy  = "deploy"
+	GasActionSubmit  = "submit"
+	GasActionConfirm = "confirm"
+	GasActionRevoke  = "revoke"
+	GasActionExecute = "execute"
+)
+
+// GasActionList is the list of actions of the gas policy
+var GasActionList = []string{GasActionDeploy, GasActionSubmit, GasActionConfirm, GasActionRevoke, GasActionExecute}
+
+const (
+	defaultGasMultiplier       = 10
+	defaultDeployGasMultiplier = 1
+	defaultGasPriceMultiplier  = 1
+)
+
+// GasPolicy chooses the gas limit and the gas price of the transactions
+// sent or built by the CLI
+type GasPolicy struct {
+	// Multiplier multiplies the estimated gas, DeployMultiplier for the
+	// contract creation, the gas limit is at most Cap if Cap is not 0
+	Multiplier       float64
+	DeployMultiplier float64
+	Cap              uint64
+	// Limit is the fixed gas limit of all the actions if not 0, otherwise
+	// Limits is the fixed gas limit by action
+	Limit  uint64
+	Limits map[string]uint64
+
+	// Price is the fixed gas price in WEI if not nil, otherwise the gas
+	// price of node is multiplied by PriceMultiplier
+	Price           *big.Int
+	PriceMultiplier float64
+}
+
+func defaultGasPolicy() *GasPolicy {
+	return &GasPolicy{
+		Multiplier:       defaultGasMultiplier,
+		DeployMultiplier: defaultDeployGasMultiplier,
+		Limits:           make(map[string]uint64),
+		PriceMultiplier:  defaultGasPriceMultiplier,
+	}
+}
+
+// loadGasPolicy loads the gas policy from the gas section of config and the
+// flags bound to it
+func loadGasPolicy() (*GasPolicy, error) {
+	p := defaultGasPolicy()
+	p.Multiplier = viper.GetFloat64("gas.multiplier")
+	if p.Multiplier < 1 {
+		return nil, fmt.Errorf("gas multiplier(%v) must not be less than 1", p.Multiplier)
+	}
+	p.DeployMultiplier = viper.GetFloat64("gas.deployMultiplier")
+	if p.DeployMultiplier < 1 {
+		return nil, fmt.Errorf("deploy gas multiplier(%v) must not be less than 1", p.DeployMultiplier)
+	}
+	p.PriceMultiplier = viper.GetFloat64("gas.priceMultiplier")
+	if p.PriceMultiplier <= 0 {
+		return nil, fmt.Errorf("gas price multiplier(%v) must be greater than 0", p.PriceMultiplier)
+	}
+	p.Cap = viper.GetUint64("gas.cap")
+	p.Limit = viper.GetUint64("gas.fixedLimit")
+	for _, action := range GasActionList {
+		if limit := viper.GetUint64("gas.limit." + action); limit != 0 {
+			p.Limits[action] = limit
+		}
+	}
+	if priceStr := viper.GetString("gas.price"); priceStr != "" {
+		price, ok := new(big.Int).SetString(priceStr, 10)
+		if !ok || price.Sign() < 0 {
+			return nil, fmt.Errorf("gas price(%s) invalid", priceStr)
+		}
+		p.Price = price
+	}
+
+	return p, nil
+}
+
+// fixedLimit returns the fixed gas limit of action if set
+func (p *GasPolicy) fixedLimit(action string) (uint64, bool) {
+	if p.Limit != 0 {
+		return p.Limit, true
+	}
+	limit, ok := p.Limits[action]
+	return limit, ok
+}
+
+// gasLimit returns the gas limit of action with the estimated gas, and how
+// it is chosen. The gas limit is at most blockLimit if not 0, unless fixed.
+func (p *GasPolicy) gasLimit(action string, estimate, blockLimit uint64) (uint64, string) {
+	if limit, ok := p.fixedLimit(action); ok {
+		return limit, fmt.Sprintf("fixed for %s", action)
+	}
+
+	multiplier := p.Multiplier
+	if action == GasActionDeploy {
+		multiplier = p.DeployMultiplier
+	}
+	limit := uint64(math.Ceil(float64(estimate) * multiplier))
+	how := fmt.Sprintf("estimate %d x %g", estimate, multiplier)
+	if p.Cap != 0 && limit > p.Cap {
+		limit = p.Cap
+		how += fmt.Sprintf(", capped at %d", p.Cap)
+	}
+	if blockLimit != 0 && limit > blockLimit {
+		limit = blockLimit
+		how += fmt.Sprintf(", capped at block gas limit %d", blockLimit)
+	}
+	return limit, how
+}
+
+// gasPrice returns the gas price with the gas price of node, and how it is
+// chosen
+func (p *GasPolicy) gasPrice(nodePrice *big.Int) (*big.Int, string) {
+	if p.Price != nil {
+		return new(big.Int).Set(p.Price), "fixed"
+	}
+	if nodePrice == nil {
+		nodePrice = new(big.Int)
+	}
+	if p.PriceMultiplier == 1 {
+		return new(big.Int).Set(nodePrice), "node price"
+	}
+	price, _ := new(big.Float).Mul(new(big.Float).SetInt(nodePrice), big.NewFloat(p.PriceMultiplier)).Int(nil)
+	return price, fmt.Sprintf("node price %s x %g", nodePrice.String(), p.PriceMultiplier)
+}
+
+// gasPolicy returns the gas policy loaded from config, or the default one
+func (cli *CLI) gasPolicy() *GasPolicy {
+	if cli.gas == nil {
+		cli.gas = defaultGasPolicy()
+	}
+	return cli.gas
+}
+
+// blockGasLimit returns the gas limit of the latest block, or 0 if failed
+// to get it
+func (cli *CLI) blockGasLimit(ctx context.Context) uint64 {
+	if err := cli.BuildClient(); err != nil {
+		return 0
+	}
+	header, err := cli.client.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return 0
+	}
+	return header.GasLimit
+}
+
+// applyGasPolicy sets the gas price of opts, and the gas limit of the
+// transaction of action before it is signed
+func (cli *CLI) applyGasPolicy(ctx context.Context, opts *bind.TransactOpts, action string) error {
+	if err := cli.BuildClient(); err != nil {
+		return err
+	}
+	p := cli.gasPolicy()
+
+	var nodePrice *big.Int
+	if p.Price == nil {
+		var err error
+		if nodePrice, err = cli.client.SuggestGasPrice(ctx); err != nil {
+			return fmt.Errorf("SuggestGasPrice error: %v", err)
+		}
+	}
+	var priceHow string
+	opts.GasPrice, priceHow = p.gasPrice(nodePrice)
+
+	if limit, ok := p.fixedLimit(action); ok {
+		opts.GasLimit = limit
+	}
+	blockLimit := cli.blockGasLimit(ctx)
+
+	sign := opts.Signer
+	opts.Signer = func(signer types.Signer, address common.Address, tx *types.Transaction) (*types.Transaction, error) {
+		gasLimit, limitHow := p.gasLimit(action, tx.Gas(), blockLimit)
+		if tx.To() == nil {
+			tx = types.NewContractCreation(tx.Nonce(), tx.Value(), gasLimit, tx.GasPrice(), tx.Data())
+		} else {
+			tx = types.NewTransaction(tx.Nonce(), *tx.To(), tx.Value(), gasLimit, tx.GasPrice(), tx.Data())
+		}
+		fmt.Printf("Gas policy: gasLimit %d (%s), gasPrice %s WEI (%s)\n", gasLimit, limitHow, tx.GasPrice().String(), priceHow)
+		return sign(signer, address, tx)
+	}
+
+	return nil
+}
+
+// gasAction returns the action of the gas policy of the intent, the
+// transaction without intent or of other intents submits
+func gasAction(i *Intent) string {
+	if i != nil && i.hasTxID() {
+		return i.Action
+	}
+	return GasActionSubmit
+}
+
+// maxGasFee returns the worst-case fee of gasLimit at gasPrice
+func maxGasFee(gasLimit uint64, gasPrice *big.Int) *big.Int {
+	if gasPrice == nil {
+		return new(big.Int)
+	}
+	return new(big.Int).Mul(gasPrice, new(big.Int).SetUint64(gasLimit))
+}
+
+// printMaxFee prints the worst-case fee and cost of the transaction
+func printMaxFee(gasLimit uint64, gasPrice, value *big.Int) {
+	fee := maxGasFee(gasLimit, gasPrice)
+	fmt.Println("\tMax GasFee:", getWeiAmountTextUnitByUnit(fee, UnitETH))
+	if value != nil && value.Sign() > 0 {
+		fmt.Println("\tMax Cost (GasFee + Value):", getWeiAmountTextUnitByUnit(new(big.Int).Add(fee, value), UnitETH))
+	}
+}
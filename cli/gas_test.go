@@ -0,0 +1,99 @@
+package cli
+
+import (
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/viper"
+)
+
+func TestGasPolicy(t *testing.T) {
+	p := defaultGasPolicy()
+	if limit, _ := p.gasLimit(GasActionSubmit, 50000, 0); limit != 500000 {
+		t.Fatalf("got default gas limit %d", limit)
+	}
+	if limit, _ := p.gasLimit(GasActionDeploy, 1500000, 8000000); limit != 1500000 {
+		t.Fatalf("got default deploy gas limit %d, want the estimate", limit)
+	}
+
+	p.Multiplier, p.Cap = 1.5, 70000
+	if limit, _ := p.gasLimit(GasActionConfirm, 40000, 0); limit != 60000 {
+		t.Fatalf("got gas limit %d", limit)
+	}
+	if limit, _ := p.gasLimit(GasActionConfirm, 50000, 0); limit != 70000 {
+		t.Fatalf("got capped gas limit %d", limit)
+	}
+	if limit, _ := p.gasLimit(GasActionConfirm, 40000, 55000); limit != 55000 {
+		t.Fatalf("got gas limit %d over block gas limit", limit)
+	}
+
+	p.Limits[GasActionExecute] = 300000
+	if limit, ok := p.fixedLimit(GasActionExecute); !ok || limit != 300000 {
+		t.Fatalf("got fixed gas limit %d %v", limit, ok)
+	}
+	if limit, _ := p.gasLimit(GasActionExecute, 40000, 55000); limit != 300000 {
+		t.Fatalf("got fixed gas limit %d", limit)
+	}
+	if _, ok := p.fixedLimit(GasActionSubmit); ok {
+		t.Fatal("got fixed gas limit of submit")
+	}
+
+	p.PriceMultiplier = 1.25
+	if price, _ := p.gasPrice(big.NewInt(1000)); price.Int64() != 1250 {
+		t.Fatalf("got gas price %s", price)
+	}
+	p.Price = big.NewInt(7)
+	if price, _ := p.gasPrice(big.NewInt(1000)); price.Int64() != 7 {
+		t.Fatalf("got fixed gas price %s", price)
+	}
+}
+
+func TestDeployGasPolicy(t *testing.T) {
+	defer viper.Reset()
+	defaultConfig(NewCLI())
+	p, err := loadGasPolicy()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if limit, _ := p.gasLimit(GasActionDeploy, 1500000, 8000000); limit != 1500000 {
+		t.Fatalf("got deploy gas limit %d of default config, want the estimate", limit)
+	}
+	if limit, _ := p.gasLimit(GasActionSubmit, 50000, 8000000); limit != 500000 {
+		t.Fatalf("got submit gas limit %d of default config", limit)
+	}
+}
+
+func TestBuildGasPolicy(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gas")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	out := filepath.Join(dir, "execute.tx")
+
+	// the gas limit of config is used without the flag gasLimit
+	config := filepath.Join(dir, "config.toml")
+	if err := ioutil.WriteFile(config, []byte("[gas]\nprice = \"20\"\n\n[gas.limit]\nexecute = 250000\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	defer viper.Reset()
+
+	cli := NewCLI()
+	cli.TestCommand("build execute 2 --offline --nonce 3 --chainID 1007 -c " + config + " -w " + dir +
+		" -a 0xf09E6759c2588eE8435902d16350E321CBD27af3 -f 0x9B3deA9C636BA262f870f98a1c64d444BF0f6544 --out " + out)
+
+	b, err := ioutil.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tran := new(Transaction)
+	if err := tran.UnmarshalJSON(b); err != nil {
+		t.Fatal(err)
+	}
+	if tran.GasLimit != 250000 || tran.GasPrice.Int64() != 20 {
+		t.Fatalf("got gas limit %d, gas price %v", tran.GasLimit, tran.GasPrice)
+	}
+}
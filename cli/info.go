@@ -3,7 +3,6 @@ package cli
 import (
 	"context"
 	"fmt"
-	"math/big"
 	"os"
 	"strings"
 
@@ -15,28 +14,34 @@ import (
 
 func (cli *CLI) buildInfoCmd() *cobra.Command {
 	cmd := &cobra.Command{
-		Use:                   "info [transactionID] [-a contractAddress] [-u NEW|WEI]",
+		Use:                   "info [transactionID [--timeline]] [-a contractAddress] [-u NEW|WEI]",
 		Short:                 "Show the basic info of contract wallet or a transaction ID",
 		DisableFlagsInUseLine: true,
-		Run: func(cmd *cobra.Command, args []string) {
+		RunE: func(cmd *cobra.Command, args []string) error {
 
 			if len(args) > 0 {
-				cli.showTxInfo(cmd, args)
-				return
+				return cli.showTxInfo(cmd, args)
 			}
 
 			unit, _ := cmd.Flags().GetString("unit")
 			if unit != "" && !stringInSlice(unit, UnitList) {
-				fmt.Printf("Unit(%s) for invalid. %s.\n", unit, fmt.Sprintf("Available unit: %s", strings.Join(UnitList, ",")))
 				fmt.Fprint(os.Stderr, cmd.UsageString())
-				return
+				return newErrorf(ErrCodeInvalidArgument, "Unit(%s) for invalid. Available unit: %s.", unit, strings.Join(UnitList, ","))
+			}
+
+			if cached, _ := cmd.Flags().GetBool("cached"); cached {
+				return cli.showCachedInfo(unit)
+			}
+
+			if cli.isJSON() {
+				token, _ := cmd.Flags().GetString("token")
+				return cli.printInfoJSON(token)
 			}
 
 			simpleRegistry, err := cli.GetSimpleRegistry()
 			if err != nil {
-				fmt.Println("GetSimpleRegistry Error: ", err)
-				fmt.Println(cmd.UsageString())
-				return
+				fmt.Fprint(os.Stderr, cmd.UsageString())
+				return newErrorf(ErrCodeRPC, "GetSimpleRegistry Error: %v", err)
 			}
 
 			fmt.Printf("The contract address(%s) basic information is as follows:\n", cli.contractAddress)
@@ -90,90 +95,130 @@ func (cli *CLI) buildInfoCmd() *cobra.Command {
 			if cmd.Flags().Changed("token") {
 				tokenAddressStr, err := cmd.Flags().GetString("token")
 				if err != nil {
-					fmt.Printf("Balance: get token address error\n")
-					return
+					return newErrorf(ErrCodeInvalidArgument, "Balance: get token address error")
 				}
 				tokenAddress := common.HexToAddress(tokenAddressStr)
 
-				parsed, err := abi.JSON(strings.NewReader(ERC20TransferABI))
+				token, err := cli.getTokenBalance(tokenAddress)
 				if err != nil {
-					fmt.Printf("Balance(%v): JSON err: %v\n",
-						tokenAddress.String(), err)
-					return
-				}
-
-				err = cli.BuildClient()
-				if err != nil {
-					fmt.Printf("Balance(%v): %v\n",
-						tokenAddress.String(), err)
-					return
-				}
-				erc20 := bind.NewBoundContract(tokenAddress, parsed, cli.client, cli.client, cli.client)
-
-				var decimals uint8
-				{
-					// get decimals
-					var (
-						ret0 = new(uint8)
-					)
-					out := ret0
-					err = erc20.Call(nil, out, "decimals")
-					if err != nil {
-						fmt.Printf("Balance(%v): %v\n",
-							tokenAddress.String(), err)
-						return
-					}
-
-					decimals = *out
-				}
-
-				var symbol string
-				{
-					// get name
-					var (
-						ret0 = new(string)
-					)
-					out := ret0
-					err = erc20.Call(nil, out, "symbol")
-					if err != nil {
-						fmt.Printf("Balance(%v): %v\n",
-							tokenAddress.String(), err)
-						return
-					}
-
-					symbol = *out
-				}
-
-				var balance *big.Int
-				{
-					// get balance
-					var (
-						ret0 = new(*big.Int)
-					)
-					out := ret0
-
-					err = erc20.Call(nil, out, "balanceOf", common.HexToAddress(cli.contractAddress))
-					if err != nil {
-						fmt.Printf("Balance(%v): %v\n",
-							tokenAddress.String(), err)
-						return
-					}
-
-					balance = big.NewInt(0).Set(*ret0)
+					return newErrorf(ErrCodeRPC, "Balance(%v): %v", tokenAddress.String(), err)
 				}
 
 				fmt.Printf("Balance(%v): %v %s\n",
 					tokenAddress.String(),
-					getAmountTextByWeiWithDecimals(balance, decimals),
-					symbol)
+					getAmountTextByWeiWithDecimals(token.Balance, token.Decimals),
+					token.Symbol)
 			}
 
-			return
+			return nil
 		},
 	}
 
 	cmd.Flags().StringP("unit", "u", "", fmt.Sprintf("unit for value. %s.", fmt.Sprintf("Available unit: %s", strings.Join(UnitList, ","))))
 	cmd.Flags().String("token", "", "the address of token, if set then show the balance of this token")
+	cmd.Flags().Bool("cached", false, "read from local cache, use sync to update it")
+	cmd.Flags().Bool("timeline", false, "show the timeline of the transaction ID from the events")
+	cmd.Flags().Uint64("start", 0, "the `block` number to start scanning events from for timeline, default is after the blocks cached by sync, required without the cache")
+	cmd.Flags().Uint64("chunk", defaultEventsChunkSize, "the `number` of blocks to scan in each request for timeline")
 
 	return cmd
 }
+
+// getTokenBalance reads the symbol, decimals and the balance of contract wallet of token
+func (cli *CLI) getTokenBalance(tokenAddress common.Address) (*tokenOutput, error) {
+	parsed, err := abi.JSON(strings.NewReader(ERC20TransferABI))
+	if err != nil {
+		return nil, fmt.Errorf("JSON err: %v", err)
+	}
+	if err := cli.BuildClient(); err != nil {
+		return nil, err
+	}
+	erc20 := bind.NewBoundContract(tokenAddress, parsed, cli.client, cli.client, cli.client)
+
+	token := &tokenOutput{Address: tokenAddress}
+	if err := erc20.Call(nil, &token.Decimals, "decimals"); err != nil {
+		return nil, err
+	}
+	if err := erc20.Call(nil, &token.Symbol, "symbol"); err != nil {
+		return nil, err
+	}
+	if err := erc20.Call(nil, &token.Balance, "balanceOf", common.HexToAddress(cli.contractAddress)); err != nil {
+		return nil, err
+	}
+
+	return token, nil
+}
+
+// printInfoJSON prints the basic info of contract wallet as JSON
+func (cli *CLI) printInfoJSON(token string) error {
+	simpleRegistry, err := cli.GetSimpleRegistry()
+	if err != nil {
+		return newErrorf(ErrCodeRPC, "GetSimpleRegistry Error: %v", err)
+	}
+
+	output := &walletInfoOutput{Contract: common.HexToAddress(cli.contractAddress)}
+	if output.Balance, err = cli.client.BalanceAt(context.Background(), output.Contract, nil); err != nil {
+		return newErrorf(ErrCodeRPC, "BalanceAt Error: %v", err)
+	}
+	if output.Owners, err = simpleRegistry.GetOwners(nil); err != nil {
+		return newErrorf(ErrCodeRPC, "GetOwners Error: %v", err)
+	}
+	if output.Required, err = simpleRegistry.Required(nil); err != nil {
+		return newErrorf(ErrCodeRPC, "Required Error: %v", err)
+	}
+	if output.DailyLimit, err = simpleRegistry.DailyLimit(nil); err != nil {
+		return newErrorf(ErrCodeRPC, "DailyLimit Error: %v", err)
+	}
+	if output.RemainingLimit, err = simpleRegistry.CalcMaxWithdraw(nil); err != nil {
+		return newErrorf(ErrCodeRPC, "CalcMaxWithdraw Error: %v", err)
+	}
+	if output.SpentToday, err = simpleRegistry.SpentToday(nil); err != nil {
+		return newErrorf(ErrCodeRPC, "SpentToday Error: %v", err)
+	}
+	if token != "" {
+		if output.Token, err = cli.getTokenBalance(common.HexToAddress(token)); err != nil {
+			return newErrorf(ErrCodeRPC, "Balance(%s): %v", token, err)
+		}
+	}
+
+	cli.printJSON(output)
+	return nil
+}
+
+func (cli *CLI) showCachedInfo(unit string) error {
+	cache, err := cli.openCache()
+	if err != nil {
+		return newErrorf(ErrCodeCache, "Error: %v", err)
+	}
+	defer cache.Close()
+
+	info, err := cache.Info()
+	if err != nil {
+		return newErrorf(ErrCodeCache, "Error: %v", err)
+	}
+
+	if cli.isJSON() {
+		cli.printJSON(&walletInfoOutput{
+			Contract:         info.Contract,
+			Balance:          info.Balance,
+			Owners:           info.Owners,
+			Required:         info.Required,
+			DailyLimit:       info.DailyLimit,
+			CachedBlock:      info.SyncedBlock,
+			TransactionCount: info.TransactionCount,
+		})
+		return nil
+	}
+
+	fmt.Printf("The contract address(%s) basic information is as follows (cached at block %d, %s):\n",
+		cli.contractAddress, info.SyncedBlock, info.SyncedTime.Format("2006-01-02 15:04:05"))
+	fmt.Println("Balance: ", getWeiAmountTextUnitByUnit(info.Balance, unit))
+	fmt.Println("Owners List: ")
+	for _, v := range info.Owners {
+		fmt.Println("\t", v.String())
+	}
+	fmt.Println("The number of required confirmations: ", info.Required.String())
+	fmt.Println("Daily Limit:", getWeiAmountTextUnitByUnit(info.DailyLimit, unit))
+	fmt.Println("The number of transactions: ", info.TransactionCount)
+	return nil
+}
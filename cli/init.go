@@ -15,14 +15,14 @@ func (cli *CLI) buildInitCmd() *cobra.Command {
 		Use:                   "init",
 		Short:                 "Initialize config file",
 		DisableFlagsInUseLine: true,
-		Run: func(cmd *cobra.Command, args []string) {
+		RunE: func(cmd *cobra.Command, args []string) error {
 
 			fmt.Println("Initialize config file")
 
 			prompt := fmt.Sprintf("Enter file in which to save (%s): ", defaultConfigFile)
 			configPath, err := console.Stdin.PromptInput(prompt)
 			if err != nil {
-				fmt.Println("PromptInput err:", err)
+				return promptError(err)
 			}
 			if configPath == "" {
 				configPath = defaultConfigFile
@@ -33,7 +33,7 @@ func (cli *CLI) buildInitCmd() *cobra.Command {
 			prompt = fmt.Sprintf("Enter the wallet storage directory (%s): ", walletPathV)
 			cli.walletPath, err = console.Stdin.PromptInput(prompt)
 			if err != nil {
-				fmt.Println("PromptInput err:", err)
+				return promptError(err)
 			}
 			if cli.walletPath == "" {
 				cli.walletPath = walletPathV
@@ -44,7 +44,7 @@ func (cli *CLI) buildInitCmd() *cobra.Command {
 			prompt = fmt.Sprintf("Enter %s json rpc or ipc url (%s): ", cli.bc.String(), rpcURLV)
 			cli.rpcURL, err = console.Stdin.PromptInput(prompt)
 			if err != nil {
-				fmt.Println("PromptInput err:", err)
+				return promptError(err)
 			}
 			if cli.rpcURL == "" {
 				cli.rpcURL = rpcURLV
@@ -54,7 +54,7 @@ func (cli *CLI) buildInitCmd() *cobra.Command {
 			prompt = fmt.Sprintf("Create a default account or not: [Y/n] ")
 			createNewAddress, err := console.Stdin.PromptInput(prompt)
 			if err != nil {
-				fmt.Println("PromptInput err:", err)
+				return promptError(err)
 			}
 			if len(createNewAddress) <= 0 {
 				createNewAddress = "Y"
@@ -65,6 +65,9 @@ func (cli *CLI) buildInitCmd() *cobra.Command {
 					keystore.StandardScryptN, keystore.StandardScryptP)
 
 				cli.walletPassword, err = getPassPhrase("Your new account is locked with a password. Please give a password. Do not forget this password.", true)
+				if err := promptError(err); errorCode(err) == ErrCodeUserAbort {
+					return err
+				}
 				if err == nil {
 					account, err := wallet.NewAccount(cli.walletPassword)
 					if err == nil {
@@ -86,10 +89,11 @@ func (cli *CLI) buildInitCmd() *cobra.Command {
 
 			err = viper.WriteConfigAs(configPath)
 			if err != nil {
-				fmt.Println("WriteConfig:", err)
-			} else {
-				fmt.Println("Your configuration has been saved in ", configPath)
+				return newErrorf(ErrCodeFile, "WriteConfig: %v", err)
 			}
+			fmt.Println("Your configuration has been saved in ", configPath)
+
+			return nil
 		},
 	}
 
@@ -0,0 +1,283 @@
+package cli
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/newtonproject/MultiSignatureWallet/msw"
+)
+
+// Actions of intent
+const (
+	IntentSubmit        = "submit"
+	IntentConfirm       = "confirm"
+	IntentRevoke        = "revoke"
+	IntentExecute       = "execute"
+	IntentOwnerAdd      = "ownerAdd"
+	IntentOwnerRemove   = "ownerRemove"
+	IntentOwnerReplace  = "ownerReplace"
+	IntentDailyLimit    = "dailyLimit"
+	IntentRequired      = "required"
+	IntentTokenTransfer = "tokenTransfer"
+	IntentCall          = "call"
+)
+
+var (
+	errNoIntent       = errors.New("no intent in transaction")
+	errIntentMismatch = errors.New("intent does not match data of transaction")
+	errIntentWallet   = errors.New("to of transaction is not the wallet of intent")
+)
+
+// Intent is the human-readable action of the transaction built, the data
+// of the transaction is encoded from it
+type Intent struct {
+	Action      string          `json:"action"`
+	Wallet      *common.Address `json:"wallet,omitempty"`
+	TxID        *big.Int        `json:"txID,omitempty"`
+	Destination *common.Address `json:"destination,omitempty"`
+	Amount      string          `json:"amount,omitempty"`
+	Unit        string          `json:"unit,omitempty"`
+	Data        hexutil.Bytes   `json:"data,omitempty"`
+	Token       *common.Address `json:"token,omitempty"`
+	Decimals    *uint8          `json:"decimals,omitempty"`
+	Owner       *common.Address `json:"owner,omitempty"`
+	NewOwner    *common.Address `json:"newOwner,omitempty"`
+	Required    *big.Int        `json:"required,omitempty"`
+	Call        *ContractCall   `json:"call,omitempty"`
+	Proposal    *Proposal       `json:"proposal,omitempty"`
+}
+
+// Proposal is the state of the transaction ID to confirm, revoke or execute,
+// read on the online computer when building, only for review at sign time.
+// It is not verified against the data, which encodes only the ID.
+type Proposal struct {
+	Destination   common.Address   `json:"destination"`
+	Value         *big.Int         `json:"value"`
+	Data          hexutil.Bytes    `json:"data"`
+	Executed      bool             `json:"executed"`
+	Confirmations []common.Address `json:"confirmations"`
+	Required      *big.Int         `json:"required"`
+}
+
+func newProposal(status *msw.Status) *Proposal {
+	return &Proposal{
+		Destination:   status.Destination,
+		Value:         status.Value,
+		Data:          status.Data,
+		Executed:      status.Executed,
+		Confirmations: status.Confirmations,
+		Required:      status.Required,
+	}
+}
+
+// hasTxID reports whether the action is on an existing transaction ID
+func (i *Intent) hasTxID() bool {
+	return i.Action == IntentConfirm || i.Action == IntentRevoke || i.Action == IntentExecute
+}
+
+// pack encodes the data of the transaction to the wallet
+func (i *Intent) pack(wallet common.Address) ([]byte, error) {
+	switch i.Action {
+	case IntentSubmit:
+		if i.Destination == nil {
+			return nil, errors.New("intent: destination not set")
+		}
+		value, err := getAmountWei(i.Amount, i.Unit)
+		if err != nil {
+			return nil, fmt.Errorf("intent: amount: %v", err)
+		}
+		return msw.Pack("submitTransaction", *i.Destination, value, []byte(i.Data))
+	case IntentConfirm, IntentRevoke, IntentExecute:
+		if i.TxID == nil {
+			return nil, errors.New("intent: txID not set")
+		}
+		method := map[string]string{
+			IntentConfirm: "confirmTransaction",
+			IntentRevoke:  "revokeConfirmation",
+			IntentExecute: "executeTransaction",
+		}[i.Action]
+		return msw.Pack(method, i.TxID)
+	case IntentOwnerAdd, IntentOwnerRemove:
+		if i.Owner == nil {
+			return nil, errors.New("intent: owner not set")
+		}
+		method := "addOwner"
+		if i.Action == IntentOwnerRemove {
+			method = "removeOwner"
+		}
+		return packWalletCall(wallet, method, *i.Owner)
+	case IntentOwnerReplace:
+		if i.Owner == nil || i.NewOwner == nil {
+			return nil, errors.New("intent: owner or newOwner not set")
+		}
+		return packWalletCall(wallet, "replaceOwner", *i.Owner, *i.NewOwner)
+	case IntentDailyLimit:
+		value, err := getAmountWei(i.Amount, i.Unit)
+		if err != nil {
+			return nil, fmt.Errorf("intent: amount: %v", err)
+		}
+		return packWalletCall(wallet, "changeDailyLimit", value)
+	case IntentRequired:
+		if i.Required == nil {
+			return nil, errors.New("intent: required not set")
+		}
+		return packWalletCall(wallet, "changeRequirement", i.Required)
+	case IntentTokenTransfer:
+		if i.Token == nil || i.Destination == nil || i.Decimals == nil {
+			return nil, errors.New("intent: token, destination or decimals not set")
+		}
+		amount, err := msw.ParseAmount(i.Amount, int(*i.Decimals))
+		if err != nil {
+			return nil, fmt.Errorf("intent: amount: %v", err)
+		}
+		data, err := packTokenTransfer(*i.Destination, amount)
+		if err != nil {
+			return nil, err
+		}
+		return msw.Pack("submitTransaction", *i.Token, new(big.Int), data)
+	case IntentCall:
+		if i.Destination == nil || i.Call == nil {
+			return nil, errors.New("intent: destination or call not set")
+		}
+		value, err := getAmountWei(i.Amount, i.Unit)
+		if err != nil {
+			return nil, fmt.Errorf("intent: amount: %v", err)
+		}
+		data, err := i.Call.pack()
+		if err != nil {
+			return nil, err
+		}
+		return msw.Pack("submitTransaction", *i.Destination, value, data)
+	}
+
+	return nil, fmt.Errorf("intent: unsupported action(%s)", i.Action)
+}
+
+// packWalletCall encodes the submission of the call of method to the wallet
+// itself, such as addOwner
+func packWalletCall(wallet common.Address, method string, args ...interface{}) ([]byte, error) {
+	data, err := msw.Pack(method, args...)
+	if err != nil {
+		return nil, err
+	}
+	return msw.Pack("submitTransaction", wallet, new(big.Int), data)
+}
+
+func (i *Intent) String() string {
+	switch i.Action {
+	case IntentSubmit:
+		s := fmt.Sprintf("Submit transaction, pay %s %s to %s", i.Amount, i.Unit, i.Destination.String())
+		if len(i.Data) > 0 {
+			s += fmt.Sprintf(" with data %s", i.Data.String())
+		}
+		return s
+	case IntentConfirm:
+		return fmt.Sprintf("Confirm transaction ID %s", i.TxID.String())
+	case IntentRevoke:
+		return fmt.Sprintf("Revoke the confirmation for transaction ID %s", i.TxID.String())
+	case IntentExecute:
+		return fmt.Sprintf("Execute transaction ID %s", i.TxID.String())
+	case IntentOwnerAdd:
+		return fmt.Sprintf("Submit transaction to add owner %s", i.Owner.String())
+	case IntentOwnerRemove:
+		return fmt.Sprintf("Submit transaction to remove owner %s", i.Owner.String())
+	case IntentOwnerReplace:
+		return fmt.Sprintf("Submit transaction to replace owner %s with %s", i.Owner.String(), i.NewOwner.String())
+	case IntentDailyLimit:
+		return fmt.Sprintf("Submit transaction to change daily limit to %s %s", i.Amount, i.Unit)
+	case IntentRequired:
+		return fmt.Sprintf("Submit transaction to change the number of required to %s", i.Required.String())
+	case IntentTokenTransfer:
+		return fmt.Sprintf("Submit transaction to transfer %s of token %s (decimals %d) to %s", i.Amount, i.Token.String(), *i.Decimals, i.Destination.String())
+	case IntentCall:
+		return fmt.Sprintf("Submit transaction to call %s of %s, pay %s %s", i.Call.Method, i.Destination.String(), i.Amount, i.Unit)
+	}
+	return fmt.Sprintf("Unknown action(%s)", i.Action)
+}
+
+// verifyIntent checks t is sent to the wallet of its intent, and the data of
+// t is encoded from its intent byte for byte
+func (t *Transaction) verifyIntent() error {
+	if t.Intent == nil {
+		return errNoIntent
+	}
+	if t.Intent.Wallet == nil {
+		return errors.New("intent: wallet not set, build the transaction again")
+	}
+	if t.To != *t.Intent.Wallet {
+		return errIntentWallet
+	}
+	if t.Value != nil && t.Value.Sign() != 0 {
+		return fmt.Errorf("%v: value of transaction is not 0", errIntentMismatch)
+	}
+	data, err := t.Intent.pack(*t.Intent.Wallet)
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(data, t.Data) {
+		return errIntentMismatch
+	}
+	return nil
+}
+
+// checkIntent shows the intent of the transaction to sign and verifies the
+// data against it, the transaction without intent is refused unless noIntent
+func (t *Transaction) checkIntent(noIntent bool) error {
+	err := t.verifyIntent()
+	if err == errNoIntent && noIntent {
+		fmt.Println("Warning: no intent in transaction, the data is not verified")
+		return nil
+	}
+	if err != nil {
+		return newErrorf(ErrCodeIntentMismatch, "Error: refuse to sign: %v", err)
+	}
+	fmt.Println("The data matches the intent")
+	return nil
+}
+
+// showIntent shows the intent and the proposal of the transaction ID in it
+func (t *Transaction) showIntent(unit string) {
+	if t.Intent == nil {
+		return
+	}
+	fmt.Println("The intent is:", t.Intent.String())
+	if t.Intent.Wallet != nil {
+		fmt.Println("The wallet is:", t.Intent.Wallet.String())
+	}
+	if t.Intent.Call != nil {
+		fmt.Println("The call is as follows:")
+		if err := t.Intent.Call.show("\t"); err != nil {
+			fmt.Println("\tError: decode call:", err)
+		}
+	}
+
+	p := t.Intent.Proposal
+	if p == nil {
+		if t.Intent.hasTxID() {
+			fmt.Println("Warning: no proposal details of the transaction ID, built offline")
+		}
+		return
+	}
+	fmt.Printf("Transaction ID %s proposal is as follows (UNVERIFIED, read from node when building, not covered by the data check):\n", t.Intent.TxID.String())
+	if p.Destination == t.To {
+		fmt.Printf("\tDestination Address: %s (contract itself)\n", p.Destination.String())
+	} else {
+		fmt.Println("\tDestination Address: ", p.Destination.String())
+	}
+	fmt.Println("\tValue: ", getWeiAmountTextUnitByUnit(p.Value, unit))
+	fmt.Printf("\tConfirmations: %d/%s\n", len(p.Confirmations), p.Required.String())
+	for _, v := range p.Confirmations {
+		fmt.Printf("\t\t%s\n", v.String())
+	}
+	if p.Executed {
+		fmt.Println("\tExecution status: Executed")
+	} else {
+		fmt.Println("\tExecution status: Pending")
+	}
+	fmt.Printf("\tData: %s\n", p.Data.String())
+	showDataAuto(p.Data, "\t\t", unit)
+}
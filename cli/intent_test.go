@@ -0,0 +1,123 @@
+package cli
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestVerifyIntent(t *testing.T) {
+	wallet := common.HexToAddress("0xf09E6759c2588eE8435902d16350E321CBD27af3")
+	to := common.HexToAddress("0x9B3deA9C636BA262f870f98a1c64d444BF0f6544")
+	decimals := uint8(6)
+
+	for _, setter := range []func(*Transaction){
+		func(tran *Transaction) { tran.setSubmit(to, big.NewInt(10), UnitETH, nil) },
+		func(tran *Transaction) { tran.setID(IntentConfirm, big.NewInt(7)) },
+		func(tran *Transaction) { tran.setOwner(IntentOwnerAdd, to) },
+		func(tran *Transaction) { tran.setOwnerReplace(wallet, to) },
+		func(tran *Transaction) { tran.setRequired(big.NewInt(2)) },
+		func(tran *Transaction) { tran.setTokenTransfer(wallet, to, big.NewInt(1500000), decimals) },
+	} {
+		tran := &Transaction{To: wallet}
+		setter(tran)
+		data, err := tran.packData()
+		if err != nil {
+			t.Fatal(err)
+		}
+		tran.Data = data
+		if err := tran.verifyIntent(); err != nil {
+			t.Fatalf("verify intent %s: %v", tran.Intent.String(), err)
+		}
+
+		// the same data redirected to another contract
+		tran.To = to
+		if err := tran.verifyIntent(); err != errIntentWallet {
+			t.Fatalf("verify intent %s sent to other contract got %v", tran.Intent.String(), err)
+		}
+		tran.To = wallet
+
+		tran.Data[len(tran.Data)-1] ^= 1
+		if err := tran.verifyIntent(); err != errIntentMismatch {
+			t.Fatalf("verify tampered intent %s got %v", tran.Intent.String(), err)
+		}
+	}
+
+	if err := (&Transaction{To: wallet}).verifyIntent(); err != errNoIntent {
+		t.Fatalf("verify no intent got %v", err)
+	}
+}
+
+func TestIntentProposal(t *testing.T) {
+	wallet := common.HexToAddress("0xf09E6759c2588eE8435902d16350E321CBD27af3")
+	owner := common.HexToAddress("0x9B3deA9C636BA262f870f98a1c64d444BF0f6544")
+
+	tran := &Transaction{To: wallet, Value: big.NewInt(0), Unit: UnitWEI, NetworkID: big.NewInt(1007)}
+	tran.setID(IntentConfirm, big.NewInt(7))
+	tran.Intent.Proposal = &Proposal{
+		Destination:   owner,
+		Value:         big.NewInt(1000),
+		Data:          []byte("hello"),
+		Confirmations: []common.Address{owner},
+		Required:      big.NewInt(2),
+	}
+	data, err := tran.packData()
+	if err != nil {
+		t.Fatal(err)
+	}
+	tran.Data = data
+
+	b, err := tran.MarshalJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := new(Transaction)
+	if err := got.UnmarshalJSON(b); err != nil {
+		t.Fatal(err)
+	}
+	p := got.Intent.Proposal
+	if p == nil || p.Destination != owner || p.Value.Int64() != 1000 || string(p.Data) != "hello" || len(p.Confirmations) != 1 {
+		t.Fatalf("got proposal %+v", p)
+	}
+	if err := got.verifyIntent(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSignIntentWallet(t *testing.T) {
+	dir, err := ioutil.TempDir("", "intent")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	txPath := filepath.Join(dir, "confirm.tx")
+
+	cli := NewCLI()
+	cli.TestCommand("build confirm 3 --offline --nonce 7 --gasLimit 100000 --chainID 1007 -w " + dir + " " +
+		"-a 0xf09E6759c2588eE8435902d16350E321CBD27af3 -f 0x9B3deA9C636BA262f870f98a1c64d444BF0f6544 --out " + txPath)
+
+	// only to is redirected to another contract, the data is the same
+	b, err := ioutil.ReadFile(txPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var fields map[string]interface{}
+	if err := json.Unmarshal(b, &fields); err != nil {
+		t.Fatal(err)
+	}
+	fields["to"] = "0x7c1d845a0CC7E24352A59FEF437eB27b504769DE"
+	if err := saveJSONToFile(fields, txPath); err != nil {
+		t.Fatal(err)
+	}
+
+	cli = NewCLI()
+	cli.rootCmd.SetArgs([]string{"sign", txPath, "-w", dir})
+	if err := cli.rootCmd.Execute(); errorCode(err) != ErrCodeIntentMismatch {
+		t.Fatalf("sign transaction redirected to other contract got %v", err)
+	}
+}
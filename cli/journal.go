@@ -0,0 +1,176 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/spf13/cobra"
+)
+
+const defaultJournalLast = 10
+
+func (cli *CLI) buildTxStatusCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:                   "status [hash] [--last n]",
+		Short:                 "Show the status of the transaction of hash, or the last transactions in the journal",
+		Args:                  cobra.MaximumNArgs(1),
+		DisableFlagsInUseLine: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 1 {
+				return cli.showTxStatus(args[0])
+			}
+			last, _ := cmd.Flags().GetInt("last")
+			if last <= 0 {
+				return newErrorf(ErrCodeInvalidArgument, "Error: last(%d) must be greater than 0", last)
+			}
+			return cli.showJournal(last)
+		},
+	}
+	cmd.Flags().Int("last", defaultJournalLast, "the `number` of the last transactions in the journal to show")
+
+	return cmd
+}
+
+func (cli *CLI) buildTxPendingCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:                   "pending",
+		Short:                 "Show the transactions in the journal not mined yet",
+		Args:                  cobra.NoArgs,
+		DisableFlagsInUseLine: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cli.showJournalPending()
+		},
+	}
+
+	return cmd
+}
+
+// showTxStatus shows the status of the transaction of hashStr, the one not
+// in the journal is read from node
+func (cli *CLI) showTxStatus(hashStr string) error {
+	hashBytes, err := hexutil.Decode(hashStr)
+	if err != nil || len(hashBytes) != common.HashLength {
+		return newErrorf(ErrCodeInvalidArgument, "Error: transaction hash(%s) invalid", hashStr)
+	}
+	hash := common.BytesToHash(hashBytes)
+
+	entries, err := cli.readJournal()
+	if err != nil {
+		return newError(ErrCodeFile, err)
+	}
+	var entry *JournalEntry
+	for _, e := range entries {
+		if e.Hash == hash {
+			entry = e
+		}
+	}
+
+	if err := cli.BuildClient(); err != nil {
+		return newErrorf(ErrCodeRPC, "Build client error(%s)", err)
+	}
+	ctx := context.Background()
+	if entry == nil {
+		tx, _, err := cli.client.TransactionByHash(ctx, hash)
+		if err == ethereum.NotFound {
+			return newErrorf(ErrCodeTxNotFound, "Error: transaction %s not found in journal or node", hash.String())
+		} else if err != nil {
+			return newErrorf(ErrCodeRPC, "TransactionByHash error: %v", err)
+		}
+		from, err := types.Sender(types.NewEIP155Signer(tx.ChainId()), tx)
+		if err != nil {
+			return newErrorf(ErrCodeInvalidArgument, "Error: recover sender error: %v", err)
+		}
+		// not sent by the CLI, the time is unknown
+		entry = newJournalEntry(tx, from)
+		entry.Time = time.Time{}
+	}
+
+	s, err := cli.queryJournalStatus(ctx, entry)
+	if err != nil {
+		return err
+	}
+	if cli.isJSON() {
+		cli.printJSON(s)
+	} else {
+		printJournalStatus(s)
+	}
+	return nil
+}
+
+// showJournal shows the status of the last transactions in the journal
+func (cli *CLI) showJournal(last int) error {
+	entries, err := cli.readJournal()
+	if err != nil {
+		return newError(ErrCodeFile, err)
+	}
+	if len(entries) > last {
+		entries = entries[len(entries)-last:]
+	}
+	return cli.showJournalStatus(entries)
+}
+
+// showJournalPending shows the transactions in the journal whose nonce is
+// not used yet, the others are mined or replaced
+func (cli *CLI) showJournalPending() error {
+	entries, err := cli.readJournal()
+	if err != nil {
+		return newError(ErrCodeFile, err)
+	}
+	if len(entries) == 0 {
+		return cli.showJournalStatus(nil)
+	}
+
+	if err := cli.BuildClient(); err != nil {
+		return newErrorf(ErrCodeRPC, "Build client error(%s)", err)
+	}
+	ctx := context.Background()
+	nonces := make(map[common.Address]uint64)
+	var pending []*JournalEntry
+	for _, e := range entries {
+		nonce, ok := nonces[e.From]
+		if !ok {
+			if nonce, err = cli.client.NonceAt(ctx, e.From, nil); err != nil {
+				return newErrorf(ErrCodeRPC, "NonceAt error: %v", err)
+			}
+			nonces[e.From] = nonce
+		}
+		if e.Nonce >= nonce {
+			pending = append(pending, e)
+		}
+	}
+	return cli.showJournalStatus(pending)
+}
+
+func (cli *CLI) showJournalStatus(entries []*JournalEntry) error {
+	statuses := make([]*journalStatus, 0, len(entries))
+	if len(entries) > 0 {
+		if err := cli.BuildClient(); err != nil {
+			return newErrorf(ErrCodeRPC, "Build client error(%s)", err)
+		}
+	}
+	for _, e := range entries {
+		s, err := cli.queryJournalStatus(context.Background(), e)
+		if err != nil {
+			return err
+		}
+		statuses = append(statuses, s)
+	}
+
+	if cli.isJSON() {
+		cli.printJSON(statuses)
+		return nil
+	}
+	if len(statuses) == 0 {
+		fmt.Println("No transaction in journal")
+		return nil
+	}
+	for _, s := range statuses {
+		printJournalLine(s)
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+package cli
+
+import (
+	"io/ioutil"
+	"math/big"
+	"os"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/newtonproject/MultiSignatureWallet/msw"
+)
+
+func TestJournal(t *testing.T) {
+	dir, err := ioutil.TempDir("", "journal")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	cli := NewCLI()
+	cli.walletPath = dir
+	if entries, err := cli.readJournal(); err != nil || len(entries) != 0 {
+		t.Fatalf("got entries %v, error %v of no journal", entries, err)
+	}
+
+	from := common.HexToAddress("0x9B3deA9C636BA262f870f98a1c64d444BF0f6544")
+	wallet := common.HexToAddress("0xf09E6759c2588eE8435902d16350E321CBD27af3")
+	confirm, _ := msw.Pack("confirmTransaction", big.NewInt(3))
+	submit, _ := msw.Pack("submitTransaction", from, big.NewInt(1), []byte{})
+	txs := []*types.Transaction{
+		types.NewContractCreation(0, new(big.Int), 3000000, big.NewInt(1), []byte{1}),
+		types.NewTransaction(1, wallet, new(big.Int), 100000, big.NewInt(1), submit),
+		types.NewTransaction(2, wallet, new(big.Int), 100000, big.NewInt(1), confirm),
+		types.NewTransaction(3, from, new(big.Int), 21000, big.NewInt(1), nil),
+	}
+	for _, tx := range txs {
+		if err := cli.appendJournal(newJournalEntry(tx, from)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	entries, err := cli.readJournal()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != len(txs) {
+		t.Fatalf("got %d entries, want %d", len(entries), len(txs))
+	}
+	for i, action := range []string{GasActionDeploy, GasActionSubmit, GasActionConfirm, JournalActionCall} {
+		if e := entries[i]; e.Action != action || e.Hash != txs[i].Hash() || e.Nonce != uint64(i) || e.From != from {
+			t.Fatalf("got entry %+v, want action %s", e, action)
+		}
+	}
+	if id := entries[2].TransactionID; id == nil || id.Int64() != 3 {
+		t.Fatalf("got transaction ID %v of confirm", id)
+	}
+	if entries[1].TransactionID != nil {
+		t.Fatalf("got transaction ID %v of submit", entries[1].TransactionID)
+	}
+}
+
+func TestTxStatus(t *testing.T) {
+	dir, err := ioutil.TempDir("", "journal")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	cli := NewCLI()
+	expectOutput(t, cli, "No transaction in journal", "tx status -w "+dir)
+	expectOutput(t, cli, "No transaction in journal", "tx pending -w "+dir)
+	cli.TestCommand("tx status 0x1234 -w " + dir)
+}
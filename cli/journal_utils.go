@@ -0,0 +1,246 @@
+package cli
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"time"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/newtonproject/MultiSignatureWallet/msw"
+)
+
+// journalDir is the directory of the journal in the wallet directory, the
+// keystore skips the directories
+const journalDir = "journal"
+
+const journalFile = "sent.jsonl"
+
+// the actions of the journal besides the actions of the gas policy
+const (
+	JournalActionCancel = "cancel"
+	JournalActionCall   = "call"
+)
+
+// JournalEntry is a transaction sent by the CLI, appended to the journal
+// once it is sent
+type JournalEntry struct {
+	Time          time.Time       `json:"time"`
+	Hash          common.Hash     `json:"hash"`
+	Action        string          `json:"action"`
+	TransactionID *big.Int        `json:"transactionId,omitempty"`
+	From          common.Address  `json:"from"`
+	To            *common.Address `json:"to,omitempty"`
+	Nonce         uint64          `json:"nonce"`
+	// Replaces is the hash of the transaction replaced by speedup or cancel
+	Replaces *common.Hash `json:"replaces,omitempty"`
+}
+
+// newJournalEntry returns the entry of tx from from, the action and the
+// transaction ID are decoded from the data calling the wallet
+func newJournalEntry(tx *types.Transaction, from common.Address) *JournalEntry {
+	e := &JournalEntry{
+		Time:   time.Now(),
+		Hash:   tx.Hash(),
+		Action: JournalActionCall,
+		From:   from,
+		To:     tx.To(),
+		Nonce:  tx.Nonce(),
+	}
+	if tx.To() == nil {
+		e.Action = GasActionDeploy
+		return e
+	}
+
+	call, err := msw.DecodeCall(tx.Data())
+	if err != nil {
+		return e
+	}
+	switch call.Method {
+	case "submitTransaction":
+		e.Action = GasActionSubmit
+	case "confirmTransaction":
+		e.Action = GasActionConfirm
+	case "revokeConfirmation":
+		e.Action = GasActionRevoke
+	case "executeTransaction":
+		e.Action = GasActionExecute
+	}
+	if e.Action != GasActionSubmit && e.Action != JournalActionCall && len(call.Args) > 0 {
+		e.TransactionID, _ = call.Args[0].Value.(*big.Int)
+	}
+	return e
+}
+
+func (cli *CLI) journalPath() string {
+	return filepath.Join(cli.walletPath, journalDir, journalFile)
+}
+
+// appendJournal appends the entry to the journal
+func (cli *CLI) appendJournal(e *JournalEntry) error {
+	if err := os.MkdirAll(filepath.Join(cli.walletPath, journalDir), 0700); err != nil {
+		return err
+	}
+	b, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	f, err := os.OpenFile(cli.journalPath(), os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = f.Write(append(b, '\n'))
+	return err
+}
+
+// recordJournal appends the entry to the journal, the error is printed as
+// a warning, the transaction has been sent
+func (cli *CLI) recordJournal(e *JournalEntry) {
+	if err := cli.appendJournal(e); err != nil {
+		fmt.Println("Warning: record transaction in journal error:", err)
+	}
+}
+
+// journalTx records tx sent from from in the journal
+func (cli *CLI) journalTx(tx *types.Transaction, from common.Address) {
+	cli.recordJournal(newJournalEntry(tx, from))
+}
+
+// readJournal reads all the entries in the journal, in the order of sent
+func (cli *CLI) readJournal() ([]*JournalEntry, error) {
+	f, err := os.Open(cli.journalPath())
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var entries []*JournalEntry
+	scanner := bufio.NewScanner(f)
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		e := new(JournalEntry)
+		if err := json.Unmarshal(scanner.Bytes(), e); err != nil {
+			return nil, fmt.Errorf("journal line %d: %v", line, err)
+		}
+		entries = append(entries, e)
+	}
+	return entries, scanner.Err()
+}
+
+// Status of the transaction in the journal
+const (
+	journalStatusPending  = "pending"
+	journalStatusDropped  = "dropped"
+	journalStatusReplaced = "replaced"
+)
+
+// journalStatus is the status of the transaction in the journal, with its
+// decoded receipt if mined
+type journalStatus struct {
+	*JournalEntry
+	Status   string         `json:"status"`
+	GasUsed  uint64         `json:"gasUsed,omitempty"`
+	Events   []*walletEvent `json:"events,omitempty"`
+	Outcomes []string       `json:"outcomes,omitempty"`
+}
+
+// mined reports whether the transaction has the receipt
+func (s *journalStatus) mined() bool {
+	return s.Status == sentTxStatusSuccess || s.Status == sentTxStatusFailed
+}
+
+// queryJournalStatus queries the receipt of the entry and decodes the wallet
+// events. The transaction without receipt is pending if known to node,
+// otherwise replaced if its nonce is used, or dropped.
+func (cli *CLI) queryJournalStatus(ctx context.Context, e *JournalEntry) (*journalStatus, error) {
+	entry := *e
+	s := &journalStatus{JournalEntry: &entry}
+
+	receipt, err := cli.client.TransactionReceipt(ctx, e.Hash)
+	if err == nil && receipt != nil {
+		wallet := receipt.ContractAddress
+		if e.To != nil {
+			wallet = *e.To
+		}
+		result, err := cli.decodeReceipt(receipt, wallet)
+		if err != nil {
+			return nil, newErrorf(ErrCodeGeneral, "decode receipt error: %v", err)
+		}
+		s.Status = sentTxStatusFailed
+		if result.Success {
+			s.Status = sentTxStatusSuccess
+		}
+		s.GasUsed, s.Events, s.Outcomes = receipt.GasUsed, result.Events, result.Outcomes
+		if sub := result.submission(); sub != nil && s.TransactionID == nil {
+			s.TransactionID = sub.TransactionID
+		}
+		return s, nil
+	} else if err != nil && err != ethereum.NotFound {
+		return nil, newErrorf(ErrCodeRPC, "TransactionReceipt error: %v", err)
+	}
+
+	if _, _, err := cli.client.TransactionByHash(ctx, e.Hash); err == nil {
+		s.Status = journalStatusPending
+		return s, nil
+	} else if err != ethereum.NotFound {
+		return nil, newErrorf(ErrCodeRPC, "TransactionByHash error: %v", err)
+	}
+	nonce, err := cli.client.NonceAt(ctx, e.From, nil)
+	if err != nil {
+		return nil, newErrorf(ErrCodeRPC, "NonceAt error: %v", err)
+	}
+	s.Status = journalStatusDropped
+	if nonce > e.Nonce {
+		s.Status = journalStatusReplaced
+	}
+	return s, nil
+}
+
+// printJournalStatus prints the entry, and the events and outcomes if mined
+func printJournalStatus(s *journalStatus) {
+	fmt.Printf("Transaction %s\n", s.Hash.String())
+	fmt.Printf("\tAction: %s\n", s.Action)
+	if s.TransactionID != nil {
+		fmt.Printf("\tTransaction ID: %s\n", s.TransactionID.String())
+	}
+	if s.Replaces != nil {
+		fmt.Printf("\tReplaces: %s\n", s.Replaces.String())
+	}
+	fmt.Printf("\tFrom: %s\n", s.From.String())
+	fmt.Printf("\tNonce: %d\n", s.Nonce)
+	if !s.Time.IsZero() {
+		fmt.Printf("\tSent: %s\n", s.Time.Format(time.RFC3339))
+	}
+	if !s.mined() {
+		fmt.Printf("\tStatus: %s\n", s.Status)
+		return
+	}
+	fmt.Printf("\tStatus: %s (gas used %d)\n", s.Status, s.GasUsed)
+	for _, e := range s.Events {
+		fmt.Printf("\tEvent: %s\n", e.Text(""))
+	}
+	for _, outcome := range s.Outcomes {
+		fmt.Printf("\tOutcome: %s\n", outcome)
+	}
+}
+
+// printJournalLine prints the status in one line
+func printJournalLine(s *journalStatus) {
+	id := "-"
+	if s.TransactionID != nil {
+		id = s.TransactionID.String()
+	}
+	fmt.Printf("%s  %s  %-8s  ID %-4s  nonce %-4d  %s\n", s.Time.Format(time.RFC3339), s.Hash.String(), s.Action, id, s.Nonce, s.Status)
+}
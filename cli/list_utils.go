@@ -0,0 +1,498 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/newtonproject/MultiSignatureWallet/msw"
+)
+
+const (
+	defaultListBatchSize = 100
+	defaultListWorkers   = 4
+)
+
+// the ABIs of the wallet and ERC20 token, parsed once by parsedABIs
+var (
+	parseABIsOnce sync.Once
+	walletABI     abi.ABI
+	erc20ABI      abi.ABI
+	parseABIsErr  error
+)
+
+// parsedABIs returns the ABIs of the wallet and ERC20 token
+func parsedABIs() (abi.ABI, abi.ABI, error) {
+	parseABIsOnce.Do(func() {
+		if walletABI, parseABIsErr = abi.JSON(strings.NewReader(msw.MultiSigWalletWithDailyLimitABI)); parseABIsErr != nil {
+			return
+		}
+		erc20ABI, parseABIsErr = abi.JSON(strings.NewReader(ERC20TransferABI))
+	})
+	if parseABIsErr != nil {
+		return abi.ABI{}, abi.ABI{}, fmt.Errorf("JSON err: %v", parseABIsErr)
+	}
+	return walletABI, erc20ABI, nil
+}
+
+// getTransactionIDs returns the transaction IDs in [from, to) filtered by
+// status with getTransactionIds of contract, to 0 means no limit
+func (cli *CLI) getTransactionIDs(opts *bind.CallOpts, from, to uint64, pending, executed bool) ([]*big.Int, error) {
+	simpleRegistry, err := cli.GetSimpleRegistry()
+	if err != nil {
+		return nil, err
+	}
+
+	// getTransactionIds indexes the filtered IDs, not the IDs themselves,
+	// so get all the filtered IDs and limit the range here
+	count, err := simpleRegistry.GetTransactionCount(opts, pending, executed)
+	if err != nil {
+		return nil, fmt.Errorf("GetTransactionCount error: %v", err)
+	}
+	if count.Sign() <= 0 {
+		return nil, nil
+	}
+	all, err := simpleRegistry.GetTransactionIds(opts, big.NewInt(0), count, pending, executed)
+	if err != nil {
+		return nil, fmt.Errorf("GetTransactionIds error: %v", err)
+	}
+
+	var ids []*big.Int
+	for _, id := range all {
+		if id.Uint64() < from || (to != 0 && id.Uint64() >= to) {
+			continue
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+// splitIDs splits ids into batches of at most size IDs
+func splitIDs(ids []*big.Int, size int) [][]*big.Int {
+	if size <= 0 {
+		size = defaultListBatchSize
+	}
+	var batches [][]*big.Int
+	for len(ids) > size {
+		batches = append(batches, ids[:size])
+		ids = ids[size:]
+	}
+	if len(ids) > 0 {
+		batches = append(batches, ids)
+	}
+	return batches
+}
+
+// getWalletTransactions reads the transaction IDs and their confirmations at
+// blockNumber (nil for latest). Each batch of IDs is sent as one JSON-RPC batch
+// request, at most workers batches are sent at the same time.
+func (cli *CLI) getWalletTransactions(ctx context.Context, blockNumber *big.Int, ids []*big.Int, batchSize, workers int) ([]*walletTransaction, error) {
+	if _, err := cli.GetSimpleRegistry(); err != nil {
+		return nil, err
+	}
+	parsed, _, err := parsedABIs()
+	if err != nil {
+		return nil, err
+	}
+
+	batches := splitIDs(ids, batchSize)
+	results := make([][]*walletTransaction, len(batches))
+	err = runBatches(ctx, len(batches), workers, func(ctx context.Context, index int) error {
+		txs, err := cli.batchGetWalletTransactions(ctx, parsed, blockNumber, batches[index])
+		if err != nil {
+			return err
+		}
+		results[index] = txs
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var txs []*walletTransaction
+	for _, r := range results {
+		txs = append(txs, r...)
+	}
+	return txs, nil
+}
+
+// runBatches calls fn for batch 0 to n-1 with at most workers goroutines,
+// it stops at the first error and returns it
+func runBatches(ctx context.Context, n, workers int, fn func(ctx context.Context, index int) error) error {
+	if workers <= 0 {
+		workers = defaultListWorkers
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	jobs := make(chan int)
+	var (
+		wg       sync.WaitGroup
+		errOnce  sync.Once
+		firstErr error
+	)
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for index := range jobs {
+				if err := fn(ctx, index); err != nil {
+					errOnce.Do(func() {
+						firstErr = err
+						cancel()
+					})
+				}
+			}
+		}()
+	}
+
+	for index := 0; index < n; index++ {
+		if ctx.Err() != nil {
+			break
+		}
+		jobs <- index
+	}
+	close(jobs)
+	wg.Wait()
+
+	if firstErr != nil {
+		return firstErr
+	}
+	return ctx.Err()
+}
+
+// batchGetWalletTransactions reads ids with transactions and getConfirmations
+// calls in one JSON-RPC batch request
+func (cli *CLI) batchGetWalletTransactions(ctx context.Context, parsed abi.ABI, blockNumber *big.Int, ids []*big.Int) ([]*walletTransaction, error) {
+	contract := common.HexToAddress(cli.contractAddress)
+	block := "latest"
+	if blockNumber != nil {
+		block = hexutil.EncodeBig(blockNumber)
+	}
+
+	elems := make([]rpc.BatchElem, 0, len(ids)*2)
+	for _, id := range ids {
+		for _, method := range []string{"transactions", "getConfirmations"} {
+			input, err := parsed.Pack(method, id)
+			if err != nil {
+				return nil, fmt.Errorf("pack %s error: %v", method, err)
+			}
+			elems = append(elems, rpc.BatchElem{
+				Method: "eth_call",
+				Args: []interface{}{map[string]interface{}{
+					"to":   contract,
+					"data": hexutil.Bytes(input),
+				}, block},
+				Result: new(hexutil.Bytes),
+			})
+		}
+	}
+
+	if err := cli.rpcClient.BatchCallContext(ctx, elems); err != nil {
+		return nil, fmt.Errorf("batch call error: %v", err)
+	}
+
+	txs := make([]*walletTransaction, 0, len(ids))
+	for i, id := range ids {
+		txElem, confElem := elems[2*i], elems[2*i+1]
+		if txElem.Error != nil {
+			return nil, fmt.Errorf("%s Transactions Error(%v)", id.String(), txElem.Error)
+		}
+		if confElem.Error != nil {
+			return nil, fmt.Errorf("%s GetConfirmations Error(%v)", id.String(), confElem.Error)
+		}
+		txOutput, confOutput := *txElem.Result.(*hexutil.Bytes), *confElem.Result.(*hexutil.Bytes)
+		if len(txOutput) == 0 || len(confOutput) == 0 {
+			return nil, bind.ErrNoCode
+		}
+
+		t := new(struct {
+			Destination common.Address
+			Value       *big.Int
+			Data        []byte
+			Executed    bool
+		})
+		if err := parsed.Unpack(t, "transactions", txOutput); err != nil {
+			return nil, fmt.Errorf("%s Transactions Error(%v)", id.String(), err)
+		}
+		var confirmations []common.Address
+		if err := parsed.Unpack(&confirmations, "getConfirmations", confOutput); err != nil {
+			return nil, fmt.Errorf("%s GetConfirmations Error(%v)", id.String(), err)
+		}
+
+		txs = append(txs, &walletTransaction{
+			ID:            id,
+			Destination:   t.Destination,
+			Value:         t.Value,
+			Data:          t.Data,
+			Executed:      t.Executed,
+			Confirmations: confirmations,
+		})
+	}
+
+	return txs, nil
+}
+
+// getConfirmedBy reads whether owner confirmed each of ids with the
+// confirmations(id, owner) calls in JSON-RPC batch requests
+func (cli *CLI) getConfirmedBy(ctx context.Context, blockNumber *big.Int, ids []*big.Int, owner common.Address, batchSize, workers int) (map[string]bool, error) {
+	parsed, _, err := parsedABIs()
+	if err != nil {
+		return nil, err
+	}
+	contract := common.HexToAddress(cli.contractAddress)
+	block := "latest"
+	if blockNumber != nil {
+		block = hexutil.EncodeBig(blockNumber)
+	}
+
+	var mu sync.Mutex
+	confirmed := make(map[string]bool)
+	batches := splitIDs(ids, batchSize)
+	err = runBatches(ctx, len(batches), workers, func(ctx context.Context, index int) error {
+		batch := batches[index]
+		elems := make([]rpc.BatchElem, 0, len(batch))
+		for _, id := range batch {
+			input, err := parsed.Pack("confirmations", id, owner)
+			if err != nil {
+				return fmt.Errorf("pack confirmations error: %v", err)
+			}
+			elems = append(elems, rpc.BatchElem{
+				Method: "eth_call",
+				Args: []interface{}{map[string]interface{}{
+					"to":   contract,
+					"data": hexutil.Bytes(input),
+				}, block},
+				Result: new(hexutil.Bytes),
+			})
+		}
+		if err := cli.rpcClient.BatchCallContext(ctx, elems); err != nil {
+			return fmt.Errorf("batch call error: %v", err)
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		for i, id := range batch {
+			if elems[i].Error != nil {
+				return fmt.Errorf("%s Confirmations Error(%v)", id.String(), elems[i].Error)
+			}
+			var ok bool
+			if err := parsed.Unpack(&ok, "confirmations", *elems[i].Result.(*hexutil.Bytes)); err != nil {
+				return fmt.Errorf("%s Confirmations Error(%v)", id.String(), err)
+			}
+			confirmed[id.String()] = ok
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return confirmed, nil
+}
+
+// txListFilter filters the transactions of list, the empty fields match all
+type txListFilter struct {
+	Destinations []common.Address
+	MinValue     *big.Int
+	MaxValue     *big.Int
+	Methods      []string
+	ConfirmedBy  []common.Address
+
+	// Awaiting only keeps the pending transactions not confirmed by Owner,
+	// the confirmations in awaitingConfirmed are used if set
+	Awaiting          bool
+	Owner             common.Address
+	awaitingConfirmed map[string]bool
+}
+
+func hasAddress(addresses []common.Address, address common.Address) bool {
+	for _, a := range addresses {
+		if a == address {
+			return true
+		}
+	}
+	return false
+}
+
+func (f *txListFilter) match(t *walletTransaction, wallet common.Address) bool {
+	if len(f.Destinations) > 0 && !hasAddress(f.Destinations, t.Destination) {
+		return false
+	}
+	if f.MinValue != nil && t.Value.Cmp(f.MinValue) < 0 {
+		return false
+	}
+	if f.MaxValue != nil && t.Value.Cmp(f.MaxValue) > 0 {
+		return false
+	}
+	if len(f.Methods) > 0 {
+		method, _ := decodeTxAction(t, wallet, "")
+		if !stringInSlice(method, f.Methods) {
+			return false
+		}
+	}
+	for _, owner := range f.ConfirmedBy {
+		if !hasAddress(t.Confirmations, owner) {
+			return false
+		}
+	}
+	if f.Awaiting {
+		if t.Executed {
+			return false
+		}
+		if f.awaitingConfirmed != nil {
+			if f.awaitingConfirmed[t.ID.String()] {
+				return false
+			}
+		} else if hasAddress(t.Confirmations, f.Owner) {
+			return false
+		}
+	}
+	return true
+}
+
+func (f *txListFilter) filter(txs []*walletTransaction, wallet common.Address) []*walletTransaction {
+	var matched []*walletTransaction
+	for _, t := range txs {
+		if f.match(t, wallet) {
+			matched = append(matched, t)
+		}
+	}
+	return matched
+}
+
+// List columns and sort keys
+const (
+	ListColumnID            = "id"
+	ListColumnConfirmed     = "confirmed"
+	ListColumnStatus        = "status"
+	ListColumnDestination   = "destination"
+	ListColumnValue         = "value"
+	ListColumnConfirmations = "confirmations"
+	ListColumnAction        = "action"
+)
+
+// ListColumnList is all the columns of list in default order
+var ListColumnList = []string{ListColumnID, ListColumnConfirmed, ListColumnStatus, ListColumnDestination, ListColumnValue, ListColumnConfirmations, ListColumnAction}
+
+// listColumnDefault is the columns shown without --columns and --long
+var listColumnDefault = []string{ListColumnID, ListColumnConfirmed, ListColumnStatus}
+
+// ListSortList is the available sort keys of list
+var ListSortList = []string{ListColumnID, ListColumnValue, ListColumnConfirmations}
+
+// sortTransactions sorts txs by key, ties are broken by ID
+func sortTransactions(txs []*walletTransaction, key string, desc bool) {
+	sort.SliceStable(txs, func(i, j int) bool {
+		a, b := txs[i], txs[j]
+		if desc {
+			a, b = b, a
+		}
+		switch key {
+		case ListColumnValue:
+			if c := a.Value.Cmp(b.Value); c != 0 {
+				return c < 0
+			}
+		case ListColumnConfirmations:
+			if len(a.Confirmations) != len(b.Confirmations) {
+				return len(a.Confirmations) < len(b.Confirmations)
+			}
+		}
+		return a.ID.Cmp(b.ID) < 0
+	})
+}
+
+// decodeTxAction decodes the method name and a short text of what the
+// transaction ID does, the method name is empty for a plain value transfer
+func decodeTxAction(t *walletTransaction, wallet common.Address, unit string) (string, string) {
+	if len(t.Data) == 0 {
+		return "", fmt.Sprintf("transfer %s", getWeiAmountTextUnitByUnit(t.Value, unit))
+	}
+	if len(t.Data) < 4 {
+		return "", fmt.Sprintf("call 0x%s", common.Bytes2Hex(t.Data))
+	}
+
+	walletParsed, erc20Parsed, err := parsedABIs()
+	if err != nil {
+		return "", fmt.Sprintf("call 0x%s", common.Bytes2Hex(t.Data[:4]))
+	}
+	abis := []abi.ABI{erc20Parsed, walletParsed}
+	if t.Destination == wallet {
+		abis = []abi.ABI{walletParsed}
+	}
+	for _, parsed := range abis {
+		method, err := parsed.MethodById(t.Data[:4])
+		if err != nil || method == nil {
+			continue
+		}
+		values, err := method.Inputs.UnpackValues(t.Data[4:])
+		if err != nil {
+			return method.Name, fmt.Sprintf("%s(invalid arguments)", method.Name)
+		}
+		var args []string
+		for k, v := range values {
+			var text string
+			switch vv := v.(type) {
+			case common.Address:
+				text = vv.String()
+			case []byte:
+				text = "0x" + common.Bytes2Hex(vv)
+			default:
+				text = fmt.Sprintf("%v", vv)
+			}
+			args = append(args, fmt.Sprintf("%s: %s", method.Inputs[k].Name, text))
+		}
+		return method.Name, fmt.Sprintf("%s(%s)", method.Name, strings.Join(args, ", "))
+	}
+
+	return "", fmt.Sprintf("call 0x%s", common.Bytes2Hex(t.Data[:4]))
+}
+
+// printTransactionList prints one line of the columns for each transaction,
+// the default columns are "ID Confirmed/Unconfirmed Executed/Pending"
+func printTransactionList(txs []*walletTransaction, required *big.Int, wallet common.Address, columns []string, unit string) {
+	if len(columns) == 0 {
+		columns = listColumnDefault
+	}
+	for _, t := range txs {
+		var fields []string
+		for _, column := range columns {
+			switch column {
+			case ListColumnID:
+				fields = append(fields, t.ID.String())
+			case ListColumnConfirmed:
+				if big.NewInt(int64(len(t.Confirmations))).Cmp(required) >= 0 {
+					fields = append(fields, "Confirmed")
+				} else {
+					fields = append(fields, "Unconfirmed")
+				}
+			case ListColumnStatus:
+				if t.Executed {
+					fields = append(fields, "Executed")
+				} else {
+					fields = append(fields, "Pending")
+				}
+			case ListColumnDestination:
+				fields = append(fields, t.Destination.String())
+			case ListColumnValue:
+				fields = append(fields, getWeiAmountTextUnitByUnit(t.Value, unit))
+			case ListColumnConfirmations:
+				fields = append(fields, fmt.Sprintf("%d/%s", len(t.Confirmations), required.String()))
+			case ListColumnAction:
+				_, action := decodeTxAction(t, wallet, unit)
+				fields = append(fields, action)
+			}
+		}
+
+		fmt.Println(strings.Join(fields, " "))
+	}
+}
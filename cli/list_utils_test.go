@@ -0,0 +1,87 @@
+package cli
+
+import (
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/newtonproject/MultiSignatureWallet/msw"
+)
+
+func TestSplitIDs(t *testing.T) {
+	var ids []*big.Int
+	for i := int64(0); i < 7; i++ {
+		ids = append(ids, big.NewInt(i))
+	}
+
+	batches := splitIDs(ids, 3)
+	if len(batches) != 3 {
+		t.Fatalf("got %d batches, want 3", len(batches))
+	}
+	if len(batches[0]) != 3 || len(batches[1]) != 3 || len(batches[2]) != 1 {
+		t.Fatalf("wrong batch sizes: %d %d %d", len(batches[0]), len(batches[1]), len(batches[2]))
+	}
+	if batches[2][0].Int64() != 6 {
+		t.Fatalf("last ID is %s, want 6", batches[2][0])
+	}
+
+	if batches := splitIDs(nil, 3); len(batches) != 0 {
+		t.Fatalf("got %d batches for no ID", len(batches))
+	}
+}
+
+func TestTxListFilter(t *testing.T) {
+	wallet := common.HexToAddress("0x6a038842f9E9010624eAeB5f30ec5004C05EE21D")
+	owner1 := common.HexToAddress("0xDC8F76075Db000Fa70fdA3AA2c95d63F22A10a67")
+	owner2 := common.HexToAddress("0xeF0b04a14e62434a99C4aF28C6dAb52ba9B1C8F3")
+
+	parsed, err := abi.JSON(strings.NewReader(msw.MultiSigWalletWithDailyLimitABI))
+	if err != nil {
+		t.Fatal(err)
+	}
+	addOwner, err := parsed.Pack("addOwner", owner2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	txs := []*walletTransaction{
+		{ID: big.NewInt(0), Destination: owner2, Value: big.NewInt(300), Confirmations: []common.Address{owner1, owner2}, Executed: true},
+		{ID: big.NewInt(1), Destination: wallet, Value: big.NewInt(0), Data: addOwner, Confirmations: []common.Address{owner2}},
+		{ID: big.NewInt(2), Destination: owner2, Value: big.NewInt(100), Confirmations: []common.Address{owner1}},
+	}
+
+	if method, action := decodeTxAction(txs[1], wallet, ""); method != "addOwner" || action != "addOwner(owner: "+owner2.String()+")" {
+		t.Fatalf("decodeTxAction got %s %s", method, action)
+	}
+
+	tests := []struct {
+		filter *txListFilter
+		want   []int64
+	}{
+		{&txListFilter{}, []int64{0, 1, 2}},
+		{&txListFilter{Destinations: []common.Address{wallet}}, []int64{1}},
+		{&txListFilter{MinValue: big.NewInt(100), MaxValue: big.NewInt(200)}, []int64{2}},
+		{&txListFilter{Methods: []string{"addOwner"}}, []int64{1}},
+		{&txListFilter{ConfirmedBy: []common.Address{owner1}}, []int64{0, 2}},
+		{&txListFilter{Awaiting: true, Owner: owner1}, []int64{1}},
+		{&txListFilter{Awaiting: true, Owner: owner1, awaitingConfirmed: map[string]bool{"1": true}}, []int64{2}},
+	}
+	for i, test := range tests {
+		got := test.filter.filter(txs, wallet)
+		if len(got) != len(test.want) {
+			t.Fatalf("test %d: got %d transactions, want %d", i, len(got), len(test.want))
+		}
+		for k, tx := range got {
+			if tx.ID.Int64() != test.want[k] {
+				t.Fatalf("test %d: got ID %s at %d, want %d", i, tx.ID, k, test.want[k])
+			}
+		}
+	}
+
+	sortTransactions(txs, ListColumnValue, true)
+	if txs[0].ID.Int64() != 0 || txs[1].ID.Int64() != 2 || txs[2].ID.Int64() != 1 {
+		t.Fatalf("sort by value desc got %s %s %s", txs[0].ID, txs[1].ID, txs[2].ID)
+	}
+}
@@ -0,0 +1,93 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/spf13/cobra"
+)
+
+func (cli *CLI) buildNonceCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:                   "nonce [address] [--sync [--reset]]",
+		Short:                 "Show the nonce ledger of offline builds, or sync it with node",
+		Long:                  "Show the nonce ledger of offline builds of the address, the from address or all addresses, or sync it with the pending nonce of node",
+		Args:                  cobra.MaximumNArgs(1),
+		DisableFlagsInUseLine: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			syncNode, _ := cmd.Flags().GetBool("sync")
+			reset, _ := cmd.Flags().GetBool("reset")
+			if reset && !syncNode {
+				fmt.Fprint(os.Stderr, cmd.UsageString())
+				return newErrorf(ErrCodeInvalidArgument, "Error: flag reset is only used with sync")
+			}
+
+			var addresses []common.Address
+			if len(args) > 0 {
+				if !common.IsHexAddress(args[0]) {
+					fmt.Fprint(os.Stderr, cmd.UsageString())
+					return newErrorf(ErrCodeInvalidArgument, "Error: address is invalid hex-encoded: %s", args[0])
+				}
+				addresses = append(addresses, common.HexToAddress(args[0]))
+			} else if common.IsHexAddress(cli.address) {
+				addresses = append(addresses, common.HexToAddress(cli.address))
+			}
+
+			var ledgers []*NonceLedger
+			if len(addresses) == 0 {
+				var err error
+				if ledgers, err = cli.nonceLedgers(); err != nil {
+					return newError(ErrCodeFile, err)
+				}
+			}
+			for _, address := range addresses {
+				ledger, err := cli.readNonceLedger(address)
+				if err != nil {
+					return newError(ErrCodeFile, err)
+				}
+				if ledger == nil {
+					if !syncNode {
+						fmt.Printf("%s has no nonce ledger\n", address.String())
+						continue
+					}
+					ledger = &NonceLedger{Address: address}
+				}
+				ledgers = append(ledgers, ledger)
+			}
+
+			if syncNode {
+				if err := cli.BuildClient(); err != nil {
+					return newErrorf(ErrCodeRPC, "BuildClient Error: %v", err)
+				}
+				for _, ledger := range ledgers {
+					nonce, err := cli.client.PendingNonceAt(context.Background(), ledger.Address)
+					if err != nil {
+						return newErrorf(ErrCodeRPC, "PendingNonceAt Error: %v", err)
+					}
+					ledger.sync(nonce, reset)
+					if err := cli.saveNonceLedger(ledger); err != nil {
+						return newError(ErrCodeFile, err)
+					}
+				}
+			}
+
+			if cli.isJSON() {
+				cli.printJSON(struct {
+					Ledgers []*NonceLedger `json:"ledgers"`
+				}{ledgers})
+				return nil
+			}
+			for _, ledger := range ledgers {
+				ledger.print()
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().Bool("sync", false, "sync the ledger with the pending nonce of node, the nonces handed out after it are kept")
+	cmd.Flags().Bool("reset", false, "drop the nonces handed out when sync, the next nonce is the pending nonce of node")
+
+	return cmd
+}
@@ -0,0 +1,62 @@
+package cli
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestNonceLedger(t *testing.T) {
+	l := &NonceLedger{Confirmed: 5, Next: 5}
+	l.use(5)
+	l.use(6)
+	l.use(4)
+	if l.Next != 7 || !reflect.DeepEqual(l.Pending, []uint64{5, 6}) {
+		t.Fatalf("got ledger %+v after use", l)
+	}
+	l.confirm(5)
+	if l.Confirmed != 6 || l.Next != 7 || !reflect.DeepEqual(l.Pending, []uint64{6}) {
+		t.Fatalf("got ledger %+v after confirm", l)
+	}
+	l.sync(9, false)
+	if l.Confirmed != 9 || l.Next != 9 || len(l.Pending) != 0 {
+		t.Fatalf("got ledger %+v after sync", l)
+	}
+	l.use(9)
+	l.sync(3, true)
+	if l.Confirmed != 3 || l.Next != 3 || len(l.Pending) != 0 {
+		t.Fatalf("got ledger %+v after reset", l)
+	}
+}
+
+func TestBuildNonceLedger(t *testing.T) {
+	dir, err := ioutil.TempDir("", "nonce")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	from := common.HexToAddress("0x9B3deA9C636BA262f870f98a1c64d444BF0f6544")
+	flags := " --offline --gasLimit 100000 -a 0xf09E6759c2588eE8435902d16350E321CBD27af3 -f " + from.String() + " -w " + dir
+	NewCLI().TestCommand("build confirm 1 --nonce 4 --out " + filepath.Join(dir, "1.tx") + flags)
+	NewCLI().TestCommand("build confirm 2 --out " + filepath.Join(dir, "2.tx") + flags)
+
+	trans, err := readTxFile(filepath.Join(dir, "2.tx"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if trans[0].Nonce != 5 {
+		t.Fatalf("got nonce %d of the second build, want 5", trans[0].Nonce)
+	}
+
+	cli := NewCLI()
+	cli.walletPath = dir
+	ledger, err := cli.readNonceLedger(from)
+	if err != nil || ledger == nil || ledger.Next != 6 || !reflect.DeepEqual(ledger.Pending, []uint64{4, 5}) {
+		t.Fatalf("got ledger %+v, error %v", ledger, err)
+	}
+}
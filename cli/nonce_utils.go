@@ -0,0 +1,187 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/spf13/cobra"
+)
+
+// nonceLedgerDir is the directory of nonce ledgers in the wallet directory,
+// the keystore skips the directories
+const nonceLedgerDir = "nonces"
+
+// NonceLedger is the nonces of an address handed out to offline builds,
+// so that the transactions built between broadcasts do not collide
+type NonceLedger struct {
+	Address common.Address `json:"address"`
+	// Confirmed is the next nonce after the ones confirmed by broadcast or
+	// synced from node
+	Confirmed uint64 `json:"confirmed"`
+	// Next is the next nonce to hand out
+	Next uint64 `json:"next"`
+	// Pending is the nonces handed out but not confirmed
+	Pending []uint64 `json:"pending"`
+}
+
+func (cli *CLI) nonceLedgerPath(address common.Address) string {
+	return filepath.Join(cli.walletPath, nonceLedgerDir, address.Hex()+".json")
+}
+
+// readNonceLedger reads the ledger of address, nil if not exist
+func (cli *CLI) readNonceLedger(address common.Address) (*NonceLedger, error) {
+	b, err := ioutil.ReadFile(cli.nonceLedgerPath(address))
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	ledger := new(NonceLedger)
+	if err := json.Unmarshal(b, ledger); err != nil {
+		return nil, fmt.Errorf("nonce ledger of %s: %v", address.String(), err)
+	}
+	return ledger, nil
+}
+
+func (cli *CLI) saveNonceLedger(ledger *NonceLedger) error {
+	if err := os.MkdirAll(filepath.Join(cli.walletPath, nonceLedgerDir), 0700); err != nil {
+		return err
+	}
+	return saveJSONToFile(ledger, cli.nonceLedgerPath(ledger.Address))
+}
+
+// nonceLedgers reads all the ledgers in the wallet directory
+func (cli *CLI) nonceLedgers() ([]*NonceLedger, error) {
+	files, err := ioutil.ReadDir(filepath.Join(cli.walletPath, nonceLedgerDir))
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	var ledgers []*NonceLedger
+	for _, f := range files {
+		name := strings.TrimSuffix(f.Name(), ".json")
+		if f.IsDir() || !common.IsHexAddress(name) {
+			continue
+		}
+		ledger, err := cli.readNonceLedger(common.HexToAddress(name))
+		if err != nil {
+			return nil, err
+		}
+		ledgers = append(ledgers, ledger)
+	}
+	return ledgers, nil
+}
+
+// use records nonce handed out to a build
+func (l *NonceLedger) use(nonce uint64) {
+	if nonce < l.Confirmed {
+		return
+	}
+	for _, n := range l.Pending {
+		if n == nonce {
+			return
+		}
+	}
+	l.Pending = append(l.Pending, nonce)
+	sort.Slice(l.Pending, func(i, j int) bool { return l.Pending[i] < l.Pending[j] })
+	if nonce >= l.Next {
+		l.Next = nonce + 1
+	}
+}
+
+// confirm records nonce confirmed on chain, the nonces before are used
+func (l *NonceLedger) confirm(nonce uint64) {
+	if nonce+1 > l.Confirmed {
+		l.Confirmed = nonce + 1
+	}
+	if l.Next < l.Confirmed {
+		l.Next = l.Confirmed
+	}
+	pending := l.Pending[:0]
+	for _, n := range l.Pending {
+		if n >= l.Confirmed {
+			pending = append(pending, n)
+		}
+	}
+	l.Pending = pending
+}
+
+// sync updates the ledger with the pending nonce of node, the nonces
+// handed out are dropped if reset
+func (l *NonceLedger) sync(nonce uint64, reset bool) {
+	l.Confirmed = nonce
+	pending := l.Pending[:0]
+	for _, n := range l.Pending {
+		if !reset && n >= nonce {
+			pending = append(pending, n)
+		}
+	}
+	l.Pending = pending
+	if reset || l.Next < nonce {
+		l.Next = nonce
+	}
+}
+
+// nextNonce returns the next nonce of address to hand out, ok is false if
+// address has no ledger
+func (cli *CLI) nextNonce(address common.Address) (uint64, bool, error) {
+	ledger, err := cli.readNonceLedger(address)
+	if err != nil || ledger == nil {
+		return 0, false, err
+	}
+	return ledger.Next, true, nil
+}
+
+// useNonce records the nonce of the offline transaction built
+func (cli *CLI) useNonce(address common.Address, nonce uint64) error {
+	ledger, err := cli.readNonceLedger(address)
+	if err != nil {
+		return err
+	}
+	if ledger == nil {
+		ledger = &NonceLedger{Address: address, Confirmed: nonce, Next: nonce}
+	}
+	ledger.use(nonce)
+	return cli.saveNonceLedger(ledger)
+}
+
+// confirmNonce records the nonce of the transaction confirmed by broadcast,
+// nothing is done if address has no ledger
+func (cli *CLI) confirmNonce(address common.Address, nonce uint64) error {
+	ledger, err := cli.readNonceLedger(address)
+	if err != nil || ledger == nil {
+		return err
+	}
+	ledger.confirm(nonce)
+	return cli.saveNonceLedger(ledger)
+}
+
+func (l *NonceLedger) print() {
+	fmt.Printf("%s confirmed: %d, next: %d, pending: %v\n", l.Address.String(), l.Confirmed, l.Next, l.Pending)
+}
+
+// applyLedgerNonce sets the nonce of the offline transaction to the next
+// nonce in the ledger of from, unless the flag nonce is set, ok is false if
+// from has no ledger
+func (cli *CLI) applyLedgerNonce(cmd *cobra.Command) (ok bool, err error) {
+	if cmd.Flags().Changed("nonce") {
+		return false, nil
+	}
+	next, ok, err := cli.nextNonce(cli.tran.From)
+	if err != nil || !ok {
+		return false, err
+	}
+	// the nonce of state is greater if the ledger is behind the chain
+	if next > cli.tran.Nonce {
+		cli.tran.Nonce = next
+	}
+	return true, nil
+}
@@ -0,0 +1,184 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// Output formats
+const (
+	OutputText = "text"
+	OutputJSON = "json"
+)
+
+// OutputList is the available output formats
+var OutputList = []string{OutputText, OutputJSON}
+
+const defaultOutput = OutputText
+
+// jsonError is the error object of JSON output
+type jsonError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// isJSON reports whether the output format is JSON
+func (cli *CLI) isJSON() bool {
+	return cli.output == OutputJSON
+}
+
+// setupOutput sends the human readable text to stderr for JSON output,
+// so that stdout only has the JSON document
+func (cli *CLI) setupOutput() {
+	if cli.isJSON() && cli.stdout == nil {
+		cli.stdout = os.Stdout
+		os.Stdout = os.Stderr
+	}
+}
+
+// restoreOutput undoes setupOutput
+func (cli *CLI) restoreOutput() {
+	if cli.stdout != nil {
+		os.Stdout = cli.stdout
+		cli.stdout = nil
+	}
+}
+
+// printJSON prints v as indented JSON to stdout
+func (cli *CLI) printJSON(v interface{}) {
+	var w io.Writer = os.Stdout
+	if cli.stdout != nil {
+		w = cli.stdout
+	}
+	b, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		fmt.Fprintf(w, "{\"error\": {\"code\": %q, \"message\": %q}}\n", "internal_error", err.Error())
+		return
+	}
+	fmt.Fprintln(w, string(b))
+}
+
+// printError prints err as the text, or as a JSON error object with its code
+func (cli *CLI) printError(err error) {
+	if !cli.isJSON() {
+		fmt.Println(err)
+		return
+	}
+	cli.printJSON(struct {
+		Error jsonError `json:"error"`
+	}{jsonError{Code: errorCode(err), Message: err.Error()}})
+}
+
+// walletInfoOutput is the JSON output of info
+type walletInfoOutput struct {
+	Contract       common.Address   `json:"contract"`
+	Balance        *big.Int         `json:"balance"`
+	Owners         []common.Address `json:"owners"`
+	Required       *big.Int         `json:"required"`
+	DailyLimit     *big.Int         `json:"dailyLimit"`
+	RemainingLimit *big.Int         `json:"remainingLimit,omitempty"`
+	SpentToday     *big.Int         `json:"spentToday,omitempty"`
+	Token          *tokenOutput     `json:"token,omitempty"`
+
+	CachedBlock      uint64 `json:"cachedBlock,omitempty"`
+	TransactionCount uint64 `json:"transactionCount,omitempty"`
+}
+
+// tokenOutput is the token balance of contract wallet
+type tokenOutput struct {
+	Address  common.Address `json:"address"`
+	Symbol   string         `json:"symbol"`
+	Decimals uint8          `json:"decimals"`
+	Balance  *big.Int       `json:"balance"`
+}
+
+// txInfoOutput is the JSON output of info <transactionID> and list
+type txInfoOutput struct {
+	*walletTransaction
+	Required  *big.Int       `json:"required"`
+	Confirmed bool           `json:"confirmed"`
+	Method    string         `json:"method,omitempty"`
+	Action    string         `json:"action"`
+	Timeline  []*walletEvent `json:"timeline,omitempty"`
+}
+
+func newTxInfoOutput(t *walletTransaction, required *big.Int, wallet common.Address) *txInfoOutput {
+	method, action := decodeTxAction(t, wallet, "")
+	return &txInfoOutput{
+		walletTransaction: t,
+		Required:          required,
+		Confirmed:         big.NewInt(int64(len(t.Confirmations))).Cmp(required) >= 0,
+		Method:            method,
+		Action:            action,
+	}
+}
+
+// sentTxOutput is the JSON output of the transaction sent to blockchain
+type sentTxOutput struct {
+	Hash          common.Hash     `json:"hash"`
+	From          common.Address  `json:"from,omitempty"`
+	To            *common.Address `json:"to,omitempty"`
+	Nonce         uint64          `json:"nonce"`
+	Status        string          `json:"status"`
+	GasUsed       uint64          `json:"gasUsed,omitempty"`
+	TransactionID *big.Int        `json:"transactionId,omitempty"`
+	Events        []*walletEvent  `json:"events,omitempty"`
+	Outcomes      []string        `json:"outcomes,omitempty"`
+}
+
+// Status of sent transaction
+const (
+	sentTxStatusSuccess = "success"
+	sentTxStatusFailed  = "failed"
+	sentTxStatusUnknown = "unknown"
+)
+
+// printSentTx prints the JSON output of tx with its decoded receipt, nothing
+// is printed for text output. The error is returned if tx is not known to
+// be successful.
+func (cli *CLI) printSentTx(tx *types.Transaction, from common.Address, result *receiptResult) error {
+	output, err := newSentTxOutput(tx, from, result)
+
+	// the error is printed for the failed transaction
+	if cli.isJSON() && err == nil {
+		cli.printJSON(output)
+	}
+	return err
+}
+
+// newSentTxOutput returns the output of tx with its decoded receipt, and
+// the error if tx is not known to be successful
+func newSentTxOutput(tx *types.Transaction, from common.Address, result *receiptResult) (*sentTxOutput, error) {
+	output := &sentTxOutput{
+		Hash:   tx.Hash(),
+		From:   from,
+		To:     tx.To(),
+		Nonce:  tx.Nonce(),
+		Status: sentTxStatusUnknown,
+	}
+	var err error
+	if result == nil {
+		err = newErrorf(ErrCodeRPC, "the receipt of transaction %s is unknown", tx.Hash().String())
+	} else {
+		if result.Success {
+			output.Status = sentTxStatusSuccess
+		} else {
+			output.Status = sentTxStatusFailed
+			err = newErrorf(ErrCodeTxFailed, "transaction %s reverted", tx.Hash().String())
+		}
+		output.GasUsed = result.Receipt.GasUsed
+		output.Events = result.Events
+		output.Outcomes = result.Outcomes
+		if e := result.submission(); e != nil {
+			output.TransactionID = e.TransactionID
+		}
+	}
+
+	return output, err
+}
@@ -0,0 +1,37 @@
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"testing"
+)
+
+func TestPrintErrorJSON(t *testing.T) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cli := &CLI{output: OutputJSON, stdout: w}
+	cli.printError(newError(ErrCodeNotOwner, errors.New("not the owner")))
+	w.Close()
+
+	var output struct {
+		Error jsonError `json:"error"`
+	}
+	if err := json.NewDecoder(r).Decode(&output); err != nil {
+		t.Fatal(err)
+	}
+	if output.Error.Code != ErrCodeNotOwner || output.Error.Message != "not the owner" {
+		t.Fatalf("got error %+v", output.Error)
+	}
+}
+
+func TestOutput(t *testing.T) {
+	cli := NewCLI()
+
+	cli.TestCommand("--output json owner list")
+	cli.TestCommand("--output json list")
+	cli.TestCommand("--output json info 9")
+}
@@ -15,8 +15,9 @@ func (cli *CLI) buildOwnerCmd() *cobra.Command {
 		Use:   "owner [list|add|check|remove|replace]",
 		Short: "Manage contract owners",
 		Args:  cobra.MinimumNArgs(1),
-		Run: func(cmd *cobra.Command, args []string) {
-			return
+		RunE: func(cmd *cobra.Command, args []string) error {
+			fmt.Fprint(os.Stderr, cmd.UsageString())
+			return newErrorf(ErrCodeInvalidArgument, "Error: unknown command %q for %q", args[0], cmd.CommandPath())
 		},
 	}
 
@@ -34,9 +35,8 @@ func (cli *CLI) buildOwnerListCmd() *cobra.Command {
 		Use:   "list",
 		Short: "List all owners",
 		Args:  cobra.MinimumNArgs(0),
-		Run: func(cmd *cobra.Command, args []string) {
-			cli.OwnerList()
-			return
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cli.OwnerList()
 		},
 	}
 
@@ -49,46 +49,40 @@ func (cli *CLI) buildOwnerAddCmd() *cobra.Command {
 		Short:                 "Allows to add a new owner. Transaction has to be sent by wallet",
 		Args:                  cobra.MinimumNArgs(1),
 		DisableFlagsInUseLine: true,
-		Run: func(cmd *cobra.Command, args []string) {
+		RunE: func(cmd *cobra.Command, args []string) error {
 			var err error
 
 			fromAddress := viper.GetString("from")
 			if fromAddress == "" || !common.IsHexAddress(fromAddress) {
-				fmt.Println("Error: not set from address of owner")
 				fmt.Fprint(os.Stderr, cmd.UsageString())
-				return
+				return newErrorf(ErrCodeInvalidArgument, "Error: not set from address of owner")
 			}
 
 			ownerStr := args[0]
 			if ownerStr == "" && !common.IsHexAddress(ownerStr) {
-				fmt.Println("Error: not set owners or invalid")
-				fmt.Println(cmd.UsageString())
-				return
+				fmt.Fprint(os.Stderr, cmd.UsageString())
+				return newErrorf(ErrCodeInvalidArgument, "Error: not set owners or invalid")
 			}
 			owner := common.HexToAddress(ownerStr)
 
 			simpleRegistry, err := cli.GetSimpleRegistry()
 			if err != nil {
-				fmt.Println("GetSimpleRegistry Error: ", err)
-				return
+				return newErrorf(ErrCodeRPC, "GetSimpleRegistry Error: %v", err)
 			}
 			isowner, err := simpleRegistry.IsOwner(nil, owner)
 			if err != nil {
-				fmt.Printf("Failed to call GetOwners: %v[%s]\n", err, cli.contractAddress)
-				return
+				return newErrorf(ErrCodeRPC, "Failed to call GetOwners: %v[%s]", err, cli.contractAddress)
 			}
 			if isowner {
-				fmt.Printf("Address[%s] is one of the owners\n", ownerStr)
-				return
+				return newErrorf(ErrCodeInvalidArgument, "Address[%s] is one of the owners", ownerStr)
 			}
 
 			data, err := cli.GetMethodData("addOwner", owner)
 			if err != nil {
-				fmt.Println("GetMethodData error: ", err)
-				return
+				return newErrorf(ErrCodeInvalidArgument, "GetMethodData error: %v", err)
 			}
 
-			cli.SubmitTransaction(fromAddress, common.HexToAddress(cli.contractAddress), big.NewInt(0), data)
+			return cli.SubmitTransaction(fromAddress, common.HexToAddress(cli.contractAddress), big.NewInt(0), data)
 		},
 	}
 
@@ -101,45 +95,39 @@ func (cli *CLI) buildOwnerRemoveCmd() *cobra.Command {
 		Short:                 "Allows to remove an owner. Transaction has to be sent by wallet",
 		Args:                  cobra.MinimumNArgs(1),
 		DisableFlagsInUseLine: true,
-		Run: func(cmd *cobra.Command, args []string) {
+		RunE: func(cmd *cobra.Command, args []string) error {
 			var err error
 			fromAddress := viper.GetString("from")
 			if fromAddress == "" || !common.IsHexAddress(fromAddress) {
-				fmt.Println("Error: not set from address of owner")
 				fmt.Fprint(os.Stderr, cmd.UsageString())
-				return
+				return newErrorf(ErrCodeInvalidArgument, "Error: not set from address of owner")
 			}
 
 			ownerStr := args[0]
 			if ownerStr == "" && !common.IsHexAddress(ownerStr) {
-				fmt.Println("Error: not set owners or invalid")
-				fmt.Println(cmd.UsageString())
-				return
+				fmt.Fprint(os.Stderr, cmd.UsageString())
+				return newErrorf(ErrCodeInvalidArgument, "Error: not set owners or invalid")
 			}
 			owner := common.HexToAddress(ownerStr)
 
 			simpleRegistry, err := cli.GetSimpleRegistry()
 			if err != nil {
-				fmt.Println("GetSimpleRegistry Error: ", err)
-				return
+				return newErrorf(ErrCodeRPC, "GetSimpleRegistry Error: %v", err)
 			}
 			isowner, err := simpleRegistry.IsOwner(nil, owner)
 			if err != nil {
-				fmt.Printf("Failed to call GetOwners: %v[%s]\n", err, cli.contractAddress)
-				return
+				return newErrorf(ErrCodeRPC, "Failed to call GetOwners: %v[%s]", err, cli.contractAddress)
 			}
 			if !isowner {
-				fmt.Printf("Address[%s] is NOT owner\n", ownerStr)
-				return
+				return newErrorf(ErrCodeNotOwner, "Address[%s] is NOT owner", ownerStr)
 			}
 
 			data, err := cli.GetMethodData("removeOwner", owner)
 			if err != nil {
-				fmt.Println("GetMethodData error: ", err)
-				return
+				return newErrorf(ErrCodeInvalidArgument, "GetMethodData error: %v", err)
 			}
 
-			cli.SubmitTransaction(fromAddress, common.HexToAddress(cli.contractAddress), big.NewInt(0), data)
+			return cli.SubmitTransaction(fromAddress, common.HexToAddress(cli.contractAddress), big.NewInt(0), data)
 		},
 	}
 
@@ -152,53 +140,46 @@ func (cli *CLI) buildOwnerReplaceCmd() *cobra.Command {
 		Short:                 "Allows to replace an owner with a new owner. Transaction has to be sent by wallet",
 		Args:                  cobra.MinimumNArgs(2),
 		DisableFlagsInUseLine: true,
-		Run: func(cmd *cobra.Command, args []string) {
+		RunE: func(cmd *cobra.Command, args []string) error {
 			var err error
 			fromAddress := viper.GetString("from")
 			if fromAddress == "" || !common.IsHexAddress(fromAddress) {
-				fmt.Println("Error: not set from address of owner")
 				fmt.Fprint(os.Stderr, cmd.UsageString())
-				return
+				return newErrorf(ErrCodeInvalidArgument, "Error: not set from address of owner")
 			}
 
 			ownerStr := args[0]
 			if ownerStr == "" || !common.IsHexAddress(ownerStr) {
-				fmt.Println("Error: not set owner or owner invalid")
-				fmt.Println(cmd.UsageString())
-				return
+				fmt.Fprint(os.Stderr, cmd.UsageString())
+				return newErrorf(ErrCodeInvalidArgument, "Error: not set owner or owner invalid")
 			}
 			owner := common.HexToAddress(ownerStr)
 
 			newOwnerStr := args[1]
 			if newOwnerStr == "" || !common.IsHexAddress(newOwnerStr) {
-				fmt.Println("Error: not set new owner or new owner invalid")
-				fmt.Println(cmd.UsageString())
-				return
+				fmt.Fprint(os.Stderr, cmd.UsageString())
+				return newErrorf(ErrCodeInvalidArgument, "Error: not set new owner or new owner invalid")
 			}
 			newOwner := common.HexToAddress(newOwnerStr)
 
 			simpleRegistry, err := cli.GetSimpleRegistry()
 			if err != nil {
-				fmt.Println("GetSimpleRegistry Error: ", err)
-				return
+				return newErrorf(ErrCodeRPC, "GetSimpleRegistry Error: %v", err)
 			}
 			isowner, err := simpleRegistry.IsOwner(nil, owner)
 			if err != nil {
-				fmt.Printf("Failed to call GetOwners: %v[%s]\n", err, cli.contractAddress)
-				return
+				return newErrorf(ErrCodeRPC, "Failed to call GetOwners: %v[%s]", err, cli.contractAddress)
 			}
 			if !isowner {
-				fmt.Printf("Address[%s] is NOT owner\n", ownerStr)
-				return
+				return newErrorf(ErrCodeNotOwner, "Address[%s] is NOT owner", ownerStr)
 			}
 
 			data, err := cli.GetMethodData("replaceOwner", owner, newOwner)
 			if err != nil {
-				fmt.Println("GetMethodData error: ", err)
-				return
+				return newErrorf(ErrCodeInvalidArgument, "GetMethodData error: %v", err)
 			}
 
-			cli.SubmitTransaction(fromAddress, common.HexToAddress(cli.contractAddress), big.NewInt(0), data)
+			return cli.SubmitTransaction(fromAddress, common.HexToAddress(cli.contractAddress), big.NewInt(0), data)
 		},
 	}
 
@@ -210,17 +191,16 @@ func (cli *CLI) buildOwnerCheckCmd() *cobra.Command {
 		Use:   "check <owner>",
 		Short: "Check whether an address is owner",
 		Args:  cobra.MinimumNArgs(1),
-		Run: func(cmd *cobra.Command, args []string) {
+		RunE: func(cmd *cobra.Command, args []string) error {
 
 			ownerStr := args[0]
 			if ownerStr == "" && !common.IsHexAddress(ownerStr) {
-				fmt.Println("Error: not set owners or invalid")
-				fmt.Println(cmd.UsageString())
-				return
+				fmt.Fprint(os.Stderr, cmd.UsageString())
+				return newErrorf(ErrCodeInvalidArgument, "Error: not set owners or invalid")
 			}
 			owner := common.HexToAddress(ownerStr)
 
-			cli.OwnerCheck(owner)
+			return cli.OwnerCheck(owner)
 		},
 	}
 
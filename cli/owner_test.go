@@ -11,3 +11,11 @@ func TestOwner(t *testing.T) {
 	cli.TestCommand("owner replace 0xF67cD3b82491cB41aaC69ab579670D1839006476 0x536e9f4e54F2A32BB47F7223a7b621AFe509cCb2")
 	cli.TestCommand("owner check 0xF67cD3b82491cB41aaC69ab579670D1839006476")
 }
+
+func TestOwnerUnknown(t *testing.T) {
+	cli := NewCLI()
+	cli.rootCmd.SetArgs([]string{"owner", "bogus"})
+	if err := cli.rootCmd.Execute(); ExitCode(err) != ExitInvalidArgument {
+		t.Fatalf("owner with unknown command got error %v", err)
+	}
+}
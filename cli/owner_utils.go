@@ -1,70 +1,54 @@
 package cli
 
 import (
+	"context"
 	"fmt"
-	"log"
-	"strings"
 
-	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/newtonproject/MultiSignatureWallet/msw"
 )
 
 // OwnerList OwnerList
-func (cli *CLI) OwnerList() {
-	var err error
-
-	simpleRegistry, err := cli.GetSimpleRegistry()
+func (cli *CLI) OwnerList() error {
+	w, err := cli.GetWallet()
 	if err != nil {
-		fmt.Println("GetSimpleRegistry Error: ", err)
-		return
+		return newErrorf(ErrCodeRPC, "GetWallet Error: %v", err)
 	}
-	owners, err := simpleRegistry.GetOwners(nil)
+	owners, err := w.Owners(context.Background())
 	if err != nil {
-		log.Fatalf("Failed to call GetOwners: %v[%s]", err, cli.contractAddress)
+		return newErrorf(ErrCodeRPC, "Failed to call GetOwners: %v[%s]", err, cli.contractAddress)
+	}
+
+	if cli.isJSON() {
+		cli.printJSON(struct {
+			Owners []common.Address `json:"owners"`
+		}{owners})
+		return nil
 	}
 
 	for _, v := range owners {
 		fmt.Println(v.String())
 	}
 
+	return nil
 }
 
 // OwnerCheck OwnerCheck
-func (cli *CLI) OwnerCheck(owner common.Address) {
-	var err error
-
-	simpleRegistry, err := cli.GetSimpleRegistry()
+func (cli *CLI) OwnerCheck(owner common.Address) error {
+	w, err := cli.GetWallet()
 	if err != nil {
-		fmt.Println("GetSimpleRegistry Error: ", err)
-		return
+		return newErrorf(ErrCodeRPC, "GetWallet Error: %v", err)
 	}
-	isowner, err := simpleRegistry.IsOwner(nil, owner)
+	isowner, err := w.IsOwner(context.Background(), owner)
 	if err != nil {
-		fmt.Printf("Failed to call GetOwners: %v[%s]\n", err, cli.contractAddress)
-		return
+		return newErrorf(ErrCodeRPC, "Failed to call IsOwner: %v[%s]", err, cli.contractAddress)
 	}
 	fmt.Println(isowner)
+
+	return nil
 }
 
 // GetMethodData GetMethodData
 func (cli *CLI) GetMethodData(name string, args ...interface{}) ([]byte, error) {
-
-	parsed, err := abi.JSON(strings.NewReader(MultiSigWalletWithDailyLimitABI))
-	if err != nil {
-		return nil, fmt.Errorf("JSON err: %v", err)
-	}
-
-	method, exist := parsed.Methods[name]
-	if !exist {
-		return nil, fmt.Errorf("method '%s' not found", name)
-	}
-
-	arguments, err := method.Inputs.Pack(args...)
-	if err != nil {
-		return nil, err
-	}
-
-	// Pack up the method ID too if not a constructor and return
-	return append(method.Id(), arguments...), nil
-
+	return msw.Pack(name, args...)
 }
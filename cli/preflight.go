@@ -0,0 +1,105 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/newtonproject/MultiSignatureWallet/msw"
+)
+
+// preflight checks the signed transaction from from against the chain
+// state, and returns the problems which would fail it on chain
+func (cli *CLI) preflight(ctx context.Context, tx *types.Transaction, from common.Address) []string {
+	var problems []string
+	if cli.client == nil {
+		if err := cli.BuildClient(); err != nil {
+			return append(problems, fmt.Sprintf("connect to node error: %v", err))
+		}
+	}
+
+	if tx.Protected() {
+		chainID, err := cli.client.NetworkID(ctx)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("get chain ID error: %v", err))
+		} else if chainID.Cmp(tx.ChainId()) != 0 {
+			problems = append(problems, fmt.Sprintf("chain ID of node is %s, but the transaction is signed for %s", chainID.String(), tx.ChainId().String()))
+		}
+	}
+
+	nonce, err := cli.client.PendingNonceAt(ctx, from)
+	if err != nil {
+		problems = append(problems, fmt.Sprintf("get nonce error: %v", err))
+	} else if tx.Nonce() < nonce {
+		problems = append(problems, fmt.Sprintf("nonce %d has been used, the next nonce of %s is %d", tx.Nonce(), from.String(), nonce))
+	}
+
+	balance, err := cli.client.BalanceAt(ctx, from, nil)
+	if err != nil {
+		problems = append(problems, fmt.Sprintf("get balance error: %v", err))
+	} else if balance.Cmp(tx.Cost()) < 0 {
+		problems = append(problems, fmt.Sprintf("balance %s of %s can not cover the cost %s of gas and value",
+			getWeiAmountTextUnitByUnit(balance, UnitETH), from.String(), getWeiAmountTextUnitByUnit(tx.Cost(), UnitETH)))
+	}
+
+	if tx.To() == nil {
+		return problems
+	}
+	if _, err := msw.DecodeCall(tx.Data()); err != nil {
+		// not a call to the wallet
+		return problems
+	}
+	w, err := msw.NewWallet(*tx.To(), cli.client, nil)
+	if err != nil {
+		return append(problems, fmt.Sprintf("wallet %s error: %v", tx.To().String(), err))
+	}
+	if err := checkWalletCall(ctx, w, from, tx.Data()); err != nil {
+		problems = append(problems, err.Error())
+	}
+
+	return problems
+}
+
+// checkWalletCall checks from can make the call of data to the wallet on the
+// current chain state, the data not calling the wallet is not checked
+func checkWalletCall(ctx context.Context, w *msw.Wallet, from common.Address, data []byte) error {
+	call, err := msw.DecodeCall(data)
+	if err != nil {
+		return nil
+	}
+
+	var id *big.Int
+	if len(call.Args) > 0 {
+		id, _ = call.Args[0].Value.(*big.Int)
+	}
+	switch {
+	case call.Method == "confirmTransaction" && id != nil:
+		return w.CheckConfirm(ctx, from, id)
+	case call.Method == "revokeConfirmation" && id != nil:
+		return w.CheckRevoke(ctx, from, id)
+	case call.Method == "executeTransaction" && id != nil:
+		return w.CheckExecute(ctx, from, id)
+	}
+	return w.CheckOwner(ctx, from)
+}
+
+// checkBeforeBroadcast shows the problems of preflight, and refuses to
+// broadcast if any unless force
+func (cli *CLI) checkBeforeBroadcast(ctx context.Context, tx *types.Transaction, from common.Address, force bool) error {
+	problems := cli.preflight(ctx, tx, from)
+	if len(problems) == 0 {
+		return nil
+	}
+
+	fmt.Printf("Pre-flight checks failed (%d):\n", len(problems))
+	for _, p := range problems {
+		fmt.Printf("\t%s\n", p)
+	}
+	if force {
+		fmt.Println("Warning: broadcast anyway with --force")
+		return nil
+	}
+	return newErrorf(ErrCodePreflightFailed, "Error: refuse to broadcast transaction %s, use --force to broadcast anyway", tx.Hash().String())
+}
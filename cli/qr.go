@@ -0,0 +1,97 @@
+package cli
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// addQRFlags adds the flags to export the file saved by cmd as QR codes
+func addQRFlags(flags *pflag.FlagSet) {
+	flags.Bool("qr", false, "show the file saved as numbered QR codes in terminal")
+	flags.String("qr-png", "", "save the file saved as numbered QR code PNG images with the path `prefix`")
+	flags.Int("qr-chunk", defaultQRChunkSize, "the `size` of data in each QR code")
+}
+
+// exportQR shows the file in path as QR codes or saves them as images,
+// as the flags of cmd set
+func (cli *CLI) exportQR(cmd *cobra.Command, path string) error {
+	show, _ := cmd.Flags().GetBool("qr")
+	prefix, _ := cmd.Flags().GetString("qr-png")
+	if !show && prefix == "" {
+		return nil
+	}
+	size, _ := cmd.Flags().GetInt("qr-chunk")
+
+	payload, err := ioutil.ReadFile(path)
+	if err != nil {
+		return newError(ErrCodeFile, err)
+	}
+	chunks := qrChunks(payload, size)
+	if len(chunks) > maxQRChunks {
+		return newErrorf(ErrCodeInvalidArgument, "Error: %d QR codes of %s are more than %d, use a larger --qr-chunk", len(chunks), path, maxQRChunks)
+	}
+	for i, chunk := range chunks {
+		bm, err := qrEncode(chunk)
+		if err != nil {
+			return newErrorf(ErrCodeInvalidArgument, "Error: encode QR code %d/%d: %v", i+1, len(chunks), err)
+		}
+		if show {
+			fmt.Printf("QR code %d/%d of %s:\n", i+1, len(chunks), path)
+			printQR(os.Stdout, bm)
+			fmt.Println(chunk)
+		}
+		if prefix != "" {
+			image := fmt.Sprintf("%s-%d-of-%d.png", prefix, i+1, len(chunks))
+			if err := saveQRPNG(bm, image, 8); err != nil {
+				return newError(ErrCodeFile, err)
+			}
+			fmt.Println("Successfully save QR code to file", image)
+		}
+	}
+
+	return nil
+}
+
+func (cli *CLI) buildQRImportCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:                   "qr-import <chunkfile|image>... <--out file>",
+		Short:                 "Reassemble the file from the QR code chunks in text files or PNG, JPEG, GIF images",
+		Args:                  cobra.MinimumNArgs(1),
+		DisableFlagsInUseLine: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			outStr, _ := cmd.Flags().GetString("out")
+			if outStr == "" {
+				fmt.Fprint(os.Stderr, cmd.UsageString())
+				return newErrorf(ErrCodeInvalidArgument, "Error: flag out is required")
+			}
+
+			var chunks []string
+			for _, path := range args {
+				c, err := readQRChunks(path)
+				if err != nil {
+					return newError(ErrCodeFile, err)
+				}
+				chunks = append(chunks, c...)
+			}
+			payload, err := qrAssemble(chunks)
+			if err != nil {
+				return newErrorf(ErrCodeInvalidArgument, "Error: reassemble QR chunks: %v", err)
+			}
+
+			if err := ioutil.WriteFile(outStr, payload, 0644); err != nil {
+				return newError(ErrCodeFile, err)
+			}
+			fmt.Printf("Successfully import %d bytes from %d QR chunks to file %s\n", len(payload), len(chunks), outStr)
+
+			return nil
+		},
+	}
+
+	cmd.Flags().String("out", "", "file `path` to save the file reassembled")
+
+	return cmd
+}
@@ -0,0 +1,81 @@
+package cli
+
+import (
+	"bytes"
+	"fmt"
+	"hash/crc32"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestQRChunks(t *testing.T) {
+	payload := bytes.Repeat([]byte(`{"nonce":1,"to":"0x0000000000000000000000000000000000000000"}`), 20)
+	chunks := qrChunks(payload, 100)
+	if len(chunks) < 2 {
+		t.Fatalf("got %d chunks, want more than one", len(chunks))
+	}
+
+	// the chunks can be read in any order, and repeatedly
+	reversed := []string{chunks[0]}
+	for i := len(chunks) - 1; i >= 0; i-- {
+		reversed = append(reversed, chunks[i])
+	}
+	got, err := qrAssemble(reversed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, payload) {
+		t.Fatal("reassembled payload is not the same")
+	}
+
+	if _, err := qrAssemble(chunks[1:]); err == nil || !strings.Contains(err.Error(), "missing chunks 1") {
+		t.Fatalf("got error %v with missing chunk", err)
+	}
+
+	tampered := append([]string{}, chunks...)
+	tampered[1] = tampered[1][:len(tampered[1])-1] + "A"
+	if tampered[1] == chunks[1] {
+		tampered[1] = tampered[1][:len(tampered[1])-1] + "B"
+	}
+	if _, err := qrAssemble(tampered); err == nil {
+		t.Fatal("tampered chunk is accepted")
+	}
+
+	if _, err := qrAssemble([]string{fmt.Sprintf("%s:1/%d:%s:%08x:%s", qrChunkPrefix, maxQRChunks+1, "00000000", crc32.ChecksumIEEE([]byte("AA")), "AA")}); err == nil {
+		t.Fatal("chunk of too many total is accepted")
+	}
+
+	other := qrChunks([]byte("other"), 100)
+	if _, err := qrAssemble(append(chunks, other...)); err == nil {
+		t.Fatal("chunk of other payload is accepted")
+	}
+}
+
+func TestQRImage(t *testing.T) {
+	dir, err := ioutil.TempDir("", "qr")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	chunk := qrChunks([]byte("0xf86b808504a817c800825208"), 0)[0]
+	bm, err := qrEncode(chunk)
+	if err != nil {
+		t.Fatal(err)
+	}
+	image := filepath.Join(dir, "tx-1-of-1.png")
+	if err := saveQRPNG(bm, image, 4); err != nil {
+		t.Fatal(err)
+	}
+
+	chunks, err := readQRChunks(image)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(chunks) != 1 || chunks[0] != chunk {
+		t.Fatalf("got chunks %v, want %s", chunks, chunk)
+	}
+}
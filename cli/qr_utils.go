@@ -0,0 +1,251 @@
+package cli
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"image"
+	"image/color"
+	_ "image/gif"  // decode GIF images
+	_ "image/jpeg" // decode JPEG images
+	"image/png"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/makiuchi-d/gozxing"
+	"github.com/makiuchi-d/gozxing/qrcode"
+)
+
+// qrChunkPrefix is the prefix of the chunk in QR code, the chunk is
+// MSW:<index>/<total>:<payload id>:<crc32 of data>:<data>
+const qrChunkPrefix = "MSW"
+
+// defaultQRChunkSize is the size of data in a QR code, small enough to be
+// shown in terminal
+const defaultQRChunkSize = 400
+
+// maxQRChunks is the maximum number of chunks of a payload, the total of
+// the chunk read is not trusted
+const maxQRChunks = 10000
+
+var errQRChunk = errors.New("invalid QR chunk")
+
+// qrPayloadID returns the id of payload, the chunks of one payload have
+// the same id
+func qrPayloadID(payload []byte) string {
+	sum := sha256.Sum256(payload)
+	return hex.EncodeToString(sum[:4])
+}
+
+// qrChunks splits the base64 of payload into the numbered chunks with
+// checksums, size is the size of data in each chunk
+func qrChunks(payload []byte, size int) []string {
+	if size <= 0 {
+		size = defaultQRChunkSize
+	}
+	data := base64.StdEncoding.EncodeToString(payload)
+	id := qrPayloadID(payload)
+	total := (len(data) + size - 1) / size
+
+	chunks := make([]string, 0, total)
+	for i := 0; i < total; i++ {
+		end := (i + 1) * size
+		if end > len(data) {
+			end = len(data)
+		}
+		part := data[i*size : end]
+		chunks = append(chunks, fmt.Sprintf("%s:%d/%d:%s:%08x:%s", qrChunkPrefix, i+1, total, id, crc32.ChecksumIEEE([]byte(part)), part))
+	}
+	return chunks
+}
+
+type qrChunk struct {
+	index, total int
+	id           string
+	data         string
+}
+
+func parseQRChunk(s string) (*qrChunk, error) {
+	fields := strings.SplitN(strings.TrimSpace(s), ":", 5)
+	if len(fields) != 5 || fields[0] != qrChunkPrefix {
+		return nil, errQRChunk
+	}
+	numbers := strings.SplitN(fields[1], "/", 2)
+	if len(numbers) != 2 {
+		return nil, errQRChunk
+	}
+	index, err := strconv.Atoi(numbers[0])
+	if err != nil {
+		return nil, errQRChunk
+	}
+	total, err := strconv.Atoi(numbers[1])
+	if err != nil || index < 1 || index > total {
+		return nil, errQRChunk
+	}
+	if total > maxQRChunks {
+		return nil, fmt.Errorf("%v: total %d is more than %d", errQRChunk, total, maxQRChunks)
+	}
+	if fmt.Sprintf("%08x", crc32.ChecksumIEEE([]byte(fields[4]))) != fields[3] {
+		return nil, fmt.Errorf("checksum of chunk %d/%d error", index, total)
+	}
+	return &qrChunk{index, total, fields[2], fields[4]}, nil
+}
+
+// qrAssemble reassembles the payload from the chunks in any order, the
+// duplicate chunks are ignored
+func qrAssemble(chunks []string) ([]byte, error) {
+	if len(chunks) == 0 {
+		return nil, errors.New("no QR chunk")
+	}
+
+	var (
+		parts []string
+		id    string
+	)
+	for _, s := range chunks {
+		c, err := parseQRChunk(s)
+		if err != nil {
+			return nil, err
+		}
+		if parts == nil {
+			parts = make([]string, c.total)
+			id = c.id
+		}
+		if c.id != id || c.total != len(parts) {
+			return nil, fmt.Errorf("chunk %d/%d of payload %s is not of payload %s", c.index, c.total, c.id, id)
+		}
+		parts[c.index-1] = c.data
+	}
+
+	var missing []string
+	for i, p := range parts {
+		if p == "" {
+			missing = append(missing, strconv.Itoa(i+1))
+		}
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("missing chunks %s of %d", strings.Join(missing, ","), len(parts))
+	}
+
+	payload, err := base64.StdEncoding.DecodeString(strings.Join(parts, ""))
+	if err != nil {
+		return nil, err
+	}
+	if qrPayloadID(payload) != id {
+		return nil, fmt.Errorf("checksum of payload %s error", id)
+	}
+	return payload, nil
+}
+
+func qrEncode(content string) (*gozxing.BitMatrix, error) {
+	hints := map[gozxing.EncodeHintType]interface{}{
+		gozxing.EncodeHintType_ERROR_CORRECTION: "M",
+		gozxing.EncodeHintType_MARGIN:           2,
+	}
+	return qrcode.NewQRCodeWriter().Encode(content, gozxing.BarcodeFormat_QR_CODE, 0, 0, hints)
+}
+
+// printQR prints the QR code with half blocks, two modules in a line, the
+// light modules are the blocks for the dark terminal
+func printQR(w io.Writer, bm *gozxing.BitMatrix) {
+	width, height := bm.GetWidth(), bm.GetHeight()
+	light := func(x, y int) bool {
+		return y >= height || !bm.Get(x, y)
+	}
+	for y := 0; y < height; y += 2 {
+		var b strings.Builder
+		for x := 0; x < width; x++ {
+			switch top, bottom := light(x, y), light(x, y+1); {
+			case top && bottom:
+				b.WriteString("█")
+			case top:
+				b.WriteString("▀")
+			case bottom:
+				b.WriteString("▄")
+			default:
+				b.WriteString(" ")
+			}
+		}
+		fmt.Fprintln(w, b.String())
+	}
+}
+
+// saveQRPNG saves the QR code as PNG image, scale pixels a module
+func saveQRPNG(bm *gozxing.BitMatrix, path string, scale int) error {
+	width, height := bm.GetWidth(), bm.GetHeight()
+	img := image.NewGray(image.Rect(0, 0, width*scale, height*scale))
+	for y := 0; y < height*scale; y++ {
+		for x := 0; x < width*scale; x++ {
+			c := color.Gray{Y: 255}
+			if bm.Get(x/scale, y/scale) {
+				c.Y = 0
+			}
+			img.SetGray(x, y, c)
+		}
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return png.Encode(f, img)
+}
+
+// decodeQRImage decodes the QR code in the PNG, JPEG or GIF image
+func decodeQRImage(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	img, _, err := image.Decode(f)
+	if err != nil {
+		return "", err
+	}
+	bmp, err := gozxing.NewBinaryBitmapFromImage(img)
+	if err != nil {
+		return "", err
+	}
+	result, err := qrcode.NewQRCodeReader().Decode(bmp, nil)
+	if err != nil {
+		return "", err
+	}
+	return result.GetText(), nil
+}
+
+// readQRChunks reads the chunks in the image, or the lines of chunks in
+// the text file
+func readQRChunks(path string) ([]string, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".png", ".jpg", ".jpeg", ".gif":
+		chunk, err := decodeQRImage(path)
+		if err != nil {
+			return nil, fmt.Errorf("decode QR image %s error: %v", path, err)
+		}
+		return []string{chunk}, nil
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var chunks []string
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		if line := strings.TrimSpace(scanner.Text()); strings.HasPrefix(line, qrChunkPrefix+":") {
+			chunks = append(chunks, line)
+		}
+	}
+	return chunks, scanner.Err()
+}
@@ -0,0 +1,219 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/newtonproject/MultiSignatureWallet/msw"
+)
+
+// receiptResult is the decoded receipt of a transaction sent to the wallet
+type receiptResult struct {
+	Receipt  *types.Receipt
+	Success  bool
+	Events   []*walletEvent
+	Outcomes []string
+}
+
+// submission returns the Submission event in the receipt, or nil
+func (r *receiptResult) submission() *walletEvent {
+	for _, e := range r.Events {
+		if e.Name == EventSubmission {
+			return e
+		}
+	}
+	return nil
+}
+
+// newWalletEventStruct returns the event struct of binding for name
+func newWalletEventStruct(name string) interface{} {
+	switch name {
+	case EventSubmission:
+		return new(msw.MultiSigWalletWithDailyLimitSubmission)
+	case EventConfirmation:
+		return new(msw.MultiSigWalletWithDailyLimitConfirmation)
+	case EventRevocation:
+		return new(msw.MultiSigWalletWithDailyLimitRevocation)
+	case EventExecution:
+		return new(msw.MultiSigWalletWithDailyLimitExecution)
+	case EventExecutionFailure:
+		return new(msw.MultiSigWalletWithDailyLimitExecutionFailure)
+	case EventDeposit:
+		return new(msw.MultiSigWalletWithDailyLimitDeposit)
+	case EventOwnerAddition:
+		return new(msw.MultiSigWalletWithDailyLimitOwnerAddition)
+	case EventOwnerRemoval:
+		return new(msw.MultiSigWalletWithDailyLimitOwnerRemoval)
+	case EventRequirementChange:
+		return new(msw.MultiSigWalletWithDailyLimitRequirementChange)
+	case EventDailyLimitChange:
+		return new(msw.MultiSigWalletWithDailyLimitDailyLimitChange)
+	}
+	return nil
+}
+
+// decodeWalletLogs decodes the logs emitted by wallet, other logs are skipped
+func decodeWalletLogs(logs []*types.Log, wallet common.Address) ([]*walletEvent, error) {
+	parsed, _, err := parsedABIs()
+	if err != nil {
+		return nil, err
+	}
+	contract := bind.NewBoundContract(wallet, parsed, nil, nil, nil)
+
+	var events []*walletEvent
+	for _, log := range logs {
+		if log == nil || log.Address != wallet || len(log.Topics) == 0 {
+			continue
+		}
+		for name, event := range parsed.Events {
+			if event.Id() != log.Topics[0] {
+				continue
+			}
+			out := newWalletEventStruct(name)
+			if out == nil {
+				break
+			}
+			if err := contract.UnpackLog(out, name, *log); err != nil {
+				return nil, fmt.Errorf("unpack %s log error: %v", name, err)
+			}
+			e := toWalletEvent(out)
+			e.BlockNumber = log.BlockNumber
+			e.TxHash = log.TxHash
+			e.Index = log.Index
+			e.Removed = log.Removed
+			events = append(events, e)
+			break
+		}
+	}
+
+	return events, nil
+}
+
+// decodeReceipt checks the status and decodes all wallet events in receipt
+func (cli *CLI) decodeReceipt(receipt *types.Receipt, wallet common.Address) (*receiptResult, error) {
+	result := &receiptResult{
+		Receipt: receipt,
+		Success: receipt.Status == types.ReceiptStatusSuccessful,
+	}
+	if !result.Success {
+		result.Outcomes = append(result.Outcomes, "transaction reverted, nothing changed in contract wallet")
+		return result, nil
+	}
+
+	events, err := decodeWalletLogs(receipt.Logs, wallet)
+	if err != nil {
+		return nil, err
+	}
+	result.Events = events
+
+	confirmed := make(map[string]bool)
+	for _, e := range events {
+		if e.Name == EventConfirmation {
+			confirmed[e.TransactionID.String()] = true
+		}
+	}
+
+	for _, e := range events {
+		var outcome string
+		switch e.Name {
+		case EventSubmission:
+			outcome = fmt.Sprintf("transaction ID %s submitted", e.TransactionID.String())
+		case EventConfirmation:
+			outcome = fmt.Sprintf("transaction ID %s confirmed by %s", e.TransactionID.String(), e.Sender.String())
+		case EventRevocation:
+			outcome = fmt.Sprintf("confirmation of transaction ID %s revoked by %s", e.TransactionID.String(), e.Sender.String())
+		case EventExecution:
+			if !confirmed[e.TransactionID.String()] {
+				outcome = fmt.Sprintf("transaction ID %s executed", e.TransactionID.String())
+			} else if cli.isTxConfirmed(e.TransactionID) {
+				outcome = fmt.Sprintf("confirmation reached quorum and auto-executed transaction ID %s", e.TransactionID.String())
+			} else {
+				outcome = fmt.Sprintf("daily-limit withdrawal executed immediately for transaction ID %s", e.TransactionID.String())
+			}
+		case EventExecutionFailure:
+			outcome = fmt.Sprintf("ExecutionFailure for ID %s", e.TransactionID.String())
+		case EventDeposit:
+			outcome = fmt.Sprintf("deposit %s from %s", getWeiAmountTextUnitByUnit(e.Value, ""), e.Sender.String())
+		case EventOwnerAddition:
+			outcome = fmt.Sprintf("owner %s added", e.Owner.String())
+		case EventOwnerRemoval:
+			outcome = fmt.Sprintf("owner %s removed", e.Owner.String())
+		case EventRequirementChange:
+			outcome = fmt.Sprintf("number of required confirmations changed to %s", e.Value.String())
+		case EventDailyLimitChange:
+			outcome = fmt.Sprintf("daily limit changed to %s", getWeiAmountTextUnitByUnit(e.Value, ""))
+		}
+		result.Outcomes = append(result.Outcomes, outcome)
+	}
+	if len(events) == 0 {
+		result.Outcomes = append(result.Outcomes, "no event of contract wallet emitted")
+	}
+
+	return result, nil
+}
+
+// isTxConfirmed checks whether the transaction ID has enough confirmations,
+// false if it can not be checked
+func (cli *CLI) isTxConfirmed(transactionId *big.Int) bool {
+	simpleRegistry, err := cli.GetSimpleRegistry()
+	if err != nil {
+		return false
+	}
+	ok, err := simpleRegistry.IsConfirmed(nil, transactionId)
+	return err == nil && ok
+}
+
+// showReceipt shows the status, the wallet events and the outcomes of receipt
+func (cli *CLI) showReceipt(receipt *types.Receipt, wallet common.Address) *receiptResult {
+	if receipt == nil {
+		fmt.Println("Error: receipt is nil")
+		return nil
+	}
+
+	result, err := cli.decodeReceipt(receipt, wallet)
+	if err != nil {
+		fmt.Println("Error: decode receipt error: ", err)
+		return nil
+	}
+
+	if result.Success {
+		fmt.Printf("Transaction receipt status: Success (gas used %d)\n", receipt.GasUsed)
+	} else {
+		fmt.Printf("Transaction receipt status: Failed (gas used %d)\n", receipt.GasUsed)
+	}
+
+	if len(result.Events) > 0 {
+		fmt.Printf("Events (%d):\n", len(result.Events))
+		for _, e := range result.Events {
+			fmt.Printf("\t%s\n", e.Text(""))
+		}
+	}
+	if e := result.submission(); e != nil {
+		fmt.Println("TransferID: ", e.TransactionID)
+	}
+	for _, outcome := range result.Outcomes {
+		fmt.Println("Outcome:", outcome)
+	}
+
+	return result
+}
+
+// waitAndShowReceipt waits for tx to be mined and shows its receipt
+func (cli *CLI) waitAndShowReceipt(ctx context.Context, tx *types.Transaction) *receiptResult {
+	fmt.Println("Waiting for transaction receipt...")
+	receipt, err := bind.WaitMined(ctx, cli.client, tx)
+	if err != nil {
+		fmt.Printf("Error: wait tx mined error(%v)\n", err)
+		return nil
+	}
+
+	var wallet common.Address
+	if tx.To() != nil {
+		wallet = *tx.To()
+	}
+	return cli.showReceipt(receipt, wallet)
+}
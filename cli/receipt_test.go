@@ -0,0 +1,47 @@
+package cli
+
+import (
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/newtonproject/MultiSignatureWallet/msw"
+)
+
+func TestDecodeWalletLogs(t *testing.T) {
+	parsed, err := abi.JSON(strings.NewReader(msw.MultiSigWalletWithDailyLimitABI))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wallet := common.HexToAddress("0xeF0b04a14e62434a99C4aF28C6dAb52ba9B1C8F3")
+	owner := common.HexToAddress("0xdDeB86Dd09F16316B67322199E288d7AF35E0806")
+	id := common.BigToHash(big.NewInt(12))
+	logs := []*types.Log{
+		{Address: wallet, Topics: []common.Hash{parsed.Events[EventSubmission].Id(), id}},
+		{Address: wallet, Topics: []common.Hash{parsed.Events[EventConfirmation].Id(), owner.Hash(), id}, Index: 1},
+		{Address: owner, Topics: []common.Hash{parsed.Events[EventExecution].Id(), id}, Index: 2}, // not wallet
+		{Address: wallet, Topics: []common.Hash{parsed.Events[EventExecutionFailure].Id(), id}, Index: 3},
+	}
+
+	events, err := decodeWalletLogs(logs, wallet)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 3 {
+		t.Fatalf("wrong number of events: want 3, got %d", len(events))
+	}
+	want := []string{
+		"Submission(transactionId: 12)",
+		"Confirmation(transactionId: 12, sender: " + owner.String() + ")",
+		"ExecutionFailure(transactionId: 12)",
+	}
+	for i, e := range events {
+		if got := e.Text(""); got != want[i] {
+			t.Errorf("wrong event %d: want %s, got %s", i, want[i], got)
+		}
+	}
+}
@@ -0,0 +1,234 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/spf13/cobra"
+)
+
+// the minimum percentage of gas price bump accepted by nodes to replace the
+// pending transaction
+const replacePriceBump = 10
+
+func (cli *CLI) buildTxSpeedupCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:                   "speedup <hash> [--gasPrice price]",
+		Short:                 "Resend the pending transaction of hash with a higher gas price",
+		Args:                  cobra.ExactArgs(1),
+		DisableFlagsInUseLine: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cli.replaceTx(cmd, args[0], false)
+		},
+	}
+	cmd.Flags().String("gasPrice", "", "the gas `price` in WEI of the replacement, at least 10% higher than the pending transaction")
+
+	return cmd
+}
+
+func (cli *CLI) buildTxCancelCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:                   "cancel <hash> [--gasPrice price]",
+		Short:                 "Cancel the pending transaction of hash by sending zero to self at the same nonce",
+		Args:                  cobra.ExactArgs(1),
+		DisableFlagsInUseLine: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cli.replaceTx(cmd, args[0], true)
+		},
+	}
+	cmd.Flags().String("gasPrice", "", "the gas `price` in WEI of the replacement, at least 10% higher than the pending transaction")
+
+	return cmd
+}
+
+// replaceOutput is the JSON output of speedup and cancel
+type replaceOutput struct {
+	Original    common.Hash `json:"original"`
+	Replacement common.Hash `json:"replacement"`
+	Winner      string      `json:"winner"`
+	*sentTxOutput
+}
+
+// the winner of the replacement
+const (
+	replaceWinnerOriginal    = "original"
+	replaceWinnerReplacement = "replacement"
+)
+
+// replaceTx resends the pending transaction of hashStr at the same nonce
+// with a higher gas price, the same payload to speed it up, or zero to the
+// sender to cancel it, and waits for either to be mined
+func (cli *CLI) replaceTx(cmd *cobra.Command, hashStr string, cancel bool) error {
+	hashBytes, err := hexutil.Decode(hashStr)
+	if err != nil || len(hashBytes) != common.HashLength {
+		return newErrorf(ErrCodeInvalidArgument, "Error: transaction hash(%s) invalid", hashStr)
+	}
+	hash := common.BytesToHash(hashBytes)
+
+	if err := cli.BuildClient(); err != nil {
+		return newErrorf(ErrCodeRPC, "Build client error(%s)", err)
+	}
+	ctx := context.Background()
+	tx, pending, err := cli.client.TransactionByHash(ctx, hash)
+	if err == ethereum.NotFound {
+		return newErrorf(ErrCodeTxNotFound, "Error: transaction %s not found", hash.String())
+	} else if err != nil {
+		return newErrorf(ErrCodeRPC, "TransactionByHash error: %v", err)
+	}
+	if !pending {
+		return newErrorf(ErrCodeInvalidArgument, "Error: transaction %s is already mined", hash.String())
+	}
+	from, err := types.Sender(types.NewEIP155Signer(tx.ChainId()), tx)
+	if err != nil {
+		return newErrorf(ErrCodeInvalidArgument, "Error: recover sender error: %v", err)
+	}
+
+	gasPrice, err := cli.replaceGasPrice(ctx, cmd, tx.GasPrice())
+	if err != nil {
+		return err
+	}
+	var replacement *types.Transaction
+	switch {
+	case cancel:
+		replacement = types.NewTransaction(tx.Nonce(), from, new(big.Int), 21000, gasPrice, nil)
+	case tx.To() == nil:
+		replacement = types.NewContractCreation(tx.Nonce(), tx.Value(), tx.Gas(), gasPrice, tx.Data())
+	default:
+		replacement = types.NewTransaction(tx.Nonce(), *tx.To(), tx.Value(), tx.Gas(), gasPrice, tx.Data())
+	}
+	fmt.Printf("Replace transaction %s of nonce %d, gasPrice %s WEI with gasPrice %s WEI\n",
+		hash.String(), tx.Nonce(), tx.GasPrice().String(), gasPrice.String())
+
+	if err := cli.buildAccount(from.String()); err != nil {
+		return newError(ErrCodeWallet, err)
+	}
+	opts := NewKeyedTransactorByAccount(cli.wallet, cli.account, cli.walletPassword, tx.ChainId())
+	signed, err := opts.Signer(types.NewEIP155Signer(tx.ChainId()), from, replacement)
+	if err != nil {
+		if errorCode(err) == ErrCodeUserAbort {
+			return err
+		}
+		return newError(ErrCodeWallet, err)
+	}
+	if err := cli.client.SendTransaction(ctx, signed); err != nil {
+		return newErrorf(ErrCodeTxFailed, "SendTransaction error: %v", err)
+	}
+	fmt.Println("Replacement transaction hash is: ", signed.Hash().String())
+	entry := newJournalEntry(signed, from)
+	entry.Replaces = &hash
+	if cancel {
+		entry.Action = JournalActionCancel
+	}
+	cli.recordJournal(entry)
+
+	fmt.Println("Waiting for transaction receipt...")
+	winner, receipt, err := waitFirstMined(ctx, cli.client, from, tx, signed)
+	if err == errNonceReplaced {
+		if err := cli.confirmNonce(from, tx.Nonce()); err != nil {
+			fmt.Println("Warning: record nonce in ledger error:", err)
+		}
+		return newErrorf(ErrCodeNonceReplaced, "Error: nonce %d of %s is replaced by another transaction, neither %s nor %s is mined",
+			tx.Nonce(), from.String(), hash.String(), signed.Hash().String())
+	} else if err != nil {
+		return newErrorf(ErrCodeRPC, "Error: wait tx mined error(%v)", err)
+	}
+	if err := cli.confirmNonce(from, tx.Nonce()); err != nil {
+		fmt.Println("Warning: record nonce in ledger error:", err)
+	}
+
+	name := replaceWinnerReplacement
+	if winner == tx {
+		name = replaceWinnerOriginal
+	}
+	fmt.Printf("The %s transaction %s is mined\n", name, winner.Hash().String())
+	var wallet common.Address
+	if winner.To() != nil {
+		wallet = *winner.To()
+	}
+	result := cli.showReceipt(receipt, wallet)
+
+	output, err := newSentTxOutput(winner, from, result)
+	if cli.isJSON() && err == nil {
+		cli.printJSON(&replaceOutput{Original: hash, Replacement: signed.Hash(), Winner: name, sentTxOutput: output})
+	}
+	return err
+}
+
+// replaceGasPrice returns the gas price of the flag gasPrice, or of the gas
+// policy, at least the minimum bump of the pending gas price
+func (cli *CLI) replaceGasPrice(ctx context.Context, cmd *cobra.Command, pending *big.Int) (*big.Int, error) {
+	// ceil(pending * (100 + bump) / 100)
+	min := new(big.Int).Mul(pending, big.NewInt(100+replacePriceBump))
+	min.Add(min, big.NewInt(99)).Div(min, big.NewInt(100))
+
+	if cmd.Flags().Changed("gasPrice") {
+		gasPriceStr, _ := cmd.Flags().GetString("gasPrice")
+		gasPrice, ok := new(big.Int).SetString(gasPriceStr, 10)
+		if !ok || gasPrice.Sign() < 0 {
+			return nil, newErrorf(ErrCodeInvalidArgument, "Error: gasPrice(%s) invalid", gasPriceStr)
+		}
+		if gasPrice.Cmp(min) < 0 {
+			return nil, newErrorf(ErrCodeInvalidArgument, "Error: gasPrice %s is less than %s, %d%% higher than the pending transaction", gasPrice.String(), min.String(), replacePriceBump)
+		}
+		return gasPrice, nil
+	}
+
+	p := cli.gasPolicy()
+	var nodePrice *big.Int
+	if p.Price == nil {
+		var err error
+		if nodePrice, err = cli.client.SuggestGasPrice(ctx); err != nil {
+			return nil, newErrorf(ErrCodeRPC, "SuggestGasPrice error: %v", err)
+		}
+	}
+	gasPrice, _ := p.gasPrice(nodePrice)
+	if gasPrice.Cmp(min) < 0 {
+		gasPrice = min
+	}
+	return gasPrice, nil
+}
+
+// errNonceReplaced is returned by waitFirstMined if the nonce is used by a
+// transaction not waited for
+var errNonceReplaced = errors.New("replaced by another transaction")
+
+// waitFirstMined waits for the first mined of the transactions of from with
+// the same nonce, and returns it with its receipt. It returns
+// errNonceReplaced if the nonce is used but none of them is mined.
+func waitFirstMined(ctx context.Context, client *ethclient.Client, from common.Address, txs ...*types.Transaction) (*types.Transaction, *types.Receipt, error) {
+	queryTicker := time.NewTicker(time.Second)
+	defer queryTicker.Stop()
+
+	nonce := txs[0].Nonce()
+	for {
+		// get the nonce before the receipts, so the receipt of the one
+		// using the nonce is found if it is one of txs
+		used := false
+		if next, err := client.NonceAt(ctx, from, nil); err == nil {
+			used = next > nonce
+		}
+		for _, tx := range txs {
+			receipt, err := client.TransactionReceipt(ctx, tx.Hash())
+			if err == nil && receipt != nil {
+				return tx, receipt, nil
+			}
+		}
+		if used {
+			return nil, nil, errNonceReplaced
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, nil, ctx.Err()
+		case <-queryTicker.C:
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package cli
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+func TestReplace(t *testing.T) {
+	cli := NewCLI()
+
+	cli.TestCommand("tx speedup 0x1234")
+	cli.TestCommand("tx cancel 0xf6b6ddc6a4c3f8d5a0e81c4b31fc24de9eafc5c8e6a1cbd1e7a1b6b3a1de6b0a --gasPrice 100")
+}
+
+func TestReplaceGasPrice(t *testing.T) {
+	cli := NewCLI()
+	cmd := cli.buildTxSpeedupCmd()
+	pending := big.NewInt(1001)
+
+	// at least ceil(1001 * 1.1)
+	cmd.Flags().Set("gasPrice", "1101")
+	if _, err := cli.replaceGasPrice(context.Background(), cmd, pending); errorCode(err) != ErrCodeInvalidArgument {
+		t.Fatalf("got error %v of gas price lower than the bump", err)
+	}
+	cmd.Flags().Set("gasPrice", "1102")
+	if gasPrice, err := cli.replaceGasPrice(context.Background(), cmd, pending); err != nil || gasPrice.Int64() != 1102 {
+		t.Fatalf("got gas price %v, error %v", gasPrice, err)
+	}
+
+	// the fixed gas price of policy is raised to the bump
+	cmd = cli.buildTxCancelCmd()
+	cli.gasPolicy().Price = big.NewInt(500)
+	if gasPrice, err := cli.replaceGasPrice(context.Background(), cmd, pending); err != nil || gasPrice.Int64() != 1102 {
+		t.Fatalf("got gas price %v, error %v", gasPrice, err)
+	}
+}
+
+// NonceService is the eth service of a node where the nonce is used by a
+// transaction not waited for
+type NonceService struct {
+	nonce hexutil.Uint64
+}
+
+func (s *NonceService) GetTransactionCount(address common.Address, block string) hexutil.Uint64 {
+	return s.nonce
+}
+
+func (s *NonceService) GetTransactionReceipt(hash common.Hash) map[string]interface{} {
+	return nil
+}
+
+func TestWaitFirstMinedReplaced(t *testing.T) {
+	server := rpc.NewServer()
+	if err := server.RegisterName("eth", &NonceService{nonce: 6}); err != nil {
+		t.Fatal(err)
+	}
+	defer server.Stop()
+
+	client := ethclient.NewClient(rpc.DialInProc(server))
+	from := common.HexToAddress("0x9B3deA9C636BA262f870f98a1c64d444BF0f6544")
+	original := types.NewTransaction(5, from, new(big.Int), 21000, big.NewInt(1000), nil)
+	replacement := types.NewTransaction(5, from, new(big.Int), 21000, big.NewInt(1100), nil)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if _, _, err := waitFirstMined(ctx, client, from, original, replacement); err != errNonceReplaced {
+		t.Fatalf("got error %v, want %v", err, errNonceReplaced)
+	}
+}
@@ -15,18 +15,17 @@ import (
 
 func (cli *CLI) buildSignCmd() *cobra.Command {
 	signTxCmd := &cobra.Command{
-		Use:                   "sign <filepath> [-u NEW|WEI]",
-		Short:                 "Sign the transaction in the file",
+		Use:                   "sign <filepath> [-u NEW|WEI] [--nointent] [--hex] [--qr] [--qr-png prefix]",
+		Short:                 "Sign the transaction or the bundle of transactions in the file",
 		Args:                  cobra.MinimumNArgs(1),
 		DisableFlagsInUseLine: true,
-		Run: func(cmd *cobra.Command, args []string) {
+		RunE: func(cmd *cobra.Command, args []string) error {
 			var unit string
 			if cmd.Flags().Changed("unit") {
 				unit, _ = cmd.Flags().GetString("unit")
 				if unit != "" && !stringInSlice(unit, UnitList) {
-					fmt.Printf("Unit(%s) for amount error. %s.\n", unit, fmt.Sprintf("Available unit: %s", strings.Join(UnitList, ",")))
 					fmt.Fprint(os.Stderr, cmd.UsageString())
-					return
+					return newErrorf(ErrCodeInvalidArgument, "Unit(%s) for amount error. Available unit: %s.", unit, strings.Join(UnitList, ","))
 				}
 			}
 
@@ -37,22 +36,12 @@ func (cli *CLI) buildSignCmd() *cobra.Command {
 
 			infileStr := args[0]
 
-			if err := cli.applyTxFile(infileStr); err != nil {
-				fmt.Printf("Error apply infile(%s): %v\n", infileStr, err)
-				return
-			}
-
-			fmt.Println("Transaction details are as follows:")
-			cli.printTxIndent()
-			fmt.Println("The data is as follows:")
-			showDataAuto(cli.tran.Data, "", unit)
-
 			var outStr string
 			var err error
 			if cmd.Flags().Changed("out") {
 				outStr, err = cmd.Flags().GetString("out")
 				if err != nil {
-					fmt.Println(err)
+					return newError(ErrCodeInvalidArgument, err)
 				}
 			}
 
@@ -63,11 +52,55 @@ func (cli *CLI) buildSignCmd() *cobra.Command {
 					outStr = infileStr + ".sign"
 				}
 			}
-			cli.signTxAndSave(outStr)
+
+			noIntent, _ := cmd.Flags().GetBool("nointent")
+
+			bundle, err := readBundleFile(infileStr)
+			if err == nil {
+				bundleFileHash, err := fileHash(infileStr)
+				if err != nil {
+					return newErrorf(ErrCodeFile, "Error apply infile(%s): %v", infileStr, err)
+				}
+				if err := cli.signBundleAndSave(bundle, bundleFileHash, outStr, unit, noIntent); err != nil {
+					return err
+				}
+				return cli.exportQR(cmd, outStr)
+			} else if err != errNotBundle {
+				return newErrorf(ErrCodeFile, "Error apply infile(%s): %v", infileStr, err)
+			}
+
+			if err := cli.applyTxFile(infileStr); err != nil {
+				return newErrorf(ErrCodeFile, "Error apply infile(%s): %v", infileStr, err)
+			}
+
+			fmt.Println("Transaction details are as follows:")
+			cli.printTxIndent()
+			printMaxFee(cli.tran.GasLimit, cli.tran.GasPrice, cli.tran.Value)
+			fmt.Println("The data is as follows:")
+			showDataAuto(cli.tran.Data, "", unit)
+			cli.tran.showIntent(unit)
+
+			if err := cli.tran.checkIntent(noIntent); err != nil {
+				return err
+			}
+
+			txFileHash, err := fileHash(infileStr)
+			if err != nil {
+				return newError(ErrCodeFile, err)
+			}
+			hex, _ := cmd.Flags().GetBool("hex")
+
+			if err := cli.signTxAndSave(outStr, txFileHash, hex); err != nil {
+				return err
+			}
+			return cli.exportQR(cmd, outStr)
 		},
 	}
 
 	signTxCmd.Flags().String("out", "", "file `path` to save signed transaction")
+	signTxCmd.Flags().Bool("hex", false, "save the bare signed transaction hex as the legacy format")
+	signTxCmd.Flags().Bool("nointent", false, "sign the transaction without intent, whose data is not verified")
+	addQRFlags(signTxCmd.Flags())
 	signTxCmd.Flags().StringP("unit", "u", UnitETH, fmt.Sprintf("unit for pay amount. %s.", fmt.Sprintf("Available unit: %s", strings.Join(UnitList, ","))))
 
 	return signTxCmd
@@ -75,35 +108,51 @@ func (cli *CLI) buildSignCmd() *cobra.Command {
 
 func (cli *CLI) printTxIndent() {
 	if cli.tran != nil {
-		tByte, err := cli.tran.MarshalJSON(true)
+		tByte, err := cli.tran.marshalIndent()
 		if err == nil {
 			fmt.Println(string(tByte))
 		}
 	}
 }
 
-func (cli *CLI) signTxAndSave(filepath string) {
+// signTxAndSave signs the transaction and saves the signed transaction
+// built from the file of txFileHash, or the bare hex if hex
+func (cli *CLI) signTxAndSave(filepath string, txFileHash common.Hash, hex bool) error {
 	signTx, err := cli.unlockAndSignTx()
 	if err != nil {
-		fmt.Println(err)
-		return
+		return newError(ErrCodeWallet, err)
 	}
 	fmt.Println("Signed Transaction Hash: ", signTx.Hash().String())
 
 	data, err := rlp.EncodeToBytes(signTx)
 	if err != nil {
-		fmt.Println(err)
-		return
+		return newError(ErrCodeInvalidArgument, err)
 	}
 	dataHex := common.ToHex(data)
 	fmt.Printf("Signed Transaction: %s\n", dataHex)
 
-	if err := saveStringToFile(dataHex, filepath); err != nil {
-		fmt.Println(err)
-		return
+	if hex {
+		err = saveStringToFile(dataHex, filepath)
+	} else {
+		err = saveJSONToFile(newSignedTx(signTx, dataHex, cli.tran, txFileHash), filepath)
+	}
+	if err != nil {
+		return newError(ErrCodeFile, err)
+	}
+
+	fmt.Println("Successfully save signed transacion to file", filepath)
+
+	if cli.isJSON() {
+		cli.printJSON(struct {
+			Hash  common.Hash    `json:"hash"`
+			From  common.Address `json:"from"`
+			Nonce uint64         `json:"nonce"`
+			Raw   string         `json:"raw"`
+			File  string         `json:"file"`
+		}{signTx.Hash(), cli.tran.From, signTx.Nonce(), dataHex, filepath})
 	}
 
-	fmt.Println("Successfully save signed transacion hex to file", filepath)
+	return nil
 }
 
 func (cli *CLI) unlockAndSignTx() (*types.Transaction, error) {
@@ -134,7 +183,10 @@ func (cli *CLI) unlockWallet(account accounts.Account) error {
 	for trials = 0; trials < 3; trials++ {
 		prompt := fmt.Sprintf("Unlocking account %s | Attempt %d/%d", account.Address.String(), trials+1, 3)
 		if walletPassword == "" {
-			walletPassword, _ = getPassPhrase(prompt, false)
+			walletPassword, err = getPassPhrase(prompt, false)
+			if err = promptError(err); errorCode(err) == ErrCodeUserAbort {
+				return err
+			}
 		} else {
 			fmt.Println(prompt, "\nUse the the password has set")
 		}
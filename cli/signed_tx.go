@@ -0,0 +1,162 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// SignedTx is the signed transaction file, the raw hex with what it was
+// signed from, the legacy file has only the raw hex
+type SignedTx struct {
+	Raw        string         `json:"raw"`
+	Hash       common.Hash    `json:"hash"`
+	Signer     common.Address `json:"signer"`
+	ChainID    *big.Int       `json:"chainID"`
+	TxFileHash common.Hash    `json:"txFileHash"`
+	Intent     *Intent        `json:"intent,omitempty"`
+
+	// legacy reports whether it is read from a bare signed transaction hex
+	// or a legacy signed bundle of raw hex, which have nothing to check
+	legacy bool
+}
+
+func newSignedTx(signTx *types.Transaction, raw string, t *Transaction, txFileHash common.Hash) *SignedTx {
+	return &SignedTx{
+		Raw:        raw,
+		Hash:       signTx.Hash(),
+		Signer:     t.From,
+		ChainID:    t.NetworkID,
+		TxFileHash: txFileHash,
+		Intent:     t.Intent,
+	}
+}
+
+// decode decodes the raw hex and recovers the sender, and cross-checks
+// them with the hash, signer and chain ID recorded
+func (s *SignedTx) decode() (*types.Transaction, common.Address, error) {
+	tx := new(types.Transaction)
+	if err := rlp.DecodeBytes(common.FromHex(s.Raw), tx); err != nil {
+		return nil, common.Address{}, newErrorf(ErrCodeInvalidArgument, "DecodeBytes signTxHex error: %v", err)
+	}
+	from, err := types.Sender(types.NewEIP155Signer(tx.ChainId()), tx)
+	if err != nil {
+		return nil, common.Address{}, newErrorf(ErrCodeInvalidArgument, "recover sender error: %v", err)
+	}
+	if s.legacy {
+		return tx, from, nil
+	}
+
+	if tx.Hash() != s.Hash {
+		return nil, common.Address{}, newErrorf(ErrCodeVerifyFailed, "Error: hash of signed transaction is %s, but %s recorded", tx.Hash().String(), s.Hash.String())
+	}
+	if from != s.Signer {
+		return nil, common.Address{}, newErrorf(ErrCodeVerifyFailed, "Error: signer of signed transaction is %s, but %s recorded", from.String(), s.Signer.String())
+	}
+	if s.ChainID != nil && tx.ChainId().Cmp(s.ChainID) != 0 {
+		return nil, common.Address{}, newErrorf(ErrCodeVerifyFailed, "Error: chain ID of signed transaction is %s, but %s recorded", tx.ChainId().String(), s.ChainID.String())
+	}
+	return tx, from, nil
+}
+
+// readSignedFile reads the signed transactions in the signed transaction
+// file, legacy signed transaction hex file or signed bundle file, bundle
+// reports whether it is a signed bundle
+func readSignedFile(path string) (signed []*SignedTx, bundle bool, err error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, false, newError(ErrCodeFile, err)
+	}
+
+	content := strings.TrimSpace(string(b))
+	if !strings.HasPrefix(content, "{") {
+		line := strings.TrimSpace(strings.SplitN(content, "\n", 2)[0])
+		if line == "" {
+			return nil, false, newErrorf(ErrCodeInvalidArgument, "Error: no signed transaction in %s", path)
+		}
+		return []*SignedTx{{Raw: line, legacy: true}}, false, nil
+	}
+
+	var file struct {
+		*SignedTx
+		Transactions json.RawMessage `json:"signedTransactions"`
+	}
+	if err := json.Unmarshal(b, &file); err != nil {
+		return nil, false, newErrorf(ErrCodeInvalidArgument, "Error: read signed file: %v", err)
+	}
+	if file.Transactions == nil {
+		if file.SignedTx == nil || file.Raw == "" {
+			return nil, false, newErrorf(ErrCodeInvalidArgument, "Error: no signed transaction in %s", path)
+		}
+		if err := file.SignedTx.checkRecorded(); err != nil {
+			return nil, false, err
+		}
+		return []*SignedTx{file.SignedTx}, false, nil
+	}
+
+	if err := json.Unmarshal(file.Transactions, &signed); err != nil {
+		// the legacy signed bundle has only the raw hex
+		var raws []string
+		if json.Unmarshal(file.Transactions, &raws) != nil {
+			return nil, true, newErrorf(ErrCodeInvalidArgument, "Error: read signed bundle: %v", err)
+		}
+		signed = nil
+		for _, raw := range raws {
+			signed = append(signed, &SignedTx{Raw: raw, legacy: true})
+		}
+	}
+	if len(signed) == 0 {
+		return nil, true, newErrorf(ErrCodeInvalidArgument, "Error: no signed transaction in bundle")
+	}
+	for i, st := range signed {
+		if st == nil || st.Raw == "" {
+			return nil, true, newErrorf(ErrCodeInvalidArgument, "Error: no raw hex of signed transaction %d in bundle", i+1)
+		}
+		if err := st.checkRecorded(); err != nil {
+			return nil, true, newErrorf(ErrCodeVerifyFailed, "signed transaction %d in bundle: %w", i+1, err)
+		}
+	}
+	return signed, true, nil
+}
+
+// checkRecorded checks the hash and signer to cross-check with are
+// recorded in the signed transaction container
+func (s *SignedTx) checkRecorded() error {
+	if s.legacy {
+		return nil
+	}
+	if s.Hash == (common.Hash{}) {
+		return newErrorf(ErrCodeVerifyFailed, "Error: no hash recorded in signed transaction")
+	}
+	if s.Signer == (common.Address{}) {
+		return newErrorf(ErrCodeVerifyFailed, "Error: no signer recorded in signed transaction")
+	}
+	return nil
+}
+
+// fileHash returns the keccak256 hash of the content of file
+func fileHash(path string) (common.Hash, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return crypto.Keccak256Hash(b), nil
+}
+
+func (s *SignedTx) print() {
+	if s.legacy {
+		return
+	}
+	fmt.Println("Signed by: ", s.Signer.String())
+	fmt.Println("Signed from transaction file hash: ", s.TxFileHash.String())
+	if s.Intent != nil {
+		fmt.Println("The intent is:", s.Intent.String())
+	}
+}
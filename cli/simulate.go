@@ -0,0 +1,83 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/newtonproject/MultiSignatureWallet/msw"
+)
+
+// dryRun simulates the transaction from fromAddress calling the wallet with
+// data instead of sending it, and fails if it would revert or the inner call
+// executed by it would fail
+func (cli *CLI) dryRun(fromAddress string, data []byte, unit string) error {
+	if !common.IsHexAddress(fromAddress) {
+		return newErrorf(ErrCodeInvalidArgument, "Error: fromAddress is invalid hex-encoded: %s", fromAddress)
+	}
+	w, err := cli.GetWallet()
+	if err != nil {
+		return newErrorf(ErrCodeRPC, "GetWallet Error: %v", err)
+	}
+
+	s, err := w.Simulate(context.Background(), common.HexToAddress(fromAddress), data)
+	if err != nil {
+		return walletError(err)
+	}
+
+	if cli.isJSON() {
+		cli.printJSON(s)
+	} else {
+		printSimulation(s, unit)
+	}
+
+	if s.Reverted() {
+		return newErrorf(ErrCodeSimulationFailed, "Error: dry run of %s would revert", s.Method)
+	}
+	return nil
+}
+
+func printSimulation(s *msw.Simulation, unit string) {
+	fmt.Printf("Dry run of %s (nothing is sent):\n", s.Method)
+	printCallResult("\tWallet call", s.Outer)
+	if s.Inner == nil {
+		return
+	}
+
+	if s.ID != nil {
+		fmt.Printf("\tTransaction ID %s pays %s to %s\n", s.ID.String(), getWeiAmountTextUnitByUnit(s.Value, unit), s.Destination.String())
+	} else {
+		fmt.Printf("\tThe transaction pays %s to %s\n", getWeiAmountTextUnitByUnit(s.Value, unit), s.Destination.String())
+	}
+	if len(s.Data) > 0 {
+		fmt.Printf("\tData: %s\n", common.ToHex(s.Data))
+		showDataAuto(s.Data, "\t\t", unit)
+	}
+	if s.Executes {
+		fmt.Println("\tThe call is executed by this transaction")
+	} else {
+		fmt.Println("\tThe call is not executed by this transaction, it needs more confirmations")
+	}
+	if !s.Executes && s.Inner.Reverted {
+		// the submission or confirmation itself succeeds
+		if s.Inner.Reason == "" {
+			fmt.Println("\tInner call from wallet: would emit ExecutionFailure if executed now, no reason returned by node")
+		} else {
+			fmt.Printf("\tInner call from wallet: would emit ExecutionFailure if executed now: %s\n", s.Inner.Reason)
+		}
+		return
+	}
+	printCallResult("\tInner call from wallet", s.Inner)
+}
+
+func printCallResult(name string, r *msw.CallResult) {
+	if !r.Reverted {
+		fmt.Printf("%s: OK, gas %d\n", name, r.Gas)
+		return
+	}
+	if r.Reason == "" {
+		fmt.Printf("%s: would REVERT, no reason returned by node\n", name)
+		return
+	}
+	fmt.Printf("%s: would REVERT: %s\n", name, r.Reason)
+}
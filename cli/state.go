@@ -0,0 +1,292 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/newtonproject/MultiSignatureWallet/msw"
+	"github.com/spf13/cobra"
+)
+
+// errStateHash is returned if the hash recorded does not match the state.
+// The hash is not keyed, it detects a corrupted or inconsistent file, not a
+// file edited on purpose, which can recompute it.
+var errStateHash = errors.New("hash of state does not match, the file is corrupted or inconsistent")
+
+// State is the snapshot of the chain state exported on the online computer,
+// to build and check offline transactions without network
+type State struct {
+	Contract         common.Address   `json:"contract"`
+	ChainID          *big.Int         `json:"chainID"`
+	GasPrice         *big.Int         `json:"gasPrice"`
+	BlockNumber      uint64           `json:"blockNumber"`
+	ExportedAt       int64            `json:"exportedAt"`
+	Owners           []*OwnerState    `json:"owners"`
+	Required         *big.Int         `json:"required"`
+	DailyLimit       *big.Int         `json:"dailyLimit"`
+	Balance          *big.Int         `json:"balance"`
+	TransactionCount *big.Int         `json:"transactionCount"`
+	Proposals        []*StateProposal `json:"proposals"`
+}
+
+// OwnerState is the nonce and balance of an owner
+type OwnerState struct {
+	Address common.Address `json:"address"`
+	Nonce   uint64         `json:"nonce"`
+	Balance *big.Int       `json:"balance"`
+}
+
+// StateProposal is a pending transaction ID of the state
+type StateProposal struct {
+	ID *big.Int `json:"id"`
+	*Proposal
+}
+
+// stateFile is the state with its keccak256 hash, a checksum against
+// corruption only
+type stateFile struct {
+	State *State      `json:"state"`
+	Hash  common.Hash `json:"hash"`
+}
+
+func (s *State) hash() (common.Hash, error) {
+	b, err := json.Marshal(s)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return crypto.Keccak256Hash(b), nil
+}
+
+func (cli *CLI) buildExportStateCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:                   "export-state [--out file]",
+		Short:                 "Export the chain state of contract wallet to build offline transactions",
+		Args:                  cobra.NoArgs,
+		DisableFlagsInUseLine: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			outStr, _ := cmd.Flags().GetString("out")
+
+			state, err := cli.exportState(context.Background())
+			if err != nil {
+				return newErrorf(errorCode(err), "Error: export state: %w", err)
+			}
+			hash, err := state.hash()
+			if err != nil {
+				return newError(ErrCodeGeneral, err)
+			}
+			if err := saveJSONToFile(&stateFile{state, hash}, outStr); err != nil {
+				return newError(ErrCodeFile, err)
+			}
+
+			fmt.Printf("Chain ID: %s, block: %d, owners: %d, pending transaction IDs: %d\n", state.ChainID.String(), state.BlockNumber, len(state.Owners), len(state.Proposals))
+			fmt.Printf("Successfully save state (hash %s) to file %s\n", hash.String(), outStr)
+
+			if cli.isJSON() {
+				cli.printJSON(struct {
+					Hash common.Hash `json:"hash"`
+					File string      `json:"file"`
+				}{hash, outStr})
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().String("out", "state.json", "file `path` to save the state")
+
+	return cmd
+}
+
+// exportState reads the state of the contract wallet and its owners
+func (cli *CLI) exportState(ctx context.Context) (*State, error) {
+	w, err := cli.GetWallet()
+	if err != nil {
+		return nil, newError(ErrCodeRPC, err)
+	}
+	info, err := w.Info(ctx)
+	if err != nil {
+		return nil, newError(ErrCodeRPC, err)
+	}
+
+	state := &State{
+		Contract:   info.Address,
+		Required:   info.Required,
+		DailyLimit: info.DailyLimit,
+		Balance:    info.Balance,
+		ExportedAt: time.Now().Unix(),
+	}
+	header, err := cli.client.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return nil, newErrorf(ErrCodeRPC, "get latest block error: %v", err)
+	}
+	state.BlockNumber = header.Number.Uint64()
+	if state.ChainID, err = cli.client.NetworkID(ctx); err != nil {
+		return nil, newErrorf(ErrCodeRPC, "NetworkID error: %v", err)
+	}
+	if state.GasPrice, err = cli.client.SuggestGasPrice(ctx); err != nil {
+		return nil, newErrorf(ErrCodeRPC, "SuggestGasPrice error: %v", err)
+	}
+
+	for _, owner := range info.Owners {
+		o := &OwnerState{Address: owner}
+		if o.Nonce, err = cli.client.PendingNonceAt(ctx, owner); err != nil {
+			return nil, newErrorf(ErrCodeRPC, "PendingNonceAt error: %v", err)
+		}
+		if o.Balance, err = cli.client.BalanceAt(ctx, owner, nil); err != nil {
+			return nil, newErrorf(ErrCodeRPC, "BalanceAt error: %v", err)
+		}
+		state.Owners = append(state.Owners, o)
+	}
+
+	if state.TransactionCount, err = w.TransactionCount(ctx); err != nil {
+		return nil, newErrorf(ErrCodeRPC, "TransactionCount error: %v", err)
+	}
+	ids, err := cli.getTransactionIDs(&bind.CallOpts{Context: ctx}, 0, 0, true, false)
+	if err != nil {
+		return nil, newError(ErrCodeRPC, err)
+	}
+	for _, id := range ids {
+		status, err := w.Status(ctx, id)
+		if err != nil {
+			return nil, walletError(err)
+		}
+		state.Proposals = append(state.Proposals, &StateProposal{id, newProposal(status)})
+	}
+
+	return state, nil
+}
+
+// readStateFile reads the state in file and checks its hash for corruption
+func readStateFile(path string) (*State, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var file stateFile
+	if err := json.Unmarshal(b, &file); err != nil {
+		return nil, err
+	}
+	if file.State == nil {
+		return nil, errors.New("no state in file")
+	}
+	hash, err := file.State.hash()
+	if err != nil {
+		return nil, err
+	}
+	if hash != file.Hash {
+		return nil, errStateHash
+	}
+	return file.State, nil
+}
+
+// loadStateFlag reads the state of the flag state of build, nil if not set
+func loadStateFlag(cmd *cobra.Command) (*State, error) {
+	if !cmd.Flags().Changed("state") {
+		return nil, nil
+	}
+	path, _ := cmd.Flags().GetString("state")
+	state, err := readStateFile(path)
+	if err != nil {
+		return nil, newErrorf(ErrCodeFile, "Error: read state(%s): %v", path, err)
+	}
+	fmt.Printf("Use state of chain ID %s at block %d, exported at %s\n", state.ChainID.String(), state.BlockNumber,
+		time.Unix(state.ExportedAt, 0).Format("2006-01-02 15:04:05"))
+	return state, nil
+}
+
+func (s *State) owner(address common.Address) *OwnerState {
+	for _, o := range s.Owners {
+		if o.Address == address {
+			return o
+		}
+	}
+	return nil
+}
+
+func (s *State) proposal(id *big.Int) *StateProposal {
+	for _, p := range s.Proposals {
+		if p.ID.Cmp(id) == 0 {
+			return p
+		}
+	}
+	return nil
+}
+
+// apply fills the contract, nonce, gasPrice and chainID of t from the state
+func (s *State) apply(t *Transaction) {
+	if t.To == (common.Address{}) {
+		t.To = s.Contract
+	}
+	if o := s.owner(t.From); o != nil {
+		t.Nonce = o.Nonce
+	}
+	t.GasPrice = new(big.Int).Set(s.GasPrice)
+	t.NetworkID = new(big.Int).Set(s.ChainID)
+}
+
+// check runs the checks of the online build on t against the state, and
+// embeds the proposal of the transaction ID of t
+func (s *State) check(t *Transaction) error {
+	if t.To != s.Contract {
+		return newErrorf(ErrCodeInvalidArgument, "contract %s is not the contract %s of state", t.To.String(), s.Contract.String())
+	}
+	if t.NetworkID == nil || t.NetworkID.Cmp(s.ChainID) != 0 {
+		return newErrorf(ErrCodeInvalidArgument, "chain ID %v is not the chain ID %s of state", t.NetworkID, s.ChainID.String())
+	}
+	o := s.owner(t.From)
+	if o == nil {
+		return walletError(fmt.Errorf("%w: %s", msw.ErrNotOwner, t.From.String()))
+	}
+	if t.Nonce < o.Nonce {
+		return newErrorf(ErrCodeInvalidArgument, "nonce %d has been used, the next nonce of %s is %d", t.Nonce, t.From.String(), o.Nonce)
+	}
+	if t.GasPrice != nil {
+		cost := new(big.Int).Mul(t.GasPrice, new(big.Int).SetUint64(t.GasLimit))
+		if o.Balance.Cmp(cost) < 0 {
+			return newErrorf(ErrCodeInvalidArgument, "balance %s of %s can not cover the gas %s",
+				getWeiAmountTextUnitByUnit(o.Balance, UnitETH), t.From.String(), getWeiAmountTextUnitByUnit(cost, UnitETH))
+		}
+	}
+
+	if t.Intent == nil || !t.Intent.hasTxID() {
+		return nil
+	}
+	id := t.Intent.TxID
+	if id.Sign() < 0 || id.Cmp(s.TransactionCount) >= 0 {
+		return walletError(fmt.Errorf("%w: ID(%s) exceeds total number(%s) of transactions", msw.ErrTxNotFound, id.String(), s.TransactionCount.String()))
+	}
+	p := s.proposal(id)
+	if p == nil {
+		return walletError(fmt.Errorf("%w: ID(%s)", msw.ErrAlreadyExecuted, id.String()))
+	}
+
+	confirmed := false
+	for _, c := range p.Confirmations {
+		if c == t.From {
+			confirmed = true
+		}
+	}
+	switch t.Intent.Action {
+	case IntentConfirm:
+		if big.NewInt(int64(len(p.Confirmations))).Cmp(s.Required) >= 0 {
+			return walletError(fmt.Errorf("%w: ID(%s) has enough confirmations", msw.ErrAlreadyConfirmed, id.String()))
+		}
+		if confirmed {
+			return walletError(fmt.Errorf("%w: ID(%s) by %s", msw.ErrAlreadyConfirmed, id.String(), t.From.String()))
+		}
+	case IntentRevoke:
+		if !confirmed {
+			return walletError(fmt.Errorf("%w: ID(%s) by %s", msw.ErrNotConfirmed, id.String(), t.From.String()))
+		}
+	}
+	t.Intent.Proposal = p.Proposal
+
+	return nil
+}
@@ -0,0 +1,96 @@
+package cli
+
+import (
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestState(t *testing.T) {
+	dir, err := ioutil.TempDir("", "state")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	contract := common.HexToAddress("0xf09E6759c2588eE8435902d16350E321CBD27af3")
+	a := common.HexToAddress("0x9B3deA9C636BA262f870f98a1c64d444BF0f6544")
+	b := common.HexToAddress("0x7c1d845a0CC7E24352A59FEF437eB27b504769DE")
+	state := &State{
+		Contract: contract,
+		ChainID:  big.NewInt(1007),
+		GasPrice: big.NewInt(100),
+		Owners: []*OwnerState{
+			{Address: a, Nonce: 5, Balance: big.NewInt(1e18)},
+			{Address: b, Nonce: 9, Balance: big.NewInt(1e18)},
+		},
+		Required:         big.NewInt(2),
+		DailyLimit:       big.NewInt(0),
+		Balance:          big.NewInt(0),
+		TransactionCount: big.NewInt(3),
+		Proposals: []*StateProposal{
+			{big.NewInt(2), &Proposal{Destination: a, Value: big.NewInt(1), Confirmations: []common.Address{a}, Required: big.NewInt(2)}},
+		},
+	}
+	hash, err := state.hash()
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, "state.json")
+	if err := saveJSONToFile(&stateFile{state, hash}, path); err != nil {
+		t.Fatal(err)
+	}
+	state, err = readStateFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, test := range []struct {
+		from   common.Address
+		action string
+		id     int64
+		code   string
+	}{
+		{b, IntentConfirm, 2, ""},
+		{a, IntentConfirm, 2, ErrCodeAlreadyConfirmed},
+		{b, IntentRevoke, 2, ErrCodeNotConfirmed},
+		{b, IntentExecute, 1, ErrCodeAlreadyExecuted},
+		{b, IntentExecute, 3, ErrCodeTxNotFound},
+		{contract, IntentExecute, 2, ErrCodeNotOwner},
+	} {
+		tran := &Transaction{From: test.from, GasLimit: 100000}
+		state.apply(tran)
+		tran.setID(test.action, big.NewInt(test.id))
+		err := state.check(tran)
+		if test.code == "" {
+			if err != nil || tran.Nonce != 9 || tran.Intent.Proposal == nil {
+				t.Fatalf("check %s %d by %s got %v, transaction %+v", test.action, test.id, test.from.String(), err, tran)
+			}
+		} else if errorCode(err) != test.code {
+			t.Fatalf("check %s %d by %s got %v, want %s", test.action, test.id, test.from.String(), err, test.code)
+		}
+	}
+
+	out := filepath.Join(dir, "confirm.tx")
+	NewCLI().TestCommand("build confirm 2 --offline --gasLimit 100000 --state " + path +
+		" -a " + contract.String() + " -f " + b.String() + " -w " + dir + " --out " + out)
+	trans, err := readTxFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tran := trans[0]; tran.Nonce != 9 || tran.NetworkID.Int64() != 1007 || tran.GasPrice.Int64() != 100 || tran.Intent.Proposal == nil {
+		t.Fatalf("got transaction built with state %+v", tran)
+	}
+
+	state.GasPrice = big.NewInt(1)
+	if err := saveJSONToFile(&stateFile{state, hash}, path); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := readStateFile(path); err != errStateHash {
+		t.Fatalf("read inconsistent state got %v", err)
+	}
+}
@@ -0,0 +1,152 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"os"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/spf13/cobra"
+)
+
+func (cli *CLI) buildSyncCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:                   "sync [--start block] [--chunk number]",
+		Short:                 "Sync the transactions, confirmations and events of contract wallet to local cache",
+		Long:                  "Sync the transactions, confirmations and events of contract wallet to local cache, only new blocks are fetched after the first sync. Use --cached with list, info and events to read from the cache",
+		Args:                  cobra.MinimumNArgs(0),
+		DisableFlagsInUseLine: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			start, _ := cmd.Flags().GetUint64("start")
+			chunk, _ := cmd.Flags().GetUint64("chunk")
+
+			if _, err := cli.GetSimpleRegistry(); err != nil {
+				fmt.Fprint(os.Stderr, cmd.UsageString())
+				return newErrorf(ErrCodeRPC, "GetSimpleRegistry Error: %v", err)
+			}
+
+			if err := cli.syncWalletCache(context.Background(), start, chunk); err != nil {
+				return newErrorf(ErrCodeRPC, "Error: %w", err)
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().Uint64("start", 0, "the `block` number to start scanning events from in the first sync")
+	cmd.Flags().Uint64("chunk", defaultEventsChunkSize, "the `number` of blocks to scan in each request")
+
+	return cmd
+}
+
+// syncWalletCache fetches the new blocks since last sync and updates the cache
+func (cli *CLI) syncWalletCache(ctx context.Context, start, chunkSize uint64) error {
+	simpleRegistry, err := cli.GetSimpleRegistry()
+	if err != nil {
+		return err
+	}
+	contract := common.HexToAddress(cli.contractAddress)
+
+	chainID, err := cli.client.NetworkID(ctx)
+	if err != nil {
+		return fmt.Errorf("get chainID error: %v", err)
+	}
+
+	cache, err := openWalletCache(cli.cachePath, chainID, contract)
+	if err != nil {
+		return newError(ErrCodeCache, err)
+	}
+	defer cache.Close()
+
+	var prevCount uint64
+	prev, err := cache.Info()
+	if err == nil {
+		start = prev.SyncedBlock + 1
+		prevCount = prev.TransactionCount
+	} else if err != errCacheNotSynced {
+		return newError(ErrCodeCache, err)
+	}
+
+	header, err := cli.client.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("get latest block error: %v", err)
+	}
+	head := header.Number.Uint64()
+	if start > head {
+		fmt.Printf("Cache is up to date at block %d\n", head)
+		return nil
+	}
+
+	fmt.Printf("Syncing contract address(%s) from block %d to %d...\n", contract.String(), start, head)
+	events, err := cli.filterEvents(ctx, start, head, chunkSize, new(eventFilter))
+	if err != nil {
+		return err
+	}
+
+	// read the state at head, so it matches the events
+	opts := &bind.CallOpts{Context: ctx, BlockNumber: header.Number}
+
+	info := &cachedWalletInfo{
+		ChainID:     chainID,
+		Contract:    contract,
+		SyncedBlock: head,
+		SyncedTime:  time.Now(),
+	}
+	if info.Balance, err = cli.client.BalanceAt(ctx, contract, header.Number); err != nil {
+		return fmt.Errorf("BalanceAt error: %v", err)
+	}
+	if info.Owners, err = simpleRegistry.GetOwners(opts); err != nil {
+		return fmt.Errorf("GetOwners error: %v", err)
+	}
+	if info.Required, err = simpleRegistry.Required(opts); err != nil {
+		return fmt.Errorf("Required error: %v", err)
+	}
+	if info.DailyLimit, err = simpleRegistry.DailyLimit(opts); err != nil {
+		return fmt.Errorf("DailyLimit error: %v", err)
+	}
+	count, err := simpleRegistry.TransactionCount(opts)
+	if err != nil {
+		return fmt.Errorf("TransactionCount error: %v", err)
+	}
+	info.TransactionCount = count.Uint64()
+
+	// the new transaction IDs and the ones changed by events
+	updated := make(map[uint64]bool)
+	for id := prevCount; id < info.TransactionCount; id++ {
+		updated[id] = true
+	}
+	for _, e := range events {
+		if e.TransactionID != nil && e.TransactionID.Uint64() < info.TransactionCount {
+			updated[e.TransactionID.Uint64()] = true
+		}
+	}
+
+	ids := make([]*big.Int, 0, len(updated))
+	for id := range updated {
+		ids = append(ids, new(big.Int).SetUint64(id))
+	}
+	txs, err := cli.getWalletTransactions(ctx, header.Number, ids, defaultListBatchSize, defaultListWorkers)
+	if err != nil {
+		return err
+	}
+
+	if err := cache.Update(info, txs, events); err != nil {
+		return newErrorf(ErrCodeCache, "update cache error: %v", err)
+	}
+
+	fmt.Printf("Synced to block %d: %d new events, %d transaction IDs updated, %d transaction IDs in total\n",
+		head, len(events), len(txs), info.TransactionCount)
+
+	return nil
+}
+
+// openCache opens the cache of contract wallet synced before
+func (cli *CLI) openCache() (*walletCache, error) {
+	if !common.IsHexAddress(cli.contractAddress) {
+		return nil, fmt.Errorf("contract address is invalid")
+	}
+	return openWalletCacheSynced(cli.cachePath, common.HexToAddress(cli.contractAddress))
+}
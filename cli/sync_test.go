@@ -0,0 +1,13 @@
+package cli
+
+import "testing"
+
+func TestSync(t *testing.T) {
+	cli := NewCLI()
+
+	cli.TestCommand("sync")
+	cli.TestCommand("list --cached")
+	cli.TestCommand("info --cached")
+	cli.TestCommand("info 1 --cached --timeline")
+	cli.TestCommand("events --cached")
+}
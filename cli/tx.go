@@ -1,7 +1,7 @@
 package cli
 
 import (
-	"bytes"
+	"context"
 	"fmt"
 	"math/big"
 	"os"
@@ -11,46 +11,62 @@ import (
 	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/newtonproject/MultiSignatureWallet/msw"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
 
+func (cli *CLI) buildTxCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "tx [speedup|cancel|status|pending]",
+		Short: "Manage the transactions sent to blockchain",
+		Args:  cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			fmt.Fprint(os.Stderr, cmd.UsageString())
+			return newErrorf(ErrCodeInvalidArgument, "Error: unknown command %q for %q", args[0], cmd.CommandPath())
+		},
+	}
+
+	cmd.AddCommand(cli.buildTxSpeedupCmd())
+	cmd.AddCommand(cli.buildTxCancelCmd())
+	cmd.AddCommand(cli.buildTxStatusCmd())
+	cmd.AddCommand(cli.buildTxPendingCmd())
+
+	return cmd
+}
+
 func (cli *CLI) buildTxSubmitCmd() *cobra.Command {
 	TxSubmitCmd := &cobra.Command{
-		Use:                   fmt.Sprintf("submit <amount> <-t target> [-u %s] [-f source]", strings.Join(UnitList, ",")),
+		Use:                   fmt.Sprintf("submit <amount> <-t target> [-u %s] [-f source] [--data text | --token token | --abi file --method name [--args arg]...] [--dry-run]", strings.Join(UnitList, ",")),
 		Short:                 "Submit a transaction, pay amount in unit to target address",
 		Aliases:               []string{"pay"},
 		Long:                  "Allows an owner to submit and confirm a transaction",
 		Args:                  cobra.MinimumNArgs(1),
 		DisableFlagsInUseLine: true,
-		Run: func(cmd *cobra.Command, args []string) {
+		RunE: func(cmd *cobra.Command, args []string) error {
 
 			amountStr := args[0]
 			unit, err := cmd.Flags().GetString("unit")
 			if err != nil {
-				fmt.Println("Error: required flag(s) \"unit\" not set")
 				fmt.Fprint(os.Stderr, cmd.UsageString())
-				return
+				return newErrorf(ErrCodeInvalidArgument, "Error: required flag(s) \"unit\" not set")
 			}
 			d := stringInSlice(unit, UnitList)
 			if !d {
-				fmt.Printf("Unit(%s) for amount error. %s.\n", unit, fmt.Sprintf("Available unit: %s", strings.Join(UnitList, ",")))
 				fmt.Fprint(os.Stderr, cmd.UsageString())
-				return
+				return newErrorf(ErrCodeInvalidArgument, "Unit(%s) for amount error. Available unit: %s.", unit, strings.Join(UnitList, ","))
 			}
 
 			fromAddress := viper.GetString("from")
 			if fromAddress == "" || !common.IsHexAddress(fromAddress) {
-				fmt.Println("Error: not set from address of owner")
 				fmt.Fprint(os.Stderr, cmd.UsageString())
-				return
+				return newErrorf(ErrCodeInvalidArgument, "Error: not set from address of owner")
 			}
 
 			toAddressStr, err := cmd.Flags().GetString("to")
 			if err != nil {
-				fmt.Println("Error: required flag(s) \"to\" not set")
 				fmt.Fprint(os.Stderr, cmd.UsageString())
-				return
+				return newErrorf(ErrCodeInvalidArgument, "Error: required flag(s) \"to\" not set")
 			}
 			toAddress := common.HexToAddress(toAddressStr)
 
@@ -58,12 +74,15 @@ func (cli *CLI) buildTxSubmitCmd() *cobra.Command {
 				data      []byte
 				amountWei = new(big.Int)
 			)
+			if cmd.Flags().Changed("token") && cmd.Flags().Changed("abi") {
+				fmt.Fprint(os.Stderr, cmd.UsageString())
+				return newErrorf(ErrCodeInvalidArgument, "Error: flag token can not be used with abi")
+			}
 			if cmd.Flags().Changed("token") {
 				fmt.Println("Trying to submit token transfer tx:")
 				tokenAddressStr, err := cmd.Flags().GetString("token")
 				if err != nil {
-					fmt.Println("Error: get token address error")
-					return
+					return newErrorf(ErrCodeInvalidArgument, "Error: get token address error")
 				}
 				tokenAddress := common.HexToAddress(tokenAddressStr)
 				fmt.Println("The token address is: ", tokenAddress.String())
@@ -71,14 +90,12 @@ func (cli *CLI) buildTxSubmitCmd() *cobra.Command {
 				ERC20TransferABI := `[{"constant":true,"inputs":[],"name":"decimals","outputs":[{"name":"","type":"uint8"}],"payable":false,"stateMutability":"view","type":"function"},{"constant":false,"inputs":[{"name":"_to","type":"address"},{"name":"_value","type":"uint256"}],"name":"transfer","outputs":[{"name":"","type":"bool"}],"payable":false,"stateMutability":"nonpayable","type":"function"}]`
 				parsed, err := abi.JSON(strings.NewReader(ERC20TransferABI))
 				if err != nil {
-					fmt.Println("JSON err: ", err)
-					return
+					return fmt.Errorf("JSON err: %v", err)
 				}
 				name := "transfer"
 				method, exist := parsed.Methods[name]
 				if !exist {
-					fmt.Errorf("method '%s' not found", name)
-					return
+					return fmt.Errorf("Error: method '%s' not found", name)
 				}
 
 				var decimals uint8
@@ -86,8 +103,7 @@ func (cli *CLI) buildTxSubmitCmd() *cobra.Command {
 					// get deicmals
 					err = cli.BuildClient()
 					if err != nil {
-						fmt.Println(err)
-						return
+						return newError(ErrCodeRPC, err)
 					}
 					erc20 := bind.NewBoundContract(tokenAddress, parsed, cli.client, cli.client, cli.client)
 					var (
@@ -96,8 +112,7 @@ func (cli *CLI) buildTxSubmitCmd() *cobra.Command {
 					out := ret0
 					err = erc20.Call(nil, out, "decimals")
 					if err != nil {
-						fmt.Println(err)
-						return
+						return newError(ErrCodeRPC, err)
 					}
 
 					decimals = *out
@@ -106,15 +121,13 @@ func (cli *CLI) buildTxSubmitCmd() *cobra.Command {
 
 				tokenAmountWei, err := GetAmountISAACFromTextWithDecimals(amountStr, decimals)
 				if err != nil {
-					fmt.Println("Get amount error:", err)
 					fmt.Fprint(os.Stderr, cmd.UsageString())
-					return
+					return newErrorf(ErrCodeInvalidArgument, "Get amount error: %v", err)
 				}
 
 				arguments, err := method.Inputs.Pack(toAddress, tokenAmountWei)
 				if err != nil {
-					fmt.Println(err)
-					return
+					return newError(ErrCodeInvalidArgument, err)
 				}
 
 				// Pack up the method ID too if not a constructor and return
@@ -122,24 +135,47 @@ func (cli *CLI) buildTxSubmitCmd() *cobra.Command {
 
 				toAddress = tokenAddress
 				amountWei = big.NewInt(0)
+			} else if cmd.Flags().Changed("abi") {
+				if amountWei, err = getAmountWei(amountStr, unit); err != nil {
+					fmt.Fprint(os.Stderr, cmd.UsageString())
+					return newErrorf(ErrCodeInvalidArgument, "Get amount error: %v", err)
+				}
+				call, err := loadCallFlags(cmd, amountWei)
+				if err != nil {
+					fmt.Fprint(os.Stderr, cmd.UsageString())
+					return err
+				}
+				fmt.Printf("Trying to submit transaction to call %s of %s, pay %s:\n", call.Method, toAddress.String(), getWeiAmountTextUnitByUnit(amountWei, unit))
+				if err := call.show("\t"); err != nil {
+					return newError(ErrCodeInvalidArgument, err)
+				}
+				if data, err = call.pack(); err != nil {
+					return newError(ErrCodeInvalidArgument, err)
+				}
 			} else {
 				dataStr, err := cmd.Flags().GetString("data")
 				if err != nil {
-					fmt.Println("Error:", err)
 					fmt.Fprint(os.Stderr, cmd.UsageString())
-					return
+					return newError(ErrCodeInvalidArgument, err)
 				}
 				data = []byte(dataStr)
 
 				amountWei, err = getAmountWei(amountStr, unit)
 				if err != nil {
-					fmt.Println("Get amount error:", err)
 					fmt.Fprint(os.Stderr, cmd.UsageString())
-					return
+					return newErrorf(ErrCodeInvalidArgument, "Get amount error: %v", err)
+				}
+			}
+
+			if dryRun, _ := cmd.Flags().GetBool("dry-run"); dryRun {
+				packed, err := msw.Pack("submitTransaction", toAddress, amountWei, data)
+				if err != nil {
+					return newError(ErrCodeInvalidArgument, err)
 				}
+				return cli.dryRun(fromAddress, packed, unit)
 			}
 
-			cli.SubmitTransaction(fromAddress, toAddress, amountWei, data)
+			return cli.SubmitTransaction(fromAddress, toAddress, amountWei, data)
 
 		},
 	}
@@ -151,39 +187,49 @@ func (cli *CLI) buildTxSubmitCmd() *cobra.Command {
 	TxSubmitCmd.MarkFlagRequired("to")
 
 	TxSubmitCmd.Flags().String("token", "", "the address of token, if set then submit send token from MSW to receipt")
+	addCallFlags(TxSubmitCmd.Flags())
+	TxSubmitCmd.Flags().Bool("dry-run", false, "simulate the transaction and the call to target without sending it")
 
 	return TxSubmitCmd
 }
 
 func (cli *CLI) buildTxConfirmCmd() *cobra.Command {
 	TxSubmitCmd := &cobra.Command{
-		Use:                   "confirm <transactionId>",
+		Use:                   "confirm <transactionId> [--dry-run]",
 		Short:                 "Confirm transactionId",
 		Long:                  "Allows an owner to confirm a transaction",
 		Args:                  cobra.MinimumNArgs(1),
 		DisableFlagsInUseLine: true,
-		Run: func(cmd *cobra.Command, args []string) {
+		RunE: func(cmd *cobra.Command, args []string) error {
 
 			txIDStr := args[0]
 			txID := new(big.Int)
 			txID, ok := txID.SetString(txIDStr, 10)
 			if !ok {
-				fmt.Println("transactionId Error")
-				return
+				return newErrorf(ErrCodeInvalidArgument, "transactionId Error")
 			}
 
 			fromAddress := viper.GetString("from")
 			if fromAddress == "" || !common.IsHexAddress(fromAddress) {
-				fmt.Println("Error: not set from address of owner")
 				fmt.Fprint(os.Stderr, cmd.UsageString())
-				return
+				return newErrorf(ErrCodeInvalidArgument, "Error: not set from address of owner")
+			}
+
+			if dryRun, _ := cmd.Flags().GetBool("dry-run"); dryRun {
+				data, err := msw.Pack("confirmTransaction", txID)
+				if err != nil {
+					return newError(ErrCodeInvalidArgument, err)
+				}
+				return cli.dryRun(fromAddress, data, UnitETH)
 			}
 
-			cli.ConfirmTransaction(fromAddress, txID)
+			return cli.ConfirmTransaction(fromAddress, txID)
 
 		},
 	}
 
+	TxSubmitCmd.Flags().Bool("dry-run", false, "simulate the transaction and the call of transaction ID without sending it")
+
 	return TxSubmitCmd
 }
 
@@ -194,24 +240,22 @@ func (cli *CLI) buildTxRevokeCmd() *cobra.Command {
 		Long:                  "Allows an owner to revoke a confirmation for a transaction",
 		Args:                  cobra.MinimumNArgs(1),
 		DisableFlagsInUseLine: true,
-		Run: func(cmd *cobra.Command, args []string) {
+		RunE: func(cmd *cobra.Command, args []string) error {
 
 			txIDStr := args[0]
 			txID := new(big.Int)
 			txID, ok := txID.SetString(txIDStr, 10)
 			if !ok {
-				fmt.Println("transactionId Error")
-				return
+				return newErrorf(ErrCodeInvalidArgument, "transactionId Error")
 			}
 
 			fromAddress := viper.GetString("from")
 			if fromAddress == "" || !common.IsHexAddress(fromAddress) {
-				fmt.Println("Error: not set from address of owner")
 				fmt.Fprint(os.Stderr, cmd.UsageString())
-				return
+				return newErrorf(ErrCodeInvalidArgument, "Error: not set from address of owner")
 			}
 
-			cli.RevokeConfirmation(fromAddress, txID)
+			return cli.RevokeConfirmation(fromAddress, txID)
 
 		},
 	}
@@ -221,61 +265,59 @@ func (cli *CLI) buildTxRevokeCmd() *cobra.Command {
 
 func (cli *CLI) buildTxExecuteCmd() *cobra.Command {
 	TxSubmitCmd := &cobra.Command{
-		Use:                   "execute <transactionId>",
+		Use:                   "execute <transactionId> [--dry-run]",
 		Short:                 "Execute transactionId",
 		Long:                  "Allows anyone to execute a confirmed transaction",
 		Args:                  cobra.MinimumNArgs(1),
 		DisableFlagsInUseLine: true,
-		Run: func(cmd *cobra.Command, args []string) {
+		RunE: func(cmd *cobra.Command, args []string) error {
 
 			txIDStr := args[0]
 			txID := new(big.Int)
 			txID, ok := txID.SetString(txIDStr, 10)
 			if !ok {
-				fmt.Println("transactionId Error")
-				return
+				return newErrorf(ErrCodeInvalidArgument, "transactionId Error")
 			}
 
 			fromAddress := viper.GetString("from")
 			if fromAddress == "" || !common.IsHexAddress(fromAddress) {
-				fmt.Println("Error: not set from address of owner")
 				fmt.Fprint(os.Stderr, cmd.UsageString())
-				return
+				return newErrorf(ErrCodeInvalidArgument, "Error: not set from address of owner")
 			}
 
-			cli.ExecuteTransaction(fromAddress, txID)
+			if dryRun, _ := cmd.Flags().GetBool("dry-run"); dryRun {
+				data, err := msw.Pack("executeTransaction", txID)
+				if err != nil {
+					return newError(ErrCodeInvalidArgument, err)
+				}
+				return cli.dryRun(fromAddress, data, UnitETH)
+			}
+
+			return cli.ExecuteTransaction(fromAddress, txID)
 
 		},
 	}
 
+	TxSubmitCmd.Flags().Bool("dry-run", false, "simulate the transaction and the call of transaction ID without sending it")
+
 	return TxSubmitCmd
 }
 
 func (cli *CLI) buildTxListCmd() *cobra.Command {
 	TxListCmd := &cobra.Command{
-		Use:                   "list [--pending] [--executed]",
+		Use:                   "list [--pending] [--executed] [--awaiting] [--destination address] [--method name] [--confirmed-by address] [--min-value amount] [--max-value amount] [--sort id|value|confirmations] [--long]",
 		Short:                 "List of transaction IDs in defined range",
-		Long:                  "List of transaction IDs in defined range",
+		Long:                  "List of transaction IDs in defined range, the IDs are filtered by getTransactionIds of contract and read with JSON-RPC batch requests",
 		Args:                  cobra.MinimumNArgs(0),
 		DisableFlagsInUseLine: true,
-		Run: func(cmd *cobra.Command, args []string) {
-			var err error
-
+		RunE: func(cmd *cobra.Command, args []string) error {
 			fromIndex, _ := cmd.Flags().GetInt64("fromindex")
-			if err != nil {
-				fmt.Println("from error")
-				return
-			}
 			if fromIndex < 0 {
 				fromIndex = 0
 			}
-			fromIndexBig := big.NewInt(fromIndex)
-
-			var toIndexBig *big.Int
 			toIndex, _ := cmd.Flags().GetInt64("toindex")
-			if err != nil {
-				fmt.Println("toindex error")
-				return
+			if toIndex < 0 {
+				toIndex = 0
 			}
 
 			pending, _ := cmd.Flags().GetBool("pending")
@@ -285,72 +327,77 @@ func (cli *CLI) buildTxListCmd() *cobra.Command {
 				pending = true
 			}
 
+			options, err := parseTxListOptions(cmd)
+			if err != nil {
+				fmt.Fprint(os.Stderr, cmd.UsageString())
+				return newErrorf(ErrCodeInvalidArgument, "Error: %v", err)
+			}
+			if options.Filter.Awaiting {
+				// only the pending ones can be awaiting confirmation
+				pending, executed = true, false
+			}
+
+			if cached, _ := cmd.Flags().GetBool("cached"); cached {
+				return cli.listCachedTransactions(uint64(fromIndex), uint64(toIndex), pending, executed, options)
+			}
+
 			simpleRegistry, err := cli.GetSimpleRegistry()
 			if err != nil {
-				fmt.Println("GetSimpleRegistry Error: ", err)
-				fmt.Println(cmd.UsageString())
-				return
+				fmt.Fprint(os.Stderr, cmd.UsageString())
+				return newErrorf(ErrCodeRPC, "GetSimpleRegistry Error: %v", err)
 			}
 
-			count, err := simpleRegistry.TransactionCount(nil)
+			ctx := context.Background()
+			// read all at the same block, so the list is consistent
+			header, err := cli.client.HeaderByNumber(ctx, nil)
 			if err != nil {
-				fmt.Println("TransactionCount err: ", err)
-				return
+				return newErrorf(ErrCodeRPC, "Get latest block error: %v", err)
 			}
+			opts := &bind.CallOpts{Context: ctx, BlockNumber: header.Number}
 
+			count, err := simpleRegistry.TransactionCount(opts)
+			if err != nil {
+				return newErrorf(ErrCodeRPC, "TransactionCount err: %v", err)
+			}
 			if count.Cmp(big.NewInt(0)) <= 0 {
-				fmt.Println("NO transaction ID")
-				return
+				if cli.isJSON() {
+					cli.printJSON([]*txInfoOutput{})
+				} else {
+					fmt.Println("NO transaction ID")
+				}
+				return nil
 			}
 
-			if toIndex == 0 || count.Cmp(big.NewInt(toIndex)) < 0 {
-				toIndexBig = count
-			} else {
-				toIndexBig = big.NewInt(toIndex)
+			required, err := simpleRegistry.Required(opts)
+			if err != nil {
+				return newErrorf(ErrCodeRPC, "Required err: %v", err)
 			}
 
-			show := false
-			for i := fromIndexBig; i.Cmp(toIndexBig) < 0; i = i.Add(i, big.NewInt(1)) {
+			ids, err := cli.getTransactionIDs(opts, uint64(fromIndex), uint64(toIndex), pending, executed)
+			if err != nil {
+				return newErrorf(ErrCodeRPC, "Error: %v", err)
+			}
+			if len(ids) == 0 {
+				cli.showTransactionList(nil, required, options)
+				return nil
+			}
 
-				ok, err := simpleRegistry.IsConfirmed(nil, i)
-				if err != nil {
-					fmt.Printf("%s IsConfirmed Error(%v)\n", i, err)
-					continue
-				}
+			batchSize, _ := cmd.Flags().GetInt("batch")
+			workers, _ := cmd.Flags().GetInt("workers")
+			txs, err := cli.getWalletTransactions(ctx, header.Number, ids, batchSize, workers)
+			if err != nil {
+				return newErrorf(ErrCodeRPC, "Error: %v", err)
+			}
 
-				t, err := simpleRegistry.Transactions(nil, i)
+			if options.Filter.Awaiting {
+				options.Filter.awaitingConfirmed, err = cli.getConfirmedBy(ctx, header.Number, ids, options.Filter.Owner, batchSize, workers)
 				if err != nil {
-					fmt.Printf("%s Transactions Error(%v)\n", i, err)
-					continue
-				}
-
-				var buffer bytes.Buffer
-				buffer.WriteString(i.String())
-
-				if ok {
-					buffer.WriteString(" Confirmed")
-				} else {
-					buffer.WriteString(" Unconfirmed")
-				}
-
-				if t.Executed {
-					if executed {
-						show = true
-						buffer.WriteString(" Executed")
-						fmt.Println(buffer.String())
-					}
-				} else {
-					if pending {
-						show = true
-						buffer.WriteString(" Pending")
-						fmt.Println(buffer.String())
-					}
+					return newErrorf(ErrCodeRPC, "Error: %v", err)
 				}
 			}
-			if !show {
-				fmt.Println("NO matching transaction ID")
-			}
 
+			cli.showTransactionList(txs, required, options)
+			return nil
 		},
 	}
 
@@ -358,50 +405,232 @@ func (cli *CLI) buildTxListCmd() *cobra.Command {
 	TxListCmd.Flags().Int64("toindex", 0, "Index end position of transaction array")
 	TxListCmd.Flags().Bool("pending", false, "Only show pending transactions")
 	TxListCmd.Flags().Bool("executed", false, "Only show executed transactions")
+	TxListCmd.Flags().Bool("cached", false, "read from local cache, use sync to update it")
+	TxListCmd.Flags().Int("batch", defaultListBatchSize, "the `number` of transaction IDs to read in each JSON-RPC batch request")
+	TxListCmd.Flags().Int("workers", defaultListWorkers, "the `number` of batch requests to send at the same time")
+
+	TxListCmd.Flags().StringSlice("destination", nil, "only show transactions to destination `address`es, separated by commas(,)")
+	TxListCmd.Flags().String("min-value", "", "only show transactions with value not less than `amount` in unit")
+	TxListCmd.Flags().String("max-value", "", "only show transactions with value not greater than `amount` in unit")
+	TxListCmd.Flags().StringSlice("method", nil, "only show transactions calling decoded method `name`s, such as addOwner,transfer")
+	TxListCmd.Flags().StringSlice("confirmed-by", nil, "only show transactions confirmed by all the owner `address`es")
+	TxListCmd.Flags().Bool("awaiting", false, "only show pending transactions not confirmed by the from address yet")
+	TxListCmd.Flags().String("sort", ListColumnID, fmt.Sprintf("sort transactions by `key`. Available key: %s", strings.Join(ListSortList, ",")))
+	TxListCmd.Flags().Bool("desc", false, "sort in descending order")
+	TxListCmd.Flags().StringSlice("columns", nil, fmt.Sprintf("the `column`s to show, separated by commas(,). Available column: %s", strings.Join(ListColumnList, ",")))
+	TxListCmd.Flags().BoolP("long", "l", false, "show all the columns")
+	TxListCmd.Flags().StringP("unit", "u", "", fmt.Sprintf("unit for value. %s.", fmt.Sprintf("Available unit: %s", strings.Join(UnitList, ","))))
 
 	return TxListCmd
 }
 
-func (cli *CLI) showTxInfo(cmd *cobra.Command, args []string) {
-	if len(args) < 0 {
+// txListOptions is how list filters, sorts and shows the transactions
+type txListOptions struct {
+	Filter  *txListFilter
+	Sort    string
+	Desc    bool
+	Columns []string
+	Unit    string
+}
+
+func parseTxListOptions(cmd *cobra.Command) (*txListOptions, error) {
+	options := &txListOptions{Filter: new(txListFilter)}
+
+	options.Unit, _ = cmd.Flags().GetString("unit")
+	if options.Unit != "" && !stringInSlice(options.Unit, UnitList) {
+		return nil, fmt.Errorf("unit(%s) invalid. Available unit: %s", options.Unit, strings.Join(UnitList, ","))
+	}
+	valueUnit := options.Unit
+	if valueUnit == "" {
+		valueUnit = UnitETH
+	}
+
+	destinations, _ := cmd.Flags().GetStringSlice("destination")
+	for _, destination := range destinations {
+		if !common.IsHexAddress(destination) {
+			return nil, fmt.Errorf("destination address(%s) invalid", destination)
+		}
+		options.Filter.Destinations = append(options.Filter.Destinations, common.HexToAddress(destination))
+	}
+
+	if minValue, _ := cmd.Flags().GetString("min-value"); minValue != "" {
+		value, err := getAmountWei(minValue, valueUnit)
+		if err != nil {
+			return nil, fmt.Errorf("min value(%s) invalid: %v", minValue, err)
+		}
+		options.Filter.MinValue = value
+	}
+	if maxValue, _ := cmd.Flags().GetString("max-value"); maxValue != "" {
+		value, err := getAmountWei(maxValue, valueUnit)
+		if err != nil {
+			return nil, fmt.Errorf("max value(%s) invalid: %v", maxValue, err)
+		}
+		options.Filter.MaxValue = value
+	}
+
+	options.Filter.Methods, _ = cmd.Flags().GetStringSlice("method")
+
+	confirmedBy, _ := cmd.Flags().GetStringSlice("confirmed-by")
+	for _, owner := range confirmedBy {
+		if !common.IsHexAddress(owner) {
+			return nil, fmt.Errorf("owner address(%s) invalid", owner)
+		}
+		options.Filter.ConfirmedBy = append(options.Filter.ConfirmedBy, common.HexToAddress(owner))
+	}
+
+	if options.Filter.Awaiting, _ = cmd.Flags().GetBool("awaiting"); options.Filter.Awaiting {
+		from := viper.GetString("from")
+		if from == "" || !common.IsHexAddress(from) {
+			return nil, fmt.Errorf("not set from address of owner")
+		}
+		options.Filter.Owner = common.HexToAddress(from)
+	}
+
+	options.Sort, _ = cmd.Flags().GetString("sort")
+	if !stringInSlice(options.Sort, ListSortList) {
+		return nil, fmt.Errorf("sort key(%s) invalid. Available key: %s", options.Sort, strings.Join(ListSortList, ","))
+	}
+	options.Desc, _ = cmd.Flags().GetBool("desc")
+
+	options.Columns, _ = cmd.Flags().GetStringSlice("columns")
+	for _, column := range options.Columns {
+		if !stringInSlice(column, ListColumnList) {
+			return nil, fmt.Errorf("column(%s) invalid. Available column: %s", column, strings.Join(ListColumnList, ","))
+		}
+	}
+	if long, _ := cmd.Flags().GetBool("long"); long {
+		options.Columns = ListColumnList
+	}
+
+	return options, nil
+}
+
+// showTransactionList filters, sorts and prints txs by options
+func (cli *CLI) showTransactionList(txs []*walletTransaction, required *big.Int, options *txListOptions) {
+	wallet := common.HexToAddress(cli.contractAddress)
+	txs = options.Filter.filter(txs, wallet)
+	sortTransactions(txs, options.Sort, options.Desc)
+
+	if cli.isJSON() {
+		outputs := make([]*txInfoOutput, 0, len(txs))
+		for _, t := range txs {
+			outputs = append(outputs, newTxInfoOutput(t, required, wallet))
+		}
+		cli.printJSON(outputs)
+		return
+	}
+
+	if len(txs) == 0 {
+		fmt.Println("NO matching transaction ID")
 		return
 	}
+	printTransactionList(txs, required, wallet, options.Columns, options.Unit)
+}
+
+func (cli *CLI) listCachedTransactions(fromIndex, toIndex uint64, pending, executed bool, options *txListOptions) error {
+	cache, err := cli.openCache()
+	if err != nil {
+		return newErrorf(ErrCodeCache, "Error: %v", err)
+	}
+	defer cache.Close()
+
+	info, err := cache.Info()
+	if err != nil {
+		return newErrorf(ErrCodeCache, "Error: %v", err)
+	}
+	if info.TransactionCount == 0 {
+		if cli.isJSON() {
+			cli.printJSON([]*txInfoOutput{})
+		} else {
+			fmt.Println("NO transaction ID")
+		}
+		return nil
+	}
+	if toIndex == 0 || toIndex > info.TransactionCount {
+		toIndex = info.TransactionCount
+	}
+
+	txs, err := cache.Transactions(fromIndex, toIndex)
+	if err != nil {
+		return newErrorf(ErrCodeCache, "Error: %v", err)
+	}
+
+	var matched []*walletTransaction
+	for _, t := range txs {
+		if (t.Executed && executed) || (!t.Executed && pending) {
+			matched = append(matched, t)
+		}
+	}
+	cli.showTransactionList(matched, info.Required, options)
+	fmt.Printf("(cached at block %d)\n", info.SyncedBlock)
+	return nil
+}
+
+func (cli *CLI) showTxInfo(cmd *cobra.Command, args []string) error {
+	if len(args) < 0 {
+		return nil
+	}
 	txIDStr := args[0]
 	txID := new(big.Int)
 	txID, ok := txID.SetString(txIDStr, 10)
 	if !ok {
-		fmt.Println("transactionId Error")
-		return
+		return newErrorf(ErrCodeInvalidArgument, "transactionId Error")
 	}
 
 	unit, _ := cmd.Flags().GetString("unit")
 	if unit != "" && !stringInSlice(unit, UnitList) {
-		fmt.Printf("Unit(%s) for amount error. %s.\n", unit, fmt.Sprintf("Available unit: %s", strings.Join(UnitList, ",")))
 		fmt.Fprint(os.Stderr, cmd.UsageString())
-		return
+		return newErrorf(ErrCodeInvalidArgument, "Unit(%s) for amount error. Available unit: %s.", unit, strings.Join(UnitList, ","))
+	}
+
+	if cached, _ := cmd.Flags().GetBool("cached"); cached {
+		timeline, _ := cmd.Flags().GetBool("timeline")
+		return cli.showCachedTxInfo(txID, unit, timeline)
 	}
 
 	simpleRegistry, err := cli.GetSimpleRegistry()
 	if err != nil {
-		fmt.Println("GetSimpleRegistry Error: ", err)
-		fmt.Println(cmd.UsageString())
-		return
+		fmt.Fprint(os.Stderr, cmd.UsageString())
+		return newErrorf(ErrCodeRPC, "GetSimpleRegistry Error: %v", err)
 	}
 
 	count, err := simpleRegistry.GetTransactionCount(nil, true, true)
 	if err != nil {
-		fmt.Printf("Failed to call GetTransactionCount: %v[%s]\n", err, cli.contractAddress)
-		return
+		return newErrorf(ErrCodeRPC, "Failed to call GetTransactionCount: %v[%s]", err, cli.contractAddress)
 	}
 	if txID.Cmp(count) >= 0 {
-		fmt.Printf("TxID[%s] exceeds total number[%s] of transactions\n", txIDStr, count.String())
-		return
+		return newErrorf(ErrCodeTxNotFound, "TxID[%s] exceeds total number[%s] of transactions", txIDStr, count.String())
 	}
 
 	t, err := simpleRegistry.Transactions(nil, txID)
 	if err != nil {
-		fmt.Printf("Failed to call GetConfirmations: %v[%s]\n", err, cli.contractAddress)
-		return
+		return newErrorf(ErrCodeRPC, "Failed to call GetConfirmations: %v[%s]", err, cli.contractAddress)
+	}
+
+	if cli.isJSON() {
+		confirmations, err := simpleRegistry.GetConfirmations(nil, txID)
+		if err != nil {
+			return newErrorf(ErrCodeRPC, "GetConfirmations Error: %v", err)
+		}
+		required, err := simpleRegistry.Required(nil)
+		if err != nil {
+			return newErrorf(ErrCodeRPC, "Required Error: %v", err)
+		}
+		output := newTxInfoOutput(&walletTransaction{
+			ID:            txID,
+			Destination:   t.Destination,
+			Value:         t.Value,
+			Data:          t.Data,
+			Executed:      t.Executed,
+			Confirmations: confirmations,
+		}, required, common.HexToAddress(cli.contractAddress))
+		if timeline, _ := cmd.Flags().GetBool("timeline"); timeline {
+			if output.Timeline, err = cli.getTxTimelineByFlags(context.Background(), cmd, txID); err != nil {
+				return newErrorf(errorCode(err), "Timeline Error: %w", err)
+			}
+		}
+		cli.printJSON(output)
+		return nil
 	}
 
 	fmt.Printf("Transaction ID %s basic information is as follows:\n", txIDStr)
@@ -423,49 +652,121 @@ func (cli *CLI) showTxInfo(cmd *cobra.Command, args []string) {
 	fmt.Printf("Data: 0x%s\n", common.Bytes2Hex(t.Data))
 	showDataAuto(t.Data, "\t", unit)
 
+	if timeline, _ := cmd.Flags().GetBool("timeline"); timeline {
+		return cli.showTxTimeline(cmd, txID)
+	}
+
+	return nil
 }
 
-func showDataAuto(data []byte, indent, unit string) {
-	if len(data) >= 4 {
-		parsed, err := abi.JSON(strings.NewReader(MultiSigWalletWithDailyLimitABI))
+func (cli *CLI) showCachedTxInfo(txID *big.Int, unit string, timeline bool) error {
+	cache, err := cli.openCache()
+	if err != nil {
+		return newErrorf(ErrCodeCache, "Error: %v", err)
+	}
+	defer cache.Close()
+
+	info, err := cache.Info()
+	if err != nil {
+		return newErrorf(ErrCodeCache, "Error: %v", err)
+	}
+	if !txID.IsUint64() || txID.Uint64() >= info.TransactionCount {
+		return newErrorf(ErrCodeTxNotFound, "TxID[%s] exceeds total number[%d] of transactions", txID.String(), info.TransactionCount)
+	}
+	t, err := cache.Transaction(txID.Uint64())
+	if err != nil {
+		return newErrorf(ErrCodeCache, "Error: %v", err)
+	}
+
+	if cli.isJSON() {
+		output := newTxInfoOutput(t, info.Required, info.Contract)
+		if timeline {
+			events, err := cache.Events(0, info.SyncedBlock)
+			if err != nil {
+				return newErrorf(ErrCodeCache, "Timeline: Events Error(%v)", err)
+			}
+			output.Timeline = filterEventsLocal(events, &eventFilter{
+				Names:          timelineEventList,
+				TransactionIDs: []*big.Int{txID},
+			})
+		}
+		cli.printJSON(output)
+		return nil
+	}
+
+	fmt.Printf("Transaction ID %s basic information is as follows (cached at block %d):\n", txID.String(), info.SyncedBlock)
+	if t.Destination == info.Contract {
+		fmt.Printf("Destination Address: %s (contract itself)\n", t.Destination.String())
+	} else {
+		fmt.Println("Destination Address: ", t.Destination.String())
+	}
+	fmt.Println("Value: ", getWeiAmountTextUnitByUnit(t.Value, unit))
+
+	count := big.NewInt(int64(len(t.Confirmations)))
+	if count.Cmp(info.Required) >= 0 {
+		fmt.Printf("Confirmation status: Confirmed(%s/%s)\n", count.String(), info.Required.String())
+	} else {
+		fmt.Printf("Confirmation status: NOT confirmed(%s/%s)\n", count.String(), info.Required.String())
+	}
+	if len(t.Confirmations) > 0 {
+		fmt.Printf("Confirmed owner list (%d):\n", len(t.Confirmations))
+		for _, v := range t.Confirmations {
+			fmt.Printf("\t%s\n", v.String())
+		}
+	}
+
+	if t.Executed {
+		fmt.Println("Execution status: Executed")
+	} else {
+		fmt.Println("Execution status: Pending")
+	}
+
+	fmt.Printf("Data: 0x%s\n", common.Bytes2Hex(t.Data))
+	showDataAuto(t.Data, "\t", unit)
+
+	if timeline {
+		events, err := cache.Events(0, info.SyncedBlock)
 		if err != nil {
-			fmt.Printf("JSON err: %v\n", err)
-			return
+			return newErrorf(ErrCodeCache, "Timeline: Events Error(%v)", err)
 		}
-		sigdata, argdata := data[:4], data[4:]
-		method, err := parsed.MethodById(sigdata)
-		if err != nil || method == nil {
-			// just data
-			if utf8.Valid(data) {
-				fmt.Printf("%s%s\n", indent, data)
-			}
-			return
+		printTxTimeline(txID, filterEventsLocal(events, &eventFilter{
+			Names:          timelineEventList,
+			TransactionIDs: []*big.Int{txID},
+		}))
+	}
+
+	return nil
+}
+
+func showDataAuto(data []byte, indent, unit string) {
+	call, err := msw.DecodeCall(data)
+	if err != nil {
+		// just data
+		if len(data) > 0 && utf8.Valid(data) {
+			fmt.Printf("%s%s\n", indent, data)
 		}
-		fmt.Printf("%s%s\n", indent, method.String())
-
-		nonIndexed := method.Inputs.NonIndexed()
-		data, err := method.Inputs.UnpackValues(argdata)
-
-		for k, v := range data {
-			if nonIndexed[k].Type.T == abi.AddressTy {
-				fmt.Printf("%s\t%s: %s \n", indent, nonIndexed[k].Name, v.(common.Address).String())
-			} else if nonIndexed[k].Type.T == abi.BytesTy {
-				vb := v.([]byte)
-				fmt.Printf("%s\t%s: 0x%s\n", indent, nonIndexed[k].Name, common.Bytes2Hex(vb))
-				if len(vb) > 0 && utf8.Valid(vb) {
-					fmt.Printf("%s\t\t%s\n", indent, vb)
-				}
-			} else if nonIndexed[k].Name == "value" {
-				vbig, ok := v.(*big.Int)
-				if ok {
-					fmt.Printf("%s\t%s: %v\n", indent, nonIndexed[k].Name, getWeiAmountTextUnitByUnit(vbig, unit))
-				} else {
-					fmt.Printf("%s\t%s: %v %v\n", indent, nonIndexed[k].Name, v, nonIndexed[k].Type.T)
-				}
+		return
+	}
+	fmt.Printf("%s%s\n", indent, call.Signature)
+
+	for _, arg := range call.Args {
+		if arg.Type.T == abi.AddressTy {
+			fmt.Printf("%s\t%s: %s \n", indent, arg.Name, arg.Value.(common.Address).String())
+		} else if arg.Type.T == abi.BytesTy {
+			vb := arg.Value.([]byte)
+			fmt.Printf("%s\t%s: 0x%s\n", indent, arg.Name, common.Bytes2Hex(vb))
+			if len(vb) > 0 && utf8.Valid(vb) {
+				fmt.Printf("%s\t\t%s\n", indent, vb)
+			}
+		} else if arg.Name == "value" {
+			vbig, ok := arg.Value.(*big.Int)
+			if ok {
+				fmt.Printf("%s\t%s: %v\n", indent, arg.Name, getWeiAmountTextUnitByUnit(vbig, unit))
 			} else {
-				fmt.Printf("%s\t%s: %v\n", indent, nonIndexed[k].Name, v)
+				fmt.Printf("%s\t%s: %v %v\n", indent, arg.Name, arg.Value, arg.Type.T)
 			}
-
+		} else {
+			fmt.Printf("%s\t%s: %v\n", indent, arg.Name, arg.Value)
 		}
 	}
 }
@@ -1,6 +1,12 @@
 package cli
 
-import "testing"
+import (
+	"context"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"testing"
+)
 
 func TestTx(t *testing.T) {
 	cli := NewCLI()
@@ -12,5 +18,26 @@ func TestTx(t *testing.T) {
 	cli.TestCommand("check 9")
 
 	cli.TestCommand("info 9")
+	cli.TestCommand("info 9 --timeline --start 100")
 	cli.TestCommand("list")
+	cli.TestCommand("list --pending --batch 10 --workers 2")
+	cli.TestCommand("list --awaiting --method addOwner,transfer --sort value --desc -l")
+}
+
+func TestTxTimelineStart(t *testing.T) {
+	dir, err := ioutil.TempDir("", "timeline")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	cli := NewCLI()
+	cli.cachePath = dir
+	cli.contractAddress = "0xf09E6759c2588eE8435902d16350E321CBD27af3"
+	cmd := cli.buildInfoCmd()
+
+	// the timeline is not scanned from block 0 without the cache
+	if _, err := cli.getTxTimelineByFlags(context.Background(), cmd, big.NewInt(1)); errorCode(err) != ErrCodeInvalidArgument {
+		t.Fatalf("timeline without start and cache got %v", err)
+	}
 }
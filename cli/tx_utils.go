@@ -2,312 +2,295 @@ package cli
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"math/big"
+	"strings"
 
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/newtonproject/MultiSignatureWallet/msw"
+	"github.com/spf13/cobra"
 )
 
-// SubmitTransaction SubmitTransaction
-func (cli *CLI) SubmitTransaction(fromAddress string, toAddress common.Address, value *big.Int, data []byte) {
-	var err error
-
+// walletTransactOpts returns the wallet and the TransactOpts of fromAddress
+// to send the transaction of action
+func (cli *CLI) walletTransactOpts(fromAddress, action string) (*msw.Wallet, *bind.TransactOpts, error) {
 	if !common.IsHexAddress(fromAddress) {
-		fmt.Println("Error: fromAddress is invalid hex-encoded: ", fromAddress)
-		return
+		return nil, nil, newErrorf(ErrCodeInvalidArgument, "Error: fromAddress is invalid hex-encoded: %s", fromAddress)
 	}
 
-	simpleRegistry, err := cli.GetSimpleRegistry()
+	w, err := cli.GetWallet()
 	if err != nil {
-		fmt.Println("GetSimpleRegistry Error: ", err)
-		return
+		return nil, nil, newErrorf(ErrCodeRPC, "GetWallet Error: %v", err)
 	}
 
-	if isowner, err := simpleRegistry.IsOwner(nil, common.HexToAddress(fromAddress)); err != nil || !isowner {
-		fmt.Println("Error: fromAddress is not the owner: ", fromAddress)
-		return
+	// check the owner before unlocking the account
+	isOwner, err := w.IsOwner(context.Background(), common.HexToAddress(fromAddress))
+	if err != nil {
+		return nil, nil, newErrorf(ErrCodeRPC, "IsOwner Error: %v", err)
+	}
+	if !isOwner {
+		return nil, nil, newErrorf(ErrCodeNotOwner, "Error: fromAddress is not the owner: %s", fromAddress)
 	}
 
-	opts, err := cli.getTransactOpts(fromAddress)
+	opts, err := cli.getTransactOpts(fromAddress, action)
 	if err != nil {
-		fmt.Println("GetTransactOpts: ", err)
-		return
+		return nil, nil, newErrorf(ErrCodeWallet, "GetTransactOpts: %w", err)
 	}
 
-	tx, err := simpleRegistry.SubmitTransaction(opts, toAddress, value, data)
+	return w, opts, nil
+}
+
+// SubmitTransaction SubmitTransaction
+func (cli *CLI) SubmitTransaction(fromAddress string, toAddress common.Address, value *big.Int, data []byte) error {
+	w, opts, err := cli.walletTransactOpts(fromAddress, GasActionSubmit)
 	if err != nil {
-		fmt.Println("SubmitTransaction error: ", err)
-		return
+		return err
 	}
 
-	fmt.Println("Transaction hash is: ", tx.Hash().String())
-
-	//var transaction *big.Int
 	ctx := context.Background()
-
-	fmt.Println("Waiting for transaction receipt to get your transferID...")
-	txp, err := bind.WaitMined(ctx, cli.client, tx)
+	tx, err := w.Submit(ctx, opts, toAddress, value, data)
 	if err != nil {
-		fmt.Printf("Error: wait tx mined error(%v)\n", err)
-		return
-	}
-	if !cli.showSubmitID(txp) {
-		fmt.Println("No transferID get, please use transaction hash to get it later")
+		return txError(err)
 	}
-	return
-}
-
-var GasFail = "failed to estimate gas needed: gas required exceeds allowance or always failing transaction"
 
-// ConfirmTransaction ConfirmTransaction
-func (cli *CLI) ConfirmTransaction(fromAddress string, transactionId *big.Int) {
-	var err error
+	fmt.Println("Transaction hash is: ", tx.Hash().String())
+	cli.journalTx(tx, opts.From)
 
-	if !common.IsHexAddress(fromAddress) {
-		fmt.Println("Error: fromAddress is invalid hex-encoded: ", fromAddress)
-		return
+	result := cli.waitAndShowReceipt(ctx, tx)
+	if result == nil || result.submission() == nil {
+		fmt.Println("No transferID get, please use tx status with the transaction hash to get it later")
 	}
+	return cli.printSentTx(tx, opts.From, result)
+}
 
-	simpleRegistry, err := cli.GetSimpleRegistry()
-	if err != nil {
-		fmt.Println("ConfirmTransaction Error: ", err)
-		return
-	}
+// isGasFail reports whether err is the failure of estimating gas
+func isGasFail(err error) bool {
+	var txErr *msw.TxError
+	return errors.As(err, &txErr) && strings.HasPrefix(txErr.Err.Error(), "failed to estimate gas")
+}
 
-	if isowner, err := simpleRegistry.IsOwner(nil, common.HexToAddress(fromAddress)); err != nil || !isowner {
-		fmt.Println("Error: fromAddress is not the owner: ", fromAddress)
-		return
+// txError returns the error of sending the transaction to the wallet. The
+// checks of msw have passed, so the failure of estimating gas is that the
+// transaction would revert, not that it has been confirmed or executed.
+func txError(err error) error {
+	if isGasFail(err) {
+		return newErrorf(ErrCodeTxFailed, "%w, the transaction would revert, see the reason with --dry-run", err)
 	}
+	return walletError(err)
+}
 
-	count, err := simpleRegistry.GetTransactionCount(nil, true, true)
+// ConfirmTransaction ConfirmTransaction
+func (cli *CLI) ConfirmTransaction(fromAddress string, transactionId *big.Int) error {
+	w, opts, err := cli.walletTransactOpts(fromAddress, GasActionConfirm)
 	if err != nil {
-		fmt.Printf("Failed to call GetTransactionCount: %v[%s]\n", err, cli.contractAddress)
-		return
-	}
-	if transactionId.Cmp(count) >= 0 {
-		fmt.Printf("TxID[%s] exceeds total number[%s] of transactions\n", transactionId.String(), count.String())
-		return
+		return err
 	}
 
-	transaction, err := simpleRegistry.Transactions(nil, transactionId)
+	ctx := context.Background()
+	tx, err := w.Confirm(ctx, opts, transactionId)
 	if err != nil {
-		fmt.Println("Transactions Error: ", err)
-		return
-	}
-	if transaction.Executed {
-		fmt.Printf("Transaction ID(%s) has been Executed\n", transactionId.String())
-		return
+		return txError(err)
 	}
 
-	isConfirmed, err := simpleRegistry.IsConfirmed(nil, transactionId)
-	if err != nil {
-		fmt.Println("IsConfirmed Error: ", err)
-		return
-	}
-	if isConfirmed {
-		fmt.Printf("Transaction ID(%s) has been Confirmed\n", transactionId.String())
-		return
-	}
+	fmt.Println("Transaction hash is: ", tx.Hash().String())
+	cli.journalTx(tx, opts.From)
 
-	confirmation, err := simpleRegistry.Confirmations(nil, transactionId, common.HexToAddress(fromAddress))
-	if err != nil {
-		fmt.Println("Confirmations Error: ", err)
-		return
-	}
-	if confirmation {
-		fmt.Printf("Address[%s] has confirmated transaction ID(%s)\n", fromAddress, transactionId.String())
-		return
-	}
+	return cli.printSentTx(tx, opts.From, cli.waitAndShowReceipt(ctx, tx))
+}
 
-	opts, err := cli.getTransactOpts(fromAddress)
+// RevokeConfirmation RevokeConfirmation
+func (cli *CLI) RevokeConfirmation(fromAddress string, transactionId *big.Int) error {
+	w, opts, err := cli.walletTransactOpts(fromAddress, GasActionRevoke)
 	if err != nil {
-		fmt.Println("GetTransactOpts: ", err)
-		return
+		return err
 	}
 
-	tx, err := simpleRegistry.ConfirmTransaction(opts, transactionId)
+	ctx := context.Background()
+	tx, err := w.Revoke(ctx, opts, transactionId)
 	if err != nil {
-		if err.Error() == GasFail {
-			fmt.Printf("ID(%s) has been confirmed\n", transactionId.String())
-		} else {
-			fmt.Println("SubmitTransaction error: ", err)
-		}
-
-		return
+		return txError(err)
 	}
 
 	fmt.Println("Transaction hash is: ", tx.Hash().String())
+	cli.journalTx(tx, opts.From)
 
+	return cli.printSentTx(tx, opts.From, cli.waitAndShowReceipt(ctx, tx))
 }
 
-// RevokeConfirmation RevokeConfirmation
-func (cli *CLI) RevokeConfirmation(fromAddress string, transactionId *big.Int) {
-	var err error
-
-	if !common.IsHexAddress(fromAddress) {
-		fmt.Println("Error: fromAddress is invalid hex-encoded: ", fromAddress)
-		return
+// ExecuteTransaction ExecuteTransaction
+func (cli *CLI) ExecuteTransaction(fromAddress string, transactionId *big.Int) error {
+	w, opts, err := cli.walletTransactOpts(fromAddress, GasActionExecute)
+	if err != nil {
+		return err
 	}
 
-	simpleRegistry, err := cli.GetSimpleRegistry()
+	ctx := context.Background()
+	tx, err := w.Execute(ctx, opts, transactionId)
 	if err != nil {
-		fmt.Println("ConfirmTransaction Error: ", err)
-		return
+		return txError(err)
 	}
 
-	if isowner, err := simpleRegistry.IsOwner(nil, common.HexToAddress(fromAddress)); err != nil || !isowner {
-		fmt.Println("Error: fromAddress is not the owner: ", fromAddress)
-		return
-	}
+	fmt.Println("Transaction hash is: ", tx.Hash().String())
+	cli.journalTx(tx, opts.From)
 
-	count, err := simpleRegistry.GetTransactionCount(nil, true, true)
-	if err != nil {
-		fmt.Printf("Failed to call GetTransactionCount: %v[%s]\n", err, cli.contractAddress)
-		return
-	}
-	if transactionId.Cmp(count) >= 0 {
-		fmt.Printf("TxID[%s] exceeds total number[%s] of transactions\n", transactionId.String(), count.String())
-		return
-	}
+	return cli.printSentTx(tx, opts.From, cli.waitAndShowReceipt(ctx, tx))
+}
 
-	transaction, err := simpleRegistry.Transactions(nil, transactionId)
+// CheckTransactionStatus CheckTransaction
+func (cli *CLI) CheckTransactionStatus(transactionId *big.Int) {
+	w, err := cli.GetWallet()
 	if err != nil {
-		fmt.Println("Transactions Error: ", err)
+		fmt.Printf("Confirmation status: GetWallet Error(%v) ", err)
 		return
 	}
-	if transaction.Executed {
-		fmt.Printf("Transaction ID(%s) has been Executed\n", transactionId.String())
-		return
-	}
-
-	confirmation, err := simpleRegistry.Confirmations(nil, transactionId, common.HexToAddress(fromAddress))
+	status, err := w.Status(context.Background(), transactionId)
 	if err != nil {
-		fmt.Println("Confirmations Error: ", err)
-		return
-	}
-	if !confirmation {
-		fmt.Printf("Address[%s] NOT confirmate transaction ID(%s)\n", fromAddress, transactionId.String())
+		fmt.Printf("Confirmation status: Status Error(%v)\n", err)
 		return
 	}
 
-	opts, err := cli.getTransactOpts(fromAddress)
-	if err != nil {
-		fmt.Println("GetTransactOpts: ", err)
-		return
+	count := len(status.Confirmations)
+	if status.Confirmed {
+		fmt.Printf("Confirmation status: Confirmed(%d/%s)\n", count, status.Required.String())
+	} else {
+		fmt.Printf("Confirmation status: NOT confirmed(%d/%s)\n", count, status.Required.String())
 	}
 
-	tx, err := simpleRegistry.RevokeConfirmation(opts, transactionId)
-	if err != nil {
-		fmt.Println("SubmitTransaction error: ", err)
-		return
+	if count > 0 {
+		fmt.Printf("Confirmed owner list (%d):\n", count)
+		for _, v := range status.Confirmations {
+			fmt.Printf("\t%s\n", v.String())
+		}
 	}
-
-	fmt.Println("Transaction hash is: ", tx.Hash().String())
 }
 
-// ExecuteTransaction ExecuteTransaction
-func (cli *CLI) ExecuteTransaction(fromAddress string, transactionId *big.Int) {
-	var err error
+// timelineEventList is the events in the life of a transaction ID
+var timelineEventList = []string{EventSubmission, EventConfirmation, EventRevocation, EventExecution, EventExecutionFailure}
 
-	if !common.IsHexAddress(fromAddress) {
-		fmt.Println("Error: fromAddress is invalid hex-encoded: ", fromAddress)
-		return
+// GetTxTimeline gets the events of transactionId from block start to the
+// latest block, that is submission, confirmations, revocations and execution
+func (cli *CLI) GetTxTimeline(ctx context.Context, transactionId *big.Int, start, chunkSize uint64) ([]*walletEvent, error) {
+	if _, err := cli.GetSimpleRegistry(); err != nil {
+		return nil, err
 	}
 
-	simpleRegistry, err := cli.GetSimpleRegistry()
+	header, err := cli.client.HeaderByNumber(ctx, nil)
 	if err != nil {
-		fmt.Println("ConfirmTransaction Error: ", err)
-		return
+		return nil, fmt.Errorf("get latest block error: %v", err)
 	}
-
-	if isowner, err := simpleRegistry.IsOwner(nil, common.HexToAddress(fromAddress)); err != nil || !isowner {
-		fmt.Println("Error: fromAddress is not the owner: ", fromAddress)
-		return
+	end := header.Number.Uint64()
+	if start > end {
+		return nil, fmt.Errorf("start block(%d) is greater than latest block(%d)", start, end)
 	}
 
-	count, err := simpleRegistry.GetTransactionCount(nil, true, true)
-	if err != nil {
-		fmt.Printf("Failed to call GetTransactionCount: %v[%s]\n", err, cli.contractAddress)
-		return
-	}
-	if transactionId.Cmp(count) >= 0 {
-		fmt.Printf("TxID[%s] exceeds total number[%s] of transactions\n", transactionId.String(), count.String())
-		return
+	filter := &eventFilter{
+		Names:          timelineEventList,
+		TransactionIDs: []*big.Int{transactionId},
 	}
 
-	transaction, err := simpleRegistry.Transactions(nil, transactionId)
-	if err != nil {
-		fmt.Println("Transactions Error: ", err)
-		return
-	}
-	if transaction.Executed {
-		fmt.Printf("Transaction ID(%s) has been Executed\n", transactionId.String())
-		return
+	return cli.filterEvents(ctx, start, end, chunkSize, filter)
+}
+
+// getTxTimelineByFlags gets the timeline of transactionId from the block of
+// the flag start. Without it, the events cached by sync are used with the
+// events of the blocks after the cache, so the blocks before the wallet are
+// never scanned from block 0.
+func (cli *CLI) getTxTimelineByFlags(ctx context.Context, cmd *cobra.Command, transactionId *big.Int) ([]*walletEvent, error) {
+	chunk, _ := cmd.Flags().GetUint64("chunk")
+	if cmd.Flags().Changed("start") {
+		start, _ := cmd.Flags().GetUint64("start")
+		events, err := cli.GetTxTimeline(ctx, transactionId, start, chunk)
+		if err != nil {
+			return nil, newErrorf(ErrCodeRPC, "GetTxTimeline Error(%v)", err)
+		}
+		return events, nil
 	}
 
-	opts, err := cli.getTransactOpts(fromAddress)
+	cache, err := cli.openCache()
+	if err == errCacheNotSynced {
+		return nil, newErrorf(ErrCodeInvalidArgument, "Error: --start is required without the cache, run sync first or set the block the wallet was deployed at")
+	} else if err != nil {
+		return nil, newErrorf(ErrCodeCache, "Error: %v", err)
+	}
+	defer cache.Close()
+	info, err := cache.Info()
 	if err != nil {
-		fmt.Println("GetTransactOpts: ", err)
-		return
+		return nil, newErrorf(ErrCodeCache, "Error: %v", err)
 	}
-
-	tx, err := simpleRegistry.ExecuteTransaction(opts, transactionId)
+	cached, err := cache.Events(0, info.SyncedBlock)
 	if err != nil {
-		if err.Error() == GasFail {
-			fmt.Printf("ID(%s) has been executed\n", transactionId.String())
-		} else {
-			fmt.Println("SubmitTransaction error: ", err)
-		}
-		return
+		return nil, newErrorf(ErrCodeCache, "Events Error(%v)", err)
 	}
+	events := filterEventsLocal(cached, &eventFilter{
+		Names:          timelineEventList,
+		TransactionIDs: []*big.Int{transactionId},
+	})
 
-	fmt.Println("Transaction hash is: ", tx.Hash().String())
-
-}
-
-// CheckTransactionStatus CheckTransaction
-func (cli *CLI) CheckTransactionStatus(transactionId *big.Int) {
-	var err error
-
-	simpleRegistry, err := cli.GetSimpleRegistry()
+	if err := cli.BuildClient(); err != nil {
+		return nil, newErrorf(ErrCodeRPC, "Build client error(%s)", err)
+	}
+	header, err := cli.client.HeaderByNumber(ctx, nil)
 	if err != nil {
-		fmt.Printf("Confirmation status: GetSimpleRegistry Error(%v) ", err)
-		return
+		return nil, newErrorf(ErrCodeRPC, "get latest block error: %v", err)
+	}
+	if header.Number.Uint64() <= info.SyncedBlock {
+		return events, nil
 	}
-	ok, err := simpleRegistry.IsConfirmed(nil, transactionId)
+	newer, err := cli.GetTxTimeline(ctx, transactionId, info.SyncedBlock+1, chunk)
 	if err != nil {
-		fmt.Printf("Confirmation status: IsConfirmed Error(%v)", err)
-		return
+		return nil, newErrorf(ErrCodeRPC, "GetTxTimeline Error(%v)", err)
 	}
+	return append(events, newer...), nil
+}
 
-	count, err := simpleRegistry.GetConfirmationCount(nil, transactionId)
+// showTxTimeline shows the life of transactionId in the order of events
+func (cli *CLI) showTxTimeline(cmd *cobra.Command, transactionId *big.Int) error {
+	events, err := cli.getTxTimelineByFlags(context.Background(), cmd, transactionId)
 	if err != nil {
-		fmt.Printf("Confirmation status: GetTransactionCount Error(%v)\n", err)
-		return
+		return newErrorf(errorCode(err), "Timeline: %w", err)
 	}
+	printTxTimeline(transactionId, events)
+	return nil
+}
 
-	required, err := simpleRegistry.Required(nil)
-	if err != nil {
-		fmt.Printf("Confirmation status: Required Error(%v)", err)
+// printTxTimeline prints the timeline events of transactionId
+func printTxTimeline(transactionId *big.Int, events []*walletEvent) {
+	if len(events) == 0 {
+		fmt.Printf("Timeline: NO event of transaction ID %s\n", transactionId.String())
 		return
 	}
 
-	if ok {
-		fmt.Printf("Confirmation status: Confirmed(%s/%s)\n", count.String(), required.String())
-	} else {
-		fmt.Printf("Confirmation status: NOT confirmed(%s/%s)\n", count.String(), required.String())
+	// the submitter confirms in the same transaction of Submission
+	submitters := make(map[common.Hash]common.Address)
+	for _, e := range events {
+		if e.Name == EventConfirmation {
+			if _, ok := submitters[e.TxHash]; !ok {
+				submitters[e.TxHash] = e.Sender
+			}
+		}
 	}
 
-	if count.Cmp(big.NewInt(0)) > 0 {
-		ownerslist, err := simpleRegistry.GetConfirmations(nil, transactionId)
-		if err != nil {
-			fmt.Printf("Confirmed owner list: GetConfirmations Error: (%v)\n", err)
-			return
-		}
-		fmt.Printf("Confirmed owner list (%d):\n", len(ownerslist))
-		for _, v := range ownerslist {
-			fmt.Printf("\t%s\n", v.String())
+	result := "Pending"
+	fmt.Printf("Timeline (%d):\n", len(events))
+	for _, e := range events {
+		var action string
+		switch e.Name {
+		case EventSubmission:
+			action = fmt.Sprintf("Submitted by %s", submitters[e.TxHash].String())
+		case EventConfirmation:
+			action = fmt.Sprintf("Confirmed by %s", e.Sender.String())
+		case EventRevocation:
+			action = fmt.Sprintf("Revoked by %s", e.Sender.String())
+		case EventExecution:
+			action = "Executed"
+			result = "Executed"
+		case EventExecutionFailure:
+			action = "Execution failed"
+			result = "ExecutionFailure"
 		}
+		fmt.Printf("\t%s Block %d %s %s\n", e.BlockTime.Format("2006-01-02 15:04:05"), e.BlockNumber, e.TxHash.String(), action)
 	}
+	fmt.Println("Timeline result:", result)
 }
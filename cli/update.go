@@ -2,7 +2,6 @@ package cli
 
 import (
 	"fmt"
-	"log"
 	"math/big"
 	"os"
 	"strings"
@@ -17,8 +16,9 @@ func (cli *CLI) buildUpdateCmd() *cobra.Command {
 		Use:   "update [dailylimit|required]",
 		Short: "Manage daily limit and requirement",
 		Args:  cobra.MinimumNArgs(1),
-		Run: func(cmd *cobra.Command, args []string) {
-			return
+		RunE: func(cmd *cobra.Command, args []string) error {
+			fmt.Fprint(os.Stderr, cmd.UsageString())
+			return newErrorf(ErrCodeInvalidArgument, "Error: unknown command %q for %q", args[0], cmd.CommandPath())
 		},
 	}
 
@@ -34,49 +34,43 @@ func (cli *CLI) buildUpdateDailyLimitCmd() *cobra.Command {
 		Long:                  "Allows to change the daily limit. Transaction has to be sent by wallet",
 		Args:                  cobra.MinimumNArgs(1),
 		DisableFlagsInUseLine: true,
-		Run: func(cmd *cobra.Command, args []string) {
+		RunE: func(cmd *cobra.Command, args []string) error {
 			var err error
 
 			fromAddress := viper.GetString("from")
 			if fromAddress == "" || !common.IsHexAddress(fromAddress) {
-				fmt.Println("Error: not set from address of owner")
 				fmt.Fprint(os.Stderr, cmd.UsageString())
-				return
+				return newErrorf(ErrCodeInvalidArgument, "Error: not set from address of owner")
 			}
 
 			amountStr := args[0]
 			if amountStr == "" {
-				fmt.Println("Error: not set daily limit ")
-				fmt.Println(cmd.UsageString())
-				return
+				fmt.Fprint(os.Stderr, cmd.UsageString())
+				return newErrorf(ErrCodeInvalidArgument, "Error: not set daily limit ")
 			}
 			unit, err := cmd.Flags().GetString("unit")
 			if err != nil {
-				fmt.Println("Error: required flag(s) \"unit\" not set")
 				fmt.Fprint(os.Stderr, cmd.UsageString())
-				return
+				return newErrorf(ErrCodeInvalidArgument, "Error: required flag(s) \"unit\" not set")
 			}
 			d := stringInSlice(unit, UnitList)
 			if !d {
-				fmt.Printf("Unit(%s) for amount error. %s.\n", unit, fmt.Sprintf("Available unit: %s", strings.Join(UnitList, ",")))
 				fmt.Fprint(os.Stderr, cmd.UsageString())
-				return
+				return newErrorf(ErrCodeInvalidArgument, "Unit(%s) for amount error. Available unit: %s.", unit, strings.Join(UnitList, ","))
 			}
 
 			amountWei, err := getAmountWei(amountStr, unit)
 			if err != nil {
-				fmt.Println("Get amount error:", err)
 				fmt.Fprint(os.Stderr, cmd.UsageString())
-				return
+				return newErrorf(ErrCodeInvalidArgument, "Get amount error: %v", err)
 			}
 
 			data, err := cli.GetMethodData("changeDailyLimit", amountWei)
 			if err != nil {
-				fmt.Println("GetMethodData error: ", err)
-				return
+				return newErrorf(ErrCodeInvalidArgument, "GetMethodData error: %v", err)
 			}
 
-			cli.SubmitTransaction(fromAddress, common.HexToAddress(cli.contractAddress), big.NewInt(0), data)
+			return cli.SubmitTransaction(fromAddress, common.HexToAddress(cli.contractAddress), big.NewInt(0), data)
 		},
 	}
 
@@ -92,27 +86,24 @@ func (cli *CLI) buildRequirementCmd() *cobra.Command {
 		Long:                  "Allows to change the number of required confirmations. Transaction has to be sent by wallet",
 		Args:                  cobra.MinimumNArgs(1),
 		DisableFlagsInUseLine: true,
-		Run: func(cmd *cobra.Command, args []string) {
+		RunE: func(cmd *cobra.Command, args []string) error {
 			var err error
 
 			fromAddress := viper.GetString("from")
 			if fromAddress == "" || !common.IsHexAddress(fromAddress) {
-				fmt.Println("Error: not set from address of owner")
 				fmt.Fprint(os.Stderr, cmd.UsageString())
-				return
+				return newErrorf(ErrCodeInvalidArgument, "Error: not set from address of owner")
 			}
 
 			numberStr := args[0]
 			if numberStr == "" {
-				fmt.Println("Error: not set daily limit ")
-				fmt.Println(cmd.UsageString())
-				return
+				fmt.Fprint(os.Stderr, cmd.UsageString())
+				return newErrorf(ErrCodeInvalidArgument, "Error: not set daily limit ")
 			}
 			number := new(big.Int)
 			number, ok := number.SetString(numberStr, 10)
 			if !ok {
-				fmt.Println("Error: daily limit invalid")
-				return
+				return newErrorf(ErrCodeInvalidArgument, "Error: daily limit invalid")
 			}
 
 			// check number
@@ -121,12 +112,11 @@ func (cli *CLI) buildRequirementCmd() *cobra.Command {
 			} else {
 				simpleRegistry, err := cli.GetSimpleRegistry()
 				if err != nil {
-					fmt.Println("GetSimpleRegistry Error: ", err)
-					return
+					return newErrorf(ErrCodeRPC, "GetSimpleRegistry Error: %v", err)
 				}
 				owners, err := simpleRegistry.GetOwners(nil)
 				if err != nil {
-					log.Fatalf("Failed to call GetOwners: %v[%s]", err, cli.contractAddress)
+					return newErrorf(ErrCodeRPC, "Failed to call GetOwners: %v[%s]", err, cli.contractAddress)
 				}
 				olen := len(owners)
 				olenBig := big.NewInt(int64(olen))
@@ -137,11 +127,10 @@ func (cli *CLI) buildRequirementCmd() *cobra.Command {
 
 			data, err := cli.GetMethodData("changeRequirement", number)
 			if err != nil {
-				fmt.Println("GetMethodData error: ", err)
-				return
+				return newErrorf(ErrCodeInvalidArgument, "GetMethodData error: %v", err)
 			}
 
-			cli.SubmitTransaction(fromAddress, common.HexToAddress(cli.contractAddress), big.NewInt(0), data)
+			return cli.SubmitTransaction(fromAddress, common.HexToAddress(cli.contractAddress), big.NewInt(0), data)
 		},
 	}
 
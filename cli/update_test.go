@@ -9,3 +9,11 @@ func TestUpdate(t *testing.T) {
 	cli.TestCommand("update dailylimit 10 -u Wei")
 	cli.TestCommand("update required 2")
 }
+
+func TestUpdateUnknown(t *testing.T) {
+	cli := NewCLI()
+	cli.rootCmd.SetArgs([]string{"update", "bogus"})
+	if err := cli.rootCmd.Execute(); ExitCode(err) != ExitInvalidArgument {
+		t.Fatalf("update with unknown command got error %v", err)
+	}
+}
@@ -9,11 +9,11 @@ import (
 	"net/http"
 	"os"
 	"regexp"
-	"strings"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/console"
 	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/newtonproject/MultiSignatureWallet/msw"
 	"github.com/sirupsen/logrus"
 )
 
@@ -93,33 +93,7 @@ func stringInSlice(str string, list []string) bool {
 func getAmountWei(amountStr, unit string) (*big.Int, error) {
 	switch unit {
 	case UnitETH:
-		index := strings.IndexByte(amountStr, '.')
-		if index <= 0 {
-			amountWei, ok := new(big.Int).SetString(amountStr, 10)
-			if !ok {
-				return nil, errBigSetString
-			}
-			return new(big.Int).Mul(amountWei, big1NEWInWEI), nil
-		}
-		amountStrInt := amountStr[:index]
-		amountStrDec := amountStr[index+1:]
-		amountStrDecLen := len(amountStrDec)
-		if amountStrDecLen > 18 {
-			return nil, errIllegalAmount
-		}
-		amountStrInt = amountStrInt + strings.Repeat("0", 18)
-		amountStrDec = amountStrDec + strings.Repeat("0", 18-amountStrDecLen)
-
-		amountStrIntBig, ok := new(big.Int).SetString(amountStrInt, 10)
-		if !ok {
-			return nil, errBigSetString
-		}
-		amountStrDecBig, ok := new(big.Int).SetString(amountStrDec, 10)
-		if !ok {
-			return nil, errBigSetString
-		}
-
-		return new(big.Int).Add(amountStrIntBig, amountStrDecBig), nil
+		return msw.ParseAmount(amountStr, 18)
 	case UnitWEI:
 		amountWei, ok := new(big.Int).SetString(amountStr, 10)
 		if !ok {
@@ -153,27 +127,11 @@ func getWeiAmountTextByUnit(amount *big.Int, unit string) string {
 	if amount == nil {
 		return "0"
 	}
-	amountStr := amount.String()
-	amountStrLen := len(amountStr)
-
 	switch unit {
 	case UnitETH:
-		var amountStrDec, amountStrInt string
-		if amountStrLen <= 18 {
-			amountStrDec = strings.Repeat("0", 18-amountStrLen) + amountStr
-			amountStrInt = "0"
-		} else {
-			amountStrDec = amountStr[amountStrLen-18:]
-			amountStrInt = amountStr[:amountStrLen-18]
-		}
-		amountStrDec = strings.TrimRight(amountStrDec, "0")
-		if len(amountStrDec) <= 0 {
-			return amountStrInt
-		}
-		return amountStrInt + "." + amountStrDec
-
+		return msw.FormatAmount(amount, 18)
 	case UnitWEI:
-		return amountStr
+		return amount.String()
 	}
 
 	return errIllegalUnit.Error()
@@ -0,0 +1,200 @@
+package cli
+
+import (
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/spf13/cobra"
+)
+
+func (cli *CLI) buildVerifyCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:                   "verify <signTxFilePath> [--tx txFilePath] [-u NEW|WEI] [--qr] [--qr-png prefix]",
+		Short:                 "Verify the signed transaction or signed bundle in the file without broadcasting",
+		Args:                  cobra.ExactArgs(1),
+		DisableFlagsInUseLine: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			unit, _ := cmd.Flags().GetString("unit")
+			if !stringInSlice(unit, UnitList) {
+				fmt.Fprint(os.Stderr, cmd.UsageString())
+				return newErrorf(ErrCodeInvalidArgument, "Unit(%s) for amount error. Available unit: %s.", unit, strings.Join(UnitList, ","))
+			}
+
+			signed, _, err := readSignedFile(args[0])
+			if err != nil {
+				return err
+			}
+
+			var trans []*Transaction
+			if cmd.Flags().Changed("tx") {
+				txFile, _ := cmd.Flags().GetString("tx")
+				if trans, err = readTxFile(txFile); err != nil {
+					return newErrorf(ErrCodeFile, "Error apply tx file(%s): %v", txFile, err)
+				}
+				if len(trans) != len(signed) {
+					return newErrorf(ErrCodeVerifyFailed, "Error: %d signed transactions, but %d transactions in %s", len(signed), len(trans), txFile)
+				}
+				txFileHash, err := fileHash(txFile)
+				if err != nil {
+					return newErrorf(ErrCodeFile, "Error apply tx file(%s): %v", txFile, err)
+				}
+				for i, st := range signed {
+					if !st.legacy && st.TxFileHash == (common.Hash{}) {
+						return newErrorf(ErrCodeVerifyFailed, "Error: signed transaction %d/%d has no transaction file hash recorded", i+1, len(signed))
+					}
+					if !st.legacy && st.TxFileHash != txFileHash {
+						return newErrorf(ErrCodeVerifyFailed, "Error: signed transaction %d/%d is signed from file hash %s, but %s has hash %s", i+1, len(signed), st.TxFileHash.String(), txFile, txFileHash.String())
+					}
+				}
+			}
+
+			var (
+				outputs []*verifyOutput
+				failed  int
+			)
+			for i, st := range signed {
+				output, err := decodeSignedTx(st)
+				if err != nil {
+					return newErrorf(errorCode(err), "Error: signed transaction %d/%d: %w", i+1, len(signed), err)
+				}
+				if trans != nil {
+					output.Mismatches = output.compare(trans[i])
+					if len(output.Mismatches) > 0 {
+						failed++
+					}
+				}
+				fmt.Printf("Signed transaction %d/%d details are as follows:\n", i+1, len(signed))
+				st.print()
+				output.print(unit, trans != nil)
+				outputs = append(outputs, output)
+			}
+
+			if cli.isJSON() {
+				cli.printJSON(struct {
+					Transactions []*verifyOutput `json:"transactions"`
+				}{outputs})
+			}
+
+			if failed > 0 {
+				return newErrorf(ErrCodeVerifyFailed, "Error: %d signed transactions do not match the transaction file", failed)
+			}
+			return cli.exportQR(cmd, args[0])
+		},
+	}
+
+	cmd.Flags().String("tx", "", "the unsigned transaction or bundle `file` to check the signed transactions against")
+	cmd.Flags().StringP("unit", "u", UnitETH, fmt.Sprintf("unit for the value. Available unit: %s", strings.Join(UnitList, ",")))
+	addQRFlags(cmd.Flags())
+
+	return cmd
+}
+
+// readTxFile reads the unsigned transaction or bundle file
+func readTxFile(path string) ([]*Transaction, error) {
+	bundle, err := readBundleFile(path)
+	if err == nil {
+		return bundle.Transactions, nil
+	} else if err != errNotBundle {
+		return nil, err
+	}
+
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	t := new(Transaction)
+	if err := t.UnmarshalJSON(b); err != nil {
+		return nil, err
+	}
+	return []*Transaction{t}, nil
+}
+
+// verifyOutput is the signed transaction decoded
+type verifyOutput struct {
+	Hash       common.Hash     `json:"hash"`
+	From       common.Address  `json:"from"`
+	To         *common.Address `json:"to"`
+	Value      *big.Int        `json:"value"`
+	Nonce      uint64          `json:"nonce"`
+	GasPrice   *big.Int        `json:"gasPrice"`
+	GasLimit   uint64          `json:"gasLimit"`
+	ChainID    *big.Int        `json:"chainID"`
+	Data       hexutil.Bytes   `json:"data"`
+	Mismatches []string        `json:"mismatches,omitempty"`
+}
+
+// decodeSignedTx decodes the signed transaction and recovers the sender
+// with the EIP-155 signer of the chain ID in the signature
+func decodeSignedTx(st *SignedTx) (*verifyOutput, error) {
+	tx, from, err := st.decode()
+	if err != nil {
+		return nil, err
+	}
+
+	return &verifyOutput{
+		Hash:     tx.Hash(),
+		From:     from,
+		To:       tx.To(),
+		Value:    tx.Value(),
+		Nonce:    tx.Nonce(),
+		GasPrice: tx.GasPrice(),
+		GasLimit: tx.Gas(),
+		ChainID:  tx.ChainId(),
+		Data:     tx.Data(),
+	}, nil
+}
+
+// compare returns the fields of the signed transaction not the same as t
+func (v *verifyOutput) compare(t *Transaction) []string {
+	var mismatches []string
+	check := func(name string, ok bool, signed, want interface{}) {
+		if !ok {
+			mismatches = append(mismatches, fmt.Sprintf("%s: signed %v, want %v", name, signed, want))
+		}
+	}
+
+	check("from", v.From == t.From, v.From.String(), t.From.String())
+	check("to", v.To != nil && *v.To == t.To, v.To, t.To.String())
+	check("value", t.Value != nil && v.Value.Cmp(t.Value) == 0, v.Value, t.Value)
+	check("nonce", v.Nonce == t.Nonce, v.Nonce, t.Nonce)
+	check("gasPrice", t.GasPrice != nil && v.GasPrice.Cmp(t.GasPrice) == 0, v.GasPrice, t.GasPrice)
+	check("gasLimit", v.GasLimit == t.GasLimit, v.GasLimit, t.GasLimit)
+	check("chainID", t.NetworkID != nil && v.ChainID.Cmp(t.NetworkID) == 0, v.ChainID, t.NetworkID)
+	check("data", string(v.Data) == string(t.Data), v.Data.String(), hexutil.Bytes(t.Data).String())
+
+	return mismatches
+}
+
+func (v *verifyOutput) print(unit string, compared bool) {
+	fmt.Println("\tHash: ", v.Hash.String())
+	fmt.Println("\tFrom: ", v.From.String())
+	if v.To != nil {
+		fmt.Println("\tTo: ", v.To.String())
+	} else {
+		fmt.Println("\tTo: (contract creation)")
+	}
+	fmt.Println("\tValue: ", getWeiAmountTextUnitByUnit(v.Value, unit))
+	fmt.Println("\tNonce: ", v.Nonce)
+	fmt.Println("\tGasPrice: ", v.GasPrice.String())
+	fmt.Println("\tGasLimit: ", v.GasLimit)
+	fmt.Println("\tChainID: ", v.ChainID.String())
+	fmt.Printf("\tData: %s\n", v.Data.String())
+	showDataAuto(v.Data, "\t\t", unit)
+
+	if !compared {
+		return
+	}
+	if len(v.Mismatches) == 0 {
+		fmt.Println("\tMatches the transaction file")
+		return
+	}
+	fmt.Println("\tDoes NOT match the transaction file:")
+	for _, m := range v.Mismatches {
+		fmt.Printf("\t\t%s\n", m)
+	}
+}
@@ -0,0 +1,143 @@
+package cli
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestVerify(t *testing.T) {
+	dir, err := ioutil.TempDir("", "verify")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	walletPath := filepath.Join(dir, "wallet")
+	account, err := keystore.NewKeyStore(walletPath, keystore.LightScryptN, keystore.LightScryptP).NewAccount("password")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cli := NewCLI()
+	txPath := filepath.Join(dir, "confirm.tx")
+	cli.TestCommand("build confirm 3 --offline --nonce 7 --gasLimit 100000 --chainID 1007 -w " + walletPath + " " +
+		"-a 0xf09E6759c2588eE8435902d16350E321CBD27af3 -f " + account.Address.String() + " --out " + txPath)
+
+	trans, err := readTxFile(txPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cli.walletPath = walletPath
+	cli.tran = trans[0]
+	cli.tran.Password = "password"
+	signPath := txPath + ".sign"
+	txFileHash, err := fileHash(txPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := cli.signTxAndSave(signPath, txFileHash, false); err != nil {
+		t.Fatal(err)
+	}
+
+	signed, bundle, err := readSignedFile(signPath)
+	if err != nil || bundle {
+		t.Fatalf("read signed file got bundle %v, error %v", bundle, err)
+	}
+	if signed[0].TxFileHash != txFileHash || signed[0].Intent == nil || signed[0].Intent.Action != IntentConfirm {
+		t.Fatalf("got signed transaction %+v", signed[0])
+	}
+	output, err := decodeSignedTx(signed[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if output.From != account.Address || output.Nonce != 7 || output.ChainID.Int64() != 1007 {
+		t.Fatalf("got signed transaction %+v", output)
+	}
+	if m := output.compare(trans[0]); len(m) != 0 {
+		t.Fatalf("got mismatches %v", m)
+	}
+
+	verify := func() error {
+		cli := NewCLI()
+		cli.rootCmd.SetArgs([]string{"verify", signPath, "--tx", txPath})
+		return cli.rootCmd.Execute()
+	}
+	if err := verify(); err != nil {
+		t.Fatalf("verify got %v", err)
+	}
+
+	// the JSON signed file without hash is not taken as the legacy one
+	signJSON, err := ioutil.ReadFile(signPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var fields map[string]interface{}
+	if err := json.Unmarshal(signJSON, &fields); err != nil {
+		t.Fatal(err)
+	}
+	delete(fields, "hash")
+	if err := saveJSONToFile(fields, signPath); err != nil {
+		t.Fatal(err)
+	}
+	if err := verify(); errorCode(err) != ErrCodeVerifyFailed {
+		t.Fatalf("verify without hash got %v", err)
+	}
+	broadcast := NewCLI()
+	broadcast.rootCmd.SetArgs([]string{"broadcast", signPath})
+	if err := broadcast.rootCmd.Execute(); errorCode(err) != ErrCodeVerifyFailed {
+		t.Fatalf("broadcast without hash got %v", err)
+	}
+	// nor the one without the transaction file hash
+	if err := json.Unmarshal(signJSON, &fields); err != nil {
+		t.Fatal(err)
+	}
+	delete(fields, "txFileHash")
+	if err := saveJSONToFile(fields, signPath); err != nil {
+		t.Fatal(err)
+	}
+	if err := verify(); errorCode(err) != ErrCodeVerifyFailed {
+		t.Fatalf("verify without transaction file hash got %v", err)
+	}
+	if err := ioutil.WriteFile(signPath, signJSON, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// the transaction file is not the one signed from, even if the same
+	// transaction
+	b, err := ioutil.ReadFile(txPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(txPath, append(b, '\n'), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := verify(); errorCode(err) != ErrCodeVerifyFailed {
+		t.Fatalf("verify against other transaction file got %v", err)
+	}
+
+	// the legacy hex is decoded without cross-check
+	if err := saveStringToFile(signed[0].Raw, signPath); err != nil {
+		t.Fatal(err)
+	}
+	legacy, _, err := readSignedFile(signPath)
+	if err != nil || !legacy[0].legacy || legacy[0].Raw != signed[0].Raw {
+		t.Fatalf("read legacy signed file got %+v, error %v", legacy, err)
+	}
+
+	signed[0].Signer = common.HexToAddress("0x9B3deA9C636BA262f870f98a1c64d444BF0f6544")
+	if _, err := decodeSignedTx(signed[0]); errorCode(err) != ErrCodeVerifyFailed {
+		t.Fatalf("decode signed transaction with wrong signer got %v", err)
+	}
+
+	trans[0].Nonce = 8
+	trans[0].Data[len(trans[0].Data)-1] ^= 1
+	if m := output.compare(trans[0]); len(m) != 2 {
+		t.Fatalf("got mismatches %v, want nonce and data", m)
+	}
+}
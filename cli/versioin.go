@@ -8,8 +8,9 @@ func (cli *CLI) buildVersionCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "version",
 		Short: "Get version of " + cli.Name + " CLI",
-		Run: func(cmd *cobra.Command, args []string) {
+		RunE: func(cmd *cobra.Command, args []string) error {
 			showSuccess(cli.version)
+			return nil
 		},
 	}
 
@@ -0,0 +1,110 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/spf13/cobra"
+)
+
+func (cli *CLI) buildWatchCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:                   "watch [--start block] [--interval duration] [-u NEW|WEI]",
+		Short:                 "Watch the new events of contract wallet",
+		Long:                  fmt.Sprintf("Watch and show the new events (%s) of contract wallet, use subscription for websocket or ipc rpcURL and poll for http rpcURL", strings.Join(watchEventList, ",")),
+		Args:                  cobra.MinimumNArgs(0),
+		DisableFlagsInUseLine: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			unit, _ := cmd.Flags().GetString("unit")
+			if unit != "" && !stringInSlice(unit, UnitList) {
+				fmt.Fprint(os.Stderr, cmd.UsageString())
+				return newErrorf(ErrCodeInvalidArgument, "Unit(%s) for invalid. Available unit: %s.", unit, strings.Join(UnitList, ","))
+			}
+
+			interval, _ := cmd.Flags().GetDuration("interval")
+			if interval <= 0 {
+				interval = defaultWatchPollInterval
+			}
+
+			if _, err := cli.GetSimpleRegistry(); err != nil {
+				fmt.Fprint(os.Stderr, cmd.UsageString())
+				return newErrorf(ErrCodeRPC, "GetSimpleRegistry Error: %v", err)
+			}
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			sigs := make(chan os.Signal, 1)
+			signal.Notify(sigs, syscall.SIGINT, syscall.SIGTERM)
+			defer signal.Stop(sigs)
+			go func() {
+				select {
+				case <-sigs:
+					cancel()
+				case <-ctx.Done():
+				}
+			}()
+
+			var start uint64
+			if cmd.Flags().Changed("start") {
+				start, _ = cmd.Flags().GetUint64("start")
+			} else {
+				header, err := cli.client.HeaderByNumber(ctx, nil)
+				if err != nil {
+					return newErrorf(ErrCodeRPC, "Get latest block error: %v", err)
+				}
+				start = header.Number.Uint64() + 1
+			}
+
+			fmt.Printf("Watching events of contract address(%s) from block %d...\n", cli.contractAddress, start)
+			w := newEventWatcher(start, func(e *walletEvent) {
+				cli.showWatchEvent(ctx, e, unit)
+			})
+			if err := cli.watchEvents(ctx, w, interval); err != nil && err != context.Canceled {
+				return newErrorf(ErrCodeRPC, "Error: %v", err)
+			}
+			fmt.Printf("Stop watching at block %d\n", w.next)
+			return nil
+		},
+	}
+
+	cmd.Flags().Uint64("start", 0, "the `block` number to start watching from, default is the next block")
+	cmd.Flags().Duration("interval", defaultWatchPollInterval, "the `duration` between two polls for http rpcURL")
+	cmd.Flags().StringP("unit", "u", "", fmt.Sprintf("unit for value. %s.", fmt.Sprintf("Available unit: %s", strings.Join(UnitList, ","))))
+
+	return cmd
+}
+
+func (cli *CLI) showWatchEvent(ctx context.Context, e *walletEvent, unit string) {
+	printEvents([]*walletEvent{e}, unit)
+
+	if e.Name != EventSubmission {
+		return
+	}
+
+	simpleRegistry, err := cli.GetSimpleRegistry()
+	if err != nil {
+		fmt.Println("\tGetSimpleRegistry Error: ", err)
+		return
+	}
+	t, err := simpleRegistry.Transactions(&bind.CallOpts{Context: ctx}, e.TransactionID)
+	if err != nil {
+		fmt.Printf("\tFailed to call Transactions: %v\n", err)
+		return
+	}
+	if t.Destination.String() == cli.contractAddress {
+		fmt.Printf("\tDestination Address: %s (contract itself)\n", t.Destination.String())
+	} else {
+		fmt.Println("\tDestination Address: ", t.Destination.String())
+	}
+	fmt.Println("\tValue: ", getWeiAmountTextUnitByUnit(t.Value, unit))
+	if len(t.Data) > 0 {
+		fmt.Printf("\tData: 0x%s\n", common.Bytes2Hex(t.Data))
+		showDataAuto(t.Data, "\t\t", unit)
+	}
+}
@@ -0,0 +1,95 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestWatchCanceled(t *testing.T) {
+	cli := NewCLI()
+	cli.rpcURL = "http://127.0.0.1:1"
+	cli.contractAddress = "0xf09E6759c2588eE8435902d16350E321CBD27af3"
+
+	// watch returns once ctx is done instead of reconnecting
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	w := newEventWatcher(0, func(e *walletEvent) {
+		t.Errorf("unexpected event %v", e)
+	})
+	if err := cli.watchEvents(ctx, w, time.Millisecond); err != context.Canceled {
+		t.Fatalf("got error %v, want %v", err, context.Canceled)
+	}
+}
+
+func TestEventWatcher(t *testing.T) {
+	var handled []uint
+	w := newEventWatcher(10, func(e *walletEvent) {
+		handled = append(handled, e.Index)
+	})
+
+	hash := common.HexToHash("0x01")
+	w.process(&walletEvent{BlockNumber: 9, TxHash: hash, Index: 1})  // before start
+	w.process(&walletEvent{BlockNumber: 10, TxHash: hash, Index: 2}) // new
+	w.process(&walletEvent{BlockNumber: 10, TxHash: hash, Index: 2}) // duplicate
+	w.process(&walletEvent{BlockNumber: 11, TxHash: hash, Index: 3, Removed: true})
+	w.done(11)
+	w.process(&walletEvent{BlockNumber: 11, TxHash: hash, Index: 4}) // already done
+	w.process(&walletEvent{BlockNumber: 12, TxHash: hash, Index: 5}) // new
+
+	if len(handled) != 2 || handled[0] != 2 || handled[1] != 5 {
+		t.Errorf("wrong handled events: want [2 5], got %v", handled)
+	}
+	if w.next != 12 {
+		t.Errorf("wrong next block: want 12, got %d", w.next)
+	}
+
+	// the events of the subscriptions arrive out of order across blocks
+	handled = nil
+	w.process(&walletEvent{BlockNumber: 13, TxHash: hash, Index: 7})
+	w.process(&walletEvent{BlockNumber: 12, TxHash: hash, Index: 6})
+	w.process(&walletEvent{BlockNumber: 13, TxHash: hash, Index: 7}) // duplicate
+	if len(handled) != 2 || handled[0] != 7 || handled[1] != 6 {
+		t.Errorf("wrong handled events: want [7 6], got %v", handled)
+	}
+	w.done(12)
+	if _, ok := w.seen[12]; ok || !w.seen[13][fmt.Sprintf("%s-%d", hash.String(), 7)] {
+		t.Errorf("wrong seen events after done: %v", w.seen)
+	}
+}
+
+func TestEventWatcherLateLog(t *testing.T) {
+	var handled []uint
+	w := newEventWatcher(10, func(e *walletEvent) {
+		handled = append(handled, e.Index)
+	})
+
+	hash := common.HexToHash("0x01")
+	late := &walletEvent{BlockNumber: 11, TxHash: hash, Index: 2}
+	w.process(&walletEvent{BlockNumber: 10, TxHash: hash, Index: 1})
+
+	// the head 13 arrives before the log of block 11 from another
+	// subscription, which is caught up by the filter
+	filter := func(from, to uint64) ([]*walletEvent, error) {
+		if from != 10 || to != 13-watchHeadLag {
+			t.Errorf("wrong catch-up range: want [10, %d], got [%d, %d]", 13-watchHeadLag, from, to)
+		}
+		return []*walletEvent{{BlockNumber: 10, TxHash: hash, Index: 1}, late}, nil
+	}
+	if err := w.catchUp(13-watchHeadLag, filter); err != nil {
+		t.Fatal(err)
+	}
+	w.process(late) // the late log itself
+	// the log of block 12 arriving after the head is still open
+	w.process(&walletEvent{BlockNumber: 12, TxHash: hash, Index: 3})
+
+	if len(handled) != 3 || handled[0] != 1 || handled[1] != 2 || handled[2] != 3 {
+		t.Errorf("wrong handled events: want [1 2 3], got %v", handled)
+	}
+	if w.next != 12 {
+		t.Errorf("wrong next block: want 12, got %d", w.next)
+	}
+}
@@ -0,0 +1,279 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/newtonproject/MultiSignatureWallet/msw"
+)
+
+const (
+	defaultWatchPollInterval = 5 * time.Second
+	maxWatchReconnectDelay   = time.Minute
+	// watchHeadLag is the number of blocks behind the new head kept open
+	// for the events of the subscriptions still on the way
+	watchHeadLag = 2
+)
+
+// watchEventList is the events followed by watch
+var watchEventList = []string{
+	EventSubmission,
+	EventConfirmation,
+	EventExecution,
+	EventExecutionFailure,
+	EventDeposit,
+}
+
+// eventWatcher keeps the position of watch, so it can resume after reconnect
+type eventWatcher struct {
+	next uint64 // the first block not processed completely
+	// seen is the events processed from block next by block, the events of
+	// the subscriptions may arrive out of order across blocks
+	seen   map[uint64]map[string]bool
+	handle func(e *walletEvent)
+}
+
+func newEventWatcher(next uint64, handle func(e *walletEvent)) *eventWatcher {
+	return &eventWatcher{
+		next:   next,
+		seen:   make(map[uint64]map[string]bool),
+		handle: handle,
+	}
+}
+
+// process handles the event once by its transaction hash and log index,
+// events before next are ignored
+func (w *eventWatcher) process(e *walletEvent) {
+	if e == nil || e.Removed || e.BlockNumber < w.next {
+		return
+	}
+
+	seen, ok := w.seen[e.BlockNumber]
+	if !ok {
+		seen = make(map[string]bool)
+		w.seen[e.BlockNumber] = seen
+	}
+	key := fmt.Sprintf("%s-%d", e.TxHash.String(), e.Index)
+	if seen[key] {
+		return
+	}
+	seen[key] = true
+
+	w.handle(e)
+}
+
+// done marks all blocks up to number as processed
+func (w *eventWatcher) done(number uint64) {
+	if number+1 > w.next {
+		w.next = number + 1
+	}
+	for block := range w.seen {
+		if block < w.next {
+			delete(w.seen, block)
+		}
+	}
+}
+
+// catchUp processes the events from block w.next to block to got by filter,
+// and marks the blocks processed
+func (w *eventWatcher) catchUp(to uint64, filter func(from, to uint64) ([]*walletEvent, error)) error {
+	if to < w.next {
+		return nil
+	}
+	events, err := filter(w.next, to)
+	if err != nil {
+		return err
+	}
+	for _, e := range events {
+		w.process(e)
+	}
+	w.done(to)
+	return nil
+}
+
+func isSubscriptionURL(rawurl string) bool {
+	return !(strings.HasPrefix(rawurl, "http://") || strings.HasPrefix(rawurl, "https://"))
+}
+
+func (cli *CLI) resetClient() {
+	if cli.client != nil {
+		cli.client.Close()
+	}
+	cli.client = nil
+	cli.rpcClient = nil
+	cli.simpleRegistry = nil
+}
+
+// watchEvents follows the wallet events from block w.next until ctx is done.
+// It reconnects and resumes from the last processed block if RPC fails.
+func (cli *CLI) watchEvents(ctx context.Context, w *eventWatcher, pollInterval time.Duration) error {
+	delay := time.Second
+	for {
+		var err error
+		if isSubscriptionURL(cli.rpcURL) {
+			err = cli.watchEventsBySubscription(ctx, w)
+		} else {
+			err = cli.watchEventsByPolling(ctx, w, pollInterval)
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		fmt.Printf("Watch interrupted(%v), reconnect in %v and resume from block %d\n", err, delay, w.next)
+		cli.resetClient()
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(delay):
+		}
+		if delay *= 2; delay > maxWatchReconnectDelay {
+			delay = maxWatchReconnectDelay
+		}
+	}
+}
+
+// catchUpEvents processes the events from block w.next to the latest block
+func (cli *CLI) catchUpEvents(ctx context.Context, w *eventWatcher) error {
+	if _, err := cli.GetSimpleRegistry(); err != nil {
+		return err
+	}
+
+	header, err := cli.client.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return err
+	}
+	return w.catchUp(header.Number.Uint64(), cli.watchFilter(ctx))
+}
+
+// watchFilter returns the filter of the events followed by watch
+func (cli *CLI) watchFilter(ctx context.Context) func(from, to uint64) ([]*walletEvent, error) {
+	return func(from, to uint64) ([]*walletEvent, error) {
+		return cli.filterEvents(ctx, from, to, defaultEventsChunkSize, &eventFilter{Names: watchEventList})
+	}
+}
+
+// watchEventsByPolling uses eth_getLogs to get new events for HTTP rpcURL
+func (cli *CLI) watchEventsByPolling(ctx context.Context, w *eventWatcher, pollInterval time.Duration) error {
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := cli.catchUpEvents(ctx, w); err != nil {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// watchEventsBySubscription uses the Watch* bindings to stream new events.
+// On each new head, the blocks up to watchHeadLag behind it are caught up
+// with eth_getLogs before marked processed, so the events arriving late
+// are not lost.
+func (cli *CLI) watchEventsBySubscription(ctx context.Context, w *eventWatcher) error {
+	simpleRegistry, err := cli.GetSimpleRegistry()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	opts := &bind.WatchOpts{Context: ctx}
+
+	submissionCh := make(chan *msw.MultiSigWalletWithDailyLimitSubmission)
+	submissionSub, err := simpleRegistry.WatchSubmission(opts, submissionCh, nil)
+	if err != nil {
+		return err
+	}
+	defer submissionSub.Unsubscribe()
+
+	confirmationCh := make(chan *msw.MultiSigWalletWithDailyLimitConfirmation)
+	confirmationSub, err := simpleRegistry.WatchConfirmation(opts, confirmationCh, nil, nil)
+	if err != nil {
+		return err
+	}
+	defer confirmationSub.Unsubscribe()
+
+	executionCh := make(chan *msw.MultiSigWalletWithDailyLimitExecution)
+	executionSub, err := simpleRegistry.WatchExecution(opts, executionCh, nil)
+	if err != nil {
+		return err
+	}
+	defer executionSub.Unsubscribe()
+
+	executionFailureCh := make(chan *msw.MultiSigWalletWithDailyLimitExecutionFailure)
+	executionFailureSub, err := simpleRegistry.WatchExecutionFailure(opts, executionFailureCh, nil)
+	if err != nil {
+		return err
+	}
+	defer executionFailureSub.Unsubscribe()
+
+	depositCh := make(chan *msw.MultiSigWalletWithDailyLimitDeposit)
+	depositSub, err := simpleRegistry.WatchDeposit(opts, depositCh, nil)
+	if err != nil {
+		return err
+	}
+	defer depositSub.Unsubscribe()
+
+	headCh := make(chan *types.Header)
+	headSub, err := cli.client.SubscribeNewHead(ctx, headCh)
+	if err != nil {
+		return err
+	}
+	defer headSub.Unsubscribe()
+
+	// subscribe first, so nothing is lost between catching up and streaming
+	if err := cli.catchUpEvents(ctx, w); err != nil {
+		return err
+	}
+
+	for {
+		var ev interface{}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case err := <-submissionSub.Err():
+			return err
+		case err := <-confirmationSub.Err():
+			return err
+		case err := <-executionSub.Err():
+			return err
+		case err := <-executionFailureSub.Err():
+			return err
+		case err := <-depositSub.Err():
+			return err
+		case err := <-headSub.Err():
+			return err
+		case header := <-headCh:
+			if head := header.Number.Uint64(); head >= watchHeadLag {
+				if err := w.catchUp(head-watchHeadLag, cli.watchFilter(ctx)); err != nil {
+					return err
+				}
+			}
+			continue
+		case ev = <-submissionCh:
+		case ev = <-confirmationCh:
+		case ev = <-executionCh:
+		case ev = <-executionFailureCh:
+		case ev = <-depositCh:
+		}
+
+		e := toWalletEvent(ev)
+		if e == nil || e.Removed {
+			continue
+		}
+		if err := cli.fillEventsBlockTime(ctx, []*walletEvent{e}); err != nil {
+			return err
+		}
+		w.process(e)
+	}
+}
@@ -1,9 +1,13 @@
 package main
 
 import (
+	"os"
+
 	"github.com/newtonproject/MultiSignatureWallet/cli"
 )
 
 func main() {
-	cli.NewCLI().Execute()
+	if err := cli.NewCLI().Execute(); err != nil {
+		os.Exit(cli.ExitCode(err))
+	}
 }
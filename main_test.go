@@ -5,6 +5,7 @@ import (
 	"io/ioutil"
 	"log"
 	"os"
+	"path/filepath"
 	"strconv"
 	"strings"
 	"testing"
@@ -52,14 +53,17 @@ func expectOutput(t *testing.T, cli *cli.CLI, want string, command string) {
 	}
 }
 
-func newCLI() (*cli.CLI, func()) {
-	_, cleanupFunc := getTempFile()
+// newCLI returns the CLI with the wallet path in a temp dir, so the test
+// accounts are not left in ./wallet
+func newCLI() (*cli.CLI, string, func()) {
+	file, cleanupFunc := getTempFile()
+	walletPath := filepath.Join(filepath.Dir(file), "wallet")
 
 	dpos := cli.NewCLI()
 	dpos.TestCommand("version")
 	run(dpos, fmt.Sprintf("version"))
 
-	return dpos, cleanupFunc
+	return dpos, walletPath, cleanupFunc
 }
 
 func getBalance(cli *cli.CLI, account string) float64 {
@@ -76,23 +80,25 @@ func getBalance(cli *cli.CLI, account string) float64 {
 
 // Create new funded test account
 func TestTx(t *testing.T) {
-	cli, _ := newCLI()
+	cli, walletPath, cleanup := newCLI()
+	defer cleanup()
 	cli.SetPassword("test")
-	address := run(cli, "account new") // no password
+	address := run(cli, "account new -w "+walletPath) // no password
 
-	run(cli, "deploy -o 0xdDeB86Dd09F16316B67322199E288d7AF35E0806,0x9B3deA9C636BA262f870f98a1c64d444BF0f6544,0xc8B5c4cB6DB7254d082b24A96627F143E8A80c31 -r 2 -l 1024 --from "+address)
+	run(cli, "deploy -o 0xdDeB86Dd09F16316B67322199E288d7AF35E0806,0x9B3deA9C636BA262f870f98a1c64d444BF0f6544,0xc8B5c4cB6DB7254d082b24A96627F143E8A80c31 -r 2 -l 1024 -w "+walletPath+" --from "+address)
 	run(cli, "info")
 
-	run(cli, "tx submit 1 --to 0xdDeB86Dd09F16316B67322199E288d7AF35E0806")
+	run(cli, "tx submit 1 -w "+walletPath+" --to 0xdDeB86Dd09F16316B67322199E288d7AF35E0806")
 
 }
 
 func TestAll(t *testing.T) {
-	cli, _ := newCLI()
+	cli, walletPath, cleanup := newCLI()
+	defer cleanup()
 
 	cli.SetPassword("test")
 
-	address := run(cli, "account new")
+	address := run(cli, "account new -w "+walletPath)
 
 	fmt.Println(address)
 
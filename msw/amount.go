@@ -0,0 +1,54 @@
+package msw
+
+import (
+	"math/big"
+	"strings"
+)
+
+var big10 = big.NewInt(10)
+
+// ParseAmount converts the decimal text amount to the integer in the
+// smallest unit with decimals, such as "1.5" with 18 decimals is
+// 1500000000000000000
+func ParseAmount(amount string, decimals int) (*big.Int, error) {
+	index := strings.IndexByte(amount, '.')
+	if index <= 0 {
+		value, ok := new(big.Int).SetString(amount, 10)
+		if !ok {
+			return nil, ErrInvalidAmount
+		}
+		return value.Mul(value, new(big.Int).Exp(big10, big.NewInt(int64(decimals)), nil)), nil
+	}
+
+	fraction := amount[index+1:]
+	if len(fraction) > decimals {
+		return nil, ErrInvalidAmount
+	}
+	value, ok := new(big.Int).SetString(amount[:index]+fraction+strings.Repeat("0", decimals-len(fraction)), 10)
+	if !ok {
+		return nil, ErrInvalidAmount
+	}
+
+	return value, nil
+}
+
+// FormatAmount converts the integer amount in the smallest unit with
+// decimals to the decimal text, the trailing zeros of fraction are trimmed
+func FormatAmount(amount *big.Int, decimals int) string {
+	if amount == nil {
+		return "0"
+	}
+	text := amount.String()
+	if decimals <= 0 {
+		return text
+	}
+	if len(text) <= decimals {
+		text = strings.Repeat("0", decimals-len(text)+1) + text
+	}
+
+	integer, fraction := text[:len(text)-decimals], strings.TrimRight(text[len(text)-decimals:], "0")
+	if fraction == "" {
+		return integer
+	}
+	return integer + "." + fraction
+}
@@ -0,0 +1,44 @@
+package msw
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestAmount(t *testing.T) {
+	tests := []struct {
+		text     string
+		decimals int
+		value    string
+		format   string
+	}{
+		{"1", 18, "1000000000000000000", "1"},
+		{"1.5", 18, "1500000000000000000", "1.5"},
+		{"0.000000000000000001", 18, "1", "0.000000000000000001"},
+		{"12.30", 2, "1230", "12.3"},
+		{"7", 0, "7", "7"},
+		{"0", 18, "0", "0"},
+	}
+
+	for _, test := range tests {
+		value, err := ParseAmount(test.text, test.decimals)
+		if err != nil {
+			t.Fatalf("ParseAmount(%s, %d): %v", test.text, test.decimals, err)
+		}
+		if value.String() != test.value {
+			t.Errorf("ParseAmount(%s, %d) got %s, want %s", test.text, test.decimals, value, test.value)
+		}
+		if format := FormatAmount(value, test.decimals); format != test.format {
+			t.Errorf("FormatAmount(%s, %d) got %s, want %s", value, test.decimals, format, test.format)
+		}
+	}
+
+	for _, text := range []string{"", "abc", "1.123", ".5"} {
+		if _, err := ParseAmount(text, 2); err != ErrInvalidAmount {
+			t.Errorf("ParseAmount(%s) got %v", text, err)
+		}
+	}
+	if FormatAmount(nil, 18) != "0" || FormatAmount(big.NewInt(5), 1) != "0.5" {
+		t.Error("FormatAmount error")
+	}
+}
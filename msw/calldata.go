@@ -0,0 +1,67 @@
+package msw
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+)
+
+// ErrUnknownMethod is returned by DecodeCall if data does not call a method
+// of the wallet
+var ErrUnknownMethod = errors.New("unknown method")
+
+var walletABI = mustParseABI(MultiSigWalletWithDailyLimitABI)
+
+func mustParseABI(definition string) abi.ABI {
+	parsed, err := abi.JSON(strings.NewReader(definition))
+	if err != nil {
+		panic(err)
+	}
+	return parsed
+}
+
+// Pack returns the data to call the wallet method name with args
+func Pack(name string, args ...interface{}) ([]byte, error) {
+	return walletABI.Pack(name, args...)
+}
+
+// Arg is a decoded argument of the call
+type Arg struct {
+	Name  string
+	Type  abi.Type
+	Value interface{}
+}
+
+// Call is the decoded data calling a method of the wallet
+type Call struct {
+	Method    string
+	Signature string
+	Args      []Arg
+}
+
+// DecodeCall decodes data calling a method of the wallet, such as the data
+// of a transaction sent to the wallet or a submitted transaction to itself
+func DecodeCall(data []byte) (*Call, error) {
+	if len(data) < 4 {
+		return nil, ErrUnknownMethod
+	}
+	method, err := walletABI.MethodById(data[:4])
+	if err != nil || method == nil {
+		return nil, ErrUnknownMethod
+	}
+	values, err := method.Inputs.UnpackValues(data[4:])
+	if err != nil {
+		return nil, err
+	}
+
+	call := &Call{Method: method.Name, Signature: method.String()}
+	for i, input := range method.Inputs.NonIndexed() {
+		if i >= len(values) {
+			break
+		}
+		call.Args = append(call.Args, Arg{Name: input.Name, Type: input.Type, Value: values[i]})
+	}
+
+	return call, nil
+}
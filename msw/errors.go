@@ -0,0 +1,31 @@
+package msw
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Errors of the checks before a transaction is sent to the wallet
+var (
+	ErrNotOwner         = errors.New("not the owner")
+	ErrTxNotFound       = errors.New("transaction ID not found")
+	ErrAlreadyExecuted  = errors.New("transaction ID has been executed")
+	ErrAlreadyConfirmed = errors.New("transaction ID has been confirmed")
+	ErrNotConfirmed     = errors.New("transaction ID is not confirmed")
+	ErrInvalidAmount    = errors.New("invalid amount")
+	ErrNoChainID        = errors.New("chain ID is unknown")
+)
+
+// TxError is the error of sending the transaction of method to the wallet
+type TxError struct {
+	Method string
+	Err    error
+}
+
+func (e *TxError) Error() string {
+	return fmt.Sprintf("%s error: %v", e.Method, e.Err)
+}
+
+func (e *TxError) Unwrap() error {
+	return e.Err
+}
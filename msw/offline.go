@@ -0,0 +1,69 @@
+package msw
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// OfflineTx is an unsigned transaction calling the wallet, with all the
+// fields needed to sign it on an offline computer
+type OfflineTx struct {
+	From     common.Address
+	To       common.Address
+	Value    *big.Int
+	Data     []byte
+	Nonce    uint64
+	GasPrice *big.Int
+	GasLimit uint64
+	ChainID  *big.Int
+}
+
+// Transaction returns the unsigned transaction of t
+func (t *OfflineTx) Transaction() *types.Transaction {
+	return types.NewTransaction(t.Nonce, t.To, t.Value, t.GasLimit, t.GasPrice, t.Data)
+}
+
+// BuildOffline reads the nonce of from, the gas price, the gas limit of data
+// and the chain ID, and returns the transaction from from calling the wallet
+// with data. Use Pack to get the data.
+func (w *Wallet) BuildOffline(ctx context.Context, from common.Address, data []byte) (*OfflineTx, error) {
+	t := &OfflineTx{
+		From:  from,
+		To:    w.address,
+		Value: new(big.Int),
+		Data:  data,
+	}
+
+	var err error
+	if t.ChainID, err = w.ChainID(ctx); err != nil {
+		return nil, err
+	}
+	if t.Nonce, err = w.backend.PendingNonceAt(ctx, from); err != nil {
+		return nil, fmt.Errorf("PendingNonceAt error: %v", err)
+	}
+	if t.GasPrice, err = w.backend.SuggestGasPrice(ctx); err != nil {
+		return nil, fmt.Errorf("SuggestGasPrice error: %v", err)
+	}
+	msg := ethereum.CallMsg{From: from, To: &w.address, Value: t.Value, Data: data}
+	if t.GasLimit, err = w.backend.EstimateGas(ctx, msg); err != nil {
+		return nil, fmt.Errorf("EstimateGas error: %v", err)
+	}
+
+	return t, nil
+}
+
+// ChainID returns the chain ID of the wallet
+func (w *Wallet) ChainID(ctx context.Context) (*big.Int, error) {
+	if w.chainID != nil {
+		return new(big.Int).Set(w.chainID), nil
+	}
+	if reader, ok := w.backend.(networkIDReader); ok {
+		return reader.NetworkID(ctx)
+	}
+	return nil, ErrNoChainID
+}
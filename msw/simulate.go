@@ -0,0 +1,154 @@
+package msw
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// revertSelector is the selector of Error(string), the data of revert with
+// a reason
+var revertSelector = []byte{0x08, 0xc3, 0x79, 0xa0}
+
+// CallResult is the result of simulating a call
+type CallResult struct {
+	Gas      uint64 `json:"gas,omitempty"`
+	Return   []byte `json:"-"`
+	Reverted bool   `json:"reverted"`
+	Reason   string `json:"reason,omitempty"`
+}
+
+// Simulation is the dry run of a transaction calling the wallet, and of the
+// inner call to the destination if the transaction submits, confirms or
+// executes a transaction ID
+type Simulation struct {
+	Method string      `json:"method"`
+	Outer  *CallResult `json:"outer"`
+
+	// the inner call, nil if the transaction calls no destination
+	ID          *big.Int        `json:"id,omitempty"`
+	Destination *common.Address `json:"destination,omitempty"`
+	Value       *big.Int        `json:"value,omitempty"`
+	Data        hexutil.Bytes   `json:"data,omitempty"`
+	Inner       *CallResult     `json:"inner,omitempty"`
+	// Executes reports whether the transaction executes the inner call,
+	// that is the transaction ID is confirmed after it, or it pays no more
+	// than the remaining daily limit without data
+	Executes bool `json:"executes"`
+}
+
+// Reverted reports whether the transaction would revert, or the inner call
+// executed by it would fail. The inner call not executed by the transaction
+// only fails when it is executed later, and the wallet emits
+// ExecutionFailure instead of reverting.
+func (s *Simulation) Reverted() bool {
+	return s.Outer.Reverted || (s.Executes && s.Inner != nil && s.Inner.Reverted)
+}
+
+// Simulate dry runs the transaction from from calling the wallet with data,
+// the outer call with eth_estimateGas, and the inner call with eth_call from
+// the wallet. The inner call is run on the current state, not on the state
+// after the outer call.
+func (w *Wallet) Simulate(ctx context.Context, from common.Address, data []byte) (*Simulation, error) {
+	call, err := DecodeCall(data)
+	if err != nil {
+		return nil, err
+	}
+	s := &Simulation{
+		Method: call.Method,
+		Outer:  w.simulate(ctx, ethereum.CallMsg{From: from, To: &w.address, Value: new(big.Int), Data: data}),
+	}
+
+	opts := callOpts(ctx)
+	required, err := w.contract.Required(opts)
+	if err != nil {
+		return nil, fmt.Errorf("Required error: %v", err)
+	}
+	var confirmations int64
+	switch call.Method {
+	case "submitTransaction":
+		destination := call.Args[0].Value.(common.Address)
+		s.Destination = &destination
+		s.Value = call.Args[1].Value.(*big.Int)
+		s.Data = call.Args[2].Value.([]byte)
+		confirmations = 1
+	case "confirmTransaction", "executeTransaction":
+		s.ID = call.Args[0].Value.(*big.Int)
+		status, err := w.Status(ctx, s.ID)
+		if err != nil {
+			return nil, err
+		}
+		s.Destination, s.Value, s.Data = &status.Destination, status.Value, status.Data
+		confirmations = int64(len(status.Confirmations))
+		if call.Method == "confirmTransaction" {
+			confirmations++
+		}
+	default:
+		return s, nil
+	}
+
+	remaining, err := w.contract.CalcMaxWithdraw(opts)
+	if err != nil {
+		return nil, fmt.Errorf("CalcMaxWithdraw error: %v", err)
+	}
+	s.Executes = big.NewInt(confirmations).Cmp(required) >= 0 || (len(s.Data) == 0 && s.Value.Cmp(remaining) <= 0)
+
+	// the balance is not checked by eth_call of some nodes
+	balance, err := w.backend.BalanceAt(ctx, w.address, nil)
+	if err != nil {
+		return nil, fmt.Errorf("BalanceAt error: %v", err)
+	}
+	if balance.Cmp(s.Value) < 0 {
+		s.Inner = &CallResult{Reverted: true, Reason: fmt.Sprintf("insufficient balance %s of wallet for value %s", balance.String(), s.Value.String())}
+		return s, nil
+	}
+	s.Inner = w.simulate(ctx, ethereum.CallMsg{From: w.address, To: s.Destination, Value: s.Value, Data: s.Data})
+
+	return s, nil
+}
+
+// simulate runs msg with eth_call and eth_estimateGas, the revert is the
+// error of either, or the revert data returned by eth_call of old nodes
+func (w *Wallet) simulate(ctx context.Context, msg ethereum.CallMsg) *CallResult {
+	r := new(CallResult)
+	ret, callErr := w.backend.CallContract(ctx, msg, nil)
+	gas, gasErr := w.backend.EstimateGas(ctx, msg)
+	r.Return, r.Gas = ret, gas
+
+	if reason, ok := revertReason(ret); ok {
+		r.Reverted, r.Reason = true, reason
+		return r
+	}
+	for _, err := range []error{callErr, gasErr} {
+		if err != nil {
+			r.Reverted, r.Reason = true, strings.TrimPrefix(err.Error(), "execution reverted: ")
+			r.Gas = 0
+			return r
+		}
+	}
+	return r
+}
+
+// revertReason decodes the reason of the revert data
+func revertReason(ret []byte) (string, bool) {
+	if len(ret) < 4 || !bytes.Equal(ret[:4], revertSelector) {
+		return "", false
+	}
+	typ, err := abi.NewType("string", nil)
+	if err != nil {
+		return "", true
+	}
+	values, err := abi.Arguments{{Type: typ}}.UnpackValues(ret[4:])
+	if err != nil || len(values) == 0 {
+		return "", true
+	}
+	reason, _ := values[0].(string)
+	return reason, true
+}
@@ -0,0 +1,90 @@
+package msw
+
+import (
+	"context"
+	"math/big"
+	"testing"
+)
+
+func TestSimulate(t *testing.T) {
+	ctx := context.Background()
+	a, b, c := newTestAccount(t), newTestAccount(t), newTestAccount(t)
+	w, backend := newTestWallet(t, a, b, c)
+
+	data, _ := Pack("submitTransaction", c.opts.From, big.NewInt(100), []byte{})
+	s, err := w.Simulate(ctx, a.opts.From, data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Reverted() || s.Executes || s.Outer.Gas == 0 || *s.Destination != c.opts.From {
+		t.Fatalf("got simulation %+v", s)
+	}
+
+	// the wallet has only 1000, the submission succeeds without executing
+	data, _ = Pack("submitTransaction", c.opts.From, big.NewInt(5000), []byte{})
+	if s, err = w.Simulate(ctx, a.opts.From, data); err != nil {
+		t.Fatal(err)
+	}
+	if s.Outer.Reverted || !s.Inner.Reverted || s.Executes || s.Reverted() {
+		t.Fatalf("got simulation %+v, outer %+v, inner %+v", s, s.Outer, s.Inner)
+	}
+
+	// not owner
+	data, _ = Pack("submitTransaction", c.opts.From, big.NewInt(100), []byte{})
+	if s, err = w.Simulate(ctx, c.opts.From, data); err != nil {
+		t.Fatal(err)
+	}
+	if !s.Outer.Reverted {
+		t.Fatalf("got outer %+v by not owner", s.Outer)
+	}
+
+	if _, err := w.Submit(ctx, a.opts, c.opts.From, big.NewInt(100), nil); err != nil {
+		t.Fatal(err)
+	}
+	backend.Commit()
+
+	data, _ = Pack("confirmTransaction", big.NewInt(0))
+	if s, err = w.Simulate(ctx, b.opts.From, data); err != nil {
+		t.Fatal(err)
+	}
+	if s.Reverted() || !s.Executes || s.ID.Int64() != 0 || s.Value.Int64() != 100 {
+		t.Fatalf("got simulation %+v", s)
+	}
+	if s, err = w.Simulate(ctx, a.opts.From, data); err != nil {
+		t.Fatal(err)
+	}
+	if !s.Outer.Reverted {
+		t.Fatalf("got outer %+v of confirming twice", s.Outer)
+	}
+
+	// the confirmation executes the call failing for the balance
+	if _, err := w.Submit(ctx, a.opts, c.opts.From, big.NewInt(5000), nil); err != nil {
+		t.Fatal(err)
+	}
+	backend.Commit()
+	data, _ = Pack("confirmTransaction", big.NewInt(1))
+	if s, err = w.Simulate(ctx, b.opts.From, data); err != nil {
+		t.Fatal(err)
+	}
+	if s.Outer.Reverted || !s.Inner.Reverted || !s.Executes || !s.Reverted() {
+		t.Fatalf("got simulation %+v, outer %+v, inner %+v", s, s.Outer, s.Inner)
+	}
+
+	if _, err := w.Simulate(ctx, a.opts.From, []byte("hello")); err == nil {
+		t.Fatal("simulate unknown data got no error")
+	}
+}
+
+func TestRevertReason(t *testing.T) {
+	// Error("not enough")
+	ret := append(append([]byte{}, revertSelector...), make([]byte, 96)...)
+	ret[4+31] = 0x20
+	ret[4+63] = 10
+	copy(ret[4+64:], "not enough")
+	if reason, ok := revertReason(ret); !ok || reason != "not enough" {
+		t.Fatalf("got reason %q %v", reason, ok)
+	}
+	if _, ok := revertReason([]byte{1, 2, 3, 4}); ok {
+		t.Fatal("got reason of no revert data")
+	}
+}
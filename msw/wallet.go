@@ -0,0 +1,350 @@
+// Package msw is the Go client of the MultiSigWalletWithDailyLimit contract.
+// The commandline in package cli is built on it.
+package msw
+
+//go:generate abigen --sol ../contract/MultiSigWalletWithDailyLimit.sol --pkg msw --out MultiSigWalletWithDailyLimit.go
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+// Backend is the blockchain the wallet reads from and sends to,
+// such as *ethclient.Client
+type Backend interface {
+	bind.ContractBackend
+	bind.DeployBackend
+	BalanceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (*big.Int, error)
+}
+
+// networkIDReader is the backend which knows its chain ID
+type networkIDReader interface {
+	NetworkID(ctx context.Context) (*big.Int, error)
+}
+
+// Wallet is the client of a deployed contract wallet. It keeps no state
+// between calls and is safe for concurrent use.
+type Wallet struct {
+	address  common.Address
+	backend  Backend
+	chainID  *big.Int
+	contract *MultiSigWalletWithDailyLimit
+}
+
+// NewWallet returns the client of the contract wallet at address. The
+// chainID is used to build offline transactions, if nil it is read from
+// the backend when needed.
+func NewWallet(address common.Address, backend Backend, chainID *big.Int) (*Wallet, error) {
+	contract, err := NewMultiSigWalletWithDailyLimit(address, backend)
+	if err != nil {
+		return nil, err
+	}
+	return &Wallet{
+		address:  address,
+		backend:  backend,
+		chainID:  chainID,
+		contract: contract,
+	}, nil
+}
+
+// Dial connects to the json rpc or ipc rawurl and returns the client of
+// the contract wallet at address
+func Dial(ctx context.Context, rawurl string, address common.Address) (*Wallet, error) {
+	client, err := ethclient.DialContext(ctx, rawurl)
+	if err != nil {
+		return nil, err
+	}
+	chainID, err := client.NetworkID(ctx)
+	if err != nil {
+		client.Close()
+		return nil, err
+	}
+	return NewWallet(address, client, chainID)
+}
+
+// Address returns the address of the contract wallet
+func (w *Wallet) Address() common.Address {
+	return w.address
+}
+
+// Contract returns the contract binding, for the methods not covered by Wallet
+func (w *Wallet) Contract() *MultiSigWalletWithDailyLimit {
+	return w.contract
+}
+
+// Info is the basic information of the contract wallet
+type Info struct {
+	Address        common.Address   `json:"contract"`
+	Balance        *big.Int         `json:"balance"`
+	Owners         []common.Address `json:"owners"`
+	Required       *big.Int         `json:"required"`
+	DailyLimit     *big.Int         `json:"dailyLimit"`
+	RemainingLimit *big.Int         `json:"remainingLimit"`
+	SpentToday     *big.Int         `json:"spentToday"`
+}
+
+// Status is the state of a transaction ID of the contract wallet
+type Status struct {
+	ID            *big.Int         `json:"id"`
+	Destination   common.Address   `json:"destination"`
+	Value         *big.Int         `json:"value"`
+	Data          []byte           `json:"data"`
+	Executed      bool             `json:"executed"`
+	Confirmed     bool             `json:"confirmed"`
+	Confirmations []common.Address `json:"confirmations"`
+	Required      *big.Int         `json:"required"`
+}
+
+func callOpts(ctx context.Context) *bind.CallOpts {
+	return &bind.CallOpts{Context: ctx}
+}
+
+// Info reads the basic information of the contract wallet
+func (w *Wallet) Info(ctx context.Context) (*Info, error) {
+	opts := callOpts(ctx)
+	info := &Info{Address: w.address}
+
+	var err error
+	if info.Balance, err = w.backend.BalanceAt(ctx, w.address, nil); err != nil {
+		return nil, fmt.Errorf("BalanceAt error: %v", err)
+	}
+	if info.Owners, err = w.contract.GetOwners(opts); err != nil {
+		return nil, fmt.Errorf("GetOwners error: %v", err)
+	}
+	if info.Required, err = w.contract.Required(opts); err != nil {
+		return nil, fmt.Errorf("Required error: %v", err)
+	}
+	if info.DailyLimit, err = w.contract.DailyLimit(opts); err != nil {
+		return nil, fmt.Errorf("DailyLimit error: %v", err)
+	}
+	if info.RemainingLimit, err = w.contract.CalcMaxWithdraw(opts); err != nil {
+		return nil, fmt.Errorf("CalcMaxWithdraw error: %v", err)
+	}
+	if info.SpentToday, err = w.contract.SpentToday(opts); err != nil {
+		return nil, fmt.Errorf("SpentToday error: %v", err)
+	}
+
+	return info, nil
+}
+
+// Owners reads the owners of the contract wallet
+func (w *Wallet) Owners(ctx context.Context) ([]common.Address, error) {
+	return w.contract.GetOwners(callOpts(ctx))
+}
+
+// IsOwner reports whether address is an owner of the contract wallet
+func (w *Wallet) IsOwner(ctx context.Context, address common.Address) (bool, error) {
+	return w.contract.IsOwner(callOpts(ctx), address)
+}
+
+// Required reads the number of required confirmations
+func (w *Wallet) Required(ctx context.Context) (*big.Int, error) {
+	return w.contract.Required(callOpts(ctx))
+}
+
+// TransactionCount reads the number of all the transaction IDs
+func (w *Wallet) TransactionCount(ctx context.Context) (*big.Int, error) {
+	return w.contract.GetTransactionCount(callOpts(ctx), true, true)
+}
+
+// Status reads the state of the transaction id, ErrTxNotFound is returned
+// if id does not exist
+func (w *Wallet) Status(ctx context.Context, id *big.Int) (*Status, error) {
+	opts := callOpts(ctx)
+	if err := w.checkExists(opts, id); err != nil {
+		return nil, err
+	}
+
+	t, err := w.contract.Transactions(opts, id)
+	if err != nil {
+		return nil, fmt.Errorf("Transactions error: %v", err)
+	}
+	status := &Status{
+		ID:          id,
+		Destination: t.Destination,
+		Value:       t.Value,
+		Data:        t.Data,
+		Executed:    t.Executed,
+	}
+	if status.Confirmations, err = w.contract.GetConfirmations(opts, id); err != nil {
+		return nil, fmt.Errorf("GetConfirmations error: %v", err)
+	}
+	if status.Required, err = w.contract.Required(opts); err != nil {
+		return nil, fmt.Errorf("Required error: %v", err)
+	}
+	if status.Confirmed, err = w.contract.IsConfirmed(opts, id); err != nil {
+		return nil, fmt.Errorf("IsConfirmed error: %v", err)
+	}
+
+	return status, nil
+}
+
+func (w *Wallet) checkOwner(opts *bind.CallOpts, address common.Address) error {
+	isOwner, err := w.contract.IsOwner(opts, address)
+	if err != nil {
+		return fmt.Errorf("IsOwner error: %v", err)
+	}
+	if !isOwner {
+		return fmt.Errorf("%w: %s", ErrNotOwner, address.String())
+	}
+	return nil
+}
+
+func (w *Wallet) checkExists(opts *bind.CallOpts, id *big.Int) error {
+	count, err := w.contract.GetTransactionCount(opts, true, true)
+	if err != nil {
+		return fmt.Errorf("GetTransactionCount error: %v", err)
+	}
+	if id.Sign() < 0 || id.Cmp(count) >= 0 {
+		return fmt.Errorf("%w: ID(%s) exceeds total number(%s) of transactions", ErrTxNotFound, id.String(), count.String())
+	}
+	return nil
+}
+
+func (w *Wallet) checkPending(opts *bind.CallOpts, id *big.Int) error {
+	if err := w.checkExists(opts, id); err != nil {
+		return err
+	}
+	t, err := w.contract.Transactions(opts, id)
+	if err != nil {
+		return fmt.Errorf("Transactions error: %v", err)
+	}
+	if t.Executed {
+		return fmt.Errorf("%w: ID(%s)", ErrAlreadyExecuted, id.String())
+	}
+	return nil
+}
+
+// transactOpts returns a copy of opts sending with ctx
+func transactOpts(ctx context.Context, opts *bind.TransactOpts) *bind.TransactOpts {
+	copied := *opts
+	copied.Context = ctx
+	return &copied
+}
+
+// Submit submits and confirms a transaction paying value to destination
+// with data, opts.From must be an owner
+func (w *Wallet) Submit(ctx context.Context, opts *bind.TransactOpts, destination common.Address, value *big.Int, data []byte) (*types.Transaction, error) {
+	if err := w.checkOwner(callOpts(ctx), opts.From); err != nil {
+		return nil, err
+	}
+
+	tx, err := w.contract.SubmitTransaction(transactOpts(ctx, opts), destination, value, data)
+	if err != nil {
+		return nil, &TxError{Method: "SubmitTransaction", Err: err}
+	}
+	return tx, nil
+}
+
+// CheckConfirm checks from is an owner who can confirm the transaction id,
+// that is id is pending, has not enough confirmations and is not
+// confirmed by from
+func (w *Wallet) CheckConfirm(ctx context.Context, from common.Address, id *big.Int) error {
+	call := callOpts(ctx)
+	if err := w.checkOwner(call, from); err != nil {
+		return err
+	}
+	if err := w.checkPending(call, id); err != nil {
+		return err
+	}
+	confirmed, err := w.contract.IsConfirmed(call, id)
+	if err != nil {
+		return fmt.Errorf("IsConfirmed error: %v", err)
+	}
+	if confirmed {
+		return fmt.Errorf("%w: ID(%s) has enough confirmations", ErrAlreadyConfirmed, id.String())
+	}
+	confirmed, err = w.contract.Confirmations(call, id, from)
+	if err != nil {
+		return fmt.Errorf("Confirmations error: %v", err)
+	}
+	if confirmed {
+		return fmt.Errorf("%w: ID(%s) by %s", ErrAlreadyConfirmed, id.String(), from.String())
+	}
+	return nil
+}
+
+// CheckRevoke checks from is an owner who has confirmed the pending
+// transaction id
+func (w *Wallet) CheckRevoke(ctx context.Context, from common.Address, id *big.Int) error {
+	call := callOpts(ctx)
+	if err := w.checkOwner(call, from); err != nil {
+		return err
+	}
+	if err := w.checkPending(call, id); err != nil {
+		return err
+	}
+	confirmed, err := w.contract.Confirmations(call, id, from)
+	if err != nil {
+		return fmt.Errorf("Confirmations error: %v", err)
+	}
+	if !confirmed {
+		return fmt.Errorf("%w: ID(%s) by %s", ErrNotConfirmed, id.String(), from.String())
+	}
+	return nil
+}
+
+// CheckExecute checks from is an owner and the transaction id is pending
+func (w *Wallet) CheckExecute(ctx context.Context, from common.Address, id *big.Int) error {
+	call := callOpts(ctx)
+	if err := w.checkOwner(call, from); err != nil {
+		return err
+	}
+	return w.checkPending(call, id)
+}
+
+// CheckOwner checks address is an owner of the contract wallet
+func (w *Wallet) CheckOwner(ctx context.Context, address common.Address) error {
+	return w.checkOwner(callOpts(ctx), address)
+}
+
+// Confirm confirms the transaction id by opts.From, it fails if id has
+// been executed, has enough confirmations or is confirmed by opts.From
+func (w *Wallet) Confirm(ctx context.Context, opts *bind.TransactOpts, id *big.Int) (*types.Transaction, error) {
+	if err := w.CheckConfirm(ctx, opts.From, id); err != nil {
+		return nil, err
+	}
+
+	tx, err := w.contract.ConfirmTransaction(transactOpts(ctx, opts), id)
+	if err != nil {
+		return nil, &TxError{Method: "ConfirmTransaction", Err: err}
+	}
+	return tx, nil
+}
+
+// Revoke revokes the confirmation of opts.From for the pending transaction id
+func (w *Wallet) Revoke(ctx context.Context, opts *bind.TransactOpts, id *big.Int) (*types.Transaction, error) {
+	if err := w.CheckRevoke(ctx, opts.From, id); err != nil {
+		return nil, err
+	}
+
+	tx, err := w.contract.RevokeConfirmation(transactOpts(ctx, opts), id)
+	if err != nil {
+		return nil, &TxError{Method: "RevokeConfirmation", Err: err}
+	}
+	return tx, nil
+}
+
+// Execute executes the pending transaction id, opts.From must be an owner
+func (w *Wallet) Execute(ctx context.Context, opts *bind.TransactOpts, id *big.Int) (*types.Transaction, error) {
+	if err := w.CheckExecute(ctx, opts.From, id); err != nil {
+		return nil, err
+	}
+
+	tx, err := w.contract.ExecuteTransaction(transactOpts(ctx, opts), id)
+	if err != nil {
+		return nil, &TxError{Method: "ExecuteTransaction", Err: err}
+	}
+	return tx, nil
+}
+
+// Wait waits for tx to be mined and returns its receipt
+func (w *Wallet) Wait(ctx context.Context, tx *types.Transaction) (*types.Receipt, error) {
+	return bind.WaitMined(ctx, w.backend, tx)
+}
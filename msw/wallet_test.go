@@ -0,0 +1,166 @@
+package msw
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind/backends"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+type testAccount struct {
+	key  *ecdsa.PrivateKey
+	opts *bind.TransactOpts
+}
+
+func newTestAccount(t *testing.T) *testAccount {
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &testAccount{key: key, opts: bind.NewKeyedTransactor(key)}
+}
+
+// newTestWallet deploys a 2/2 contract wallet owned by a and b
+func newTestWallet(t *testing.T, a, b, c *testAccount) (*Wallet, *backends.SimulatedBackend) {
+	balance := new(big.Int).Exp(big10, big.NewInt(20), nil)
+	backend := backends.NewSimulatedBackend(core.GenesisAlloc{
+		a.opts.From: {Balance: balance},
+		b.opts.From: {Balance: balance},
+		c.opts.From: {Balance: balance},
+	}, 8000000)
+
+	address, _, _, err := DeployMultiSigWalletWithDailyLimit(a.opts, backend,
+		[]common.Address{a.opts.From, b.opts.From}, big.NewInt(2), new(big.Int))
+	if err != nil {
+		t.Fatal(err)
+	}
+	backend.Commit()
+
+	// deposit to the wallet
+	nonce, _ := backend.PendingNonceAt(context.Background(), c.opts.From)
+	tx, err := types.SignTx(types.NewTransaction(nonce, address, big.NewInt(1000), 100000, big.NewInt(1), nil), types.HomesteadSigner{}, c.key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := backend.SendTransaction(context.Background(), tx); err != nil {
+		t.Fatal(err)
+	}
+	backend.Commit()
+
+	w, err := NewWallet(address, backend, big.NewInt(1337))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return w, backend
+}
+
+func TestWallet(t *testing.T) {
+	ctx := context.Background()
+	a, b, c := newTestAccount(t), newTestAccount(t), newTestAccount(t)
+	w, backend := newTestWallet(t, a, b, c)
+
+	owners, err := w.Owners(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(owners) != 2 || owners[0] != a.opts.From || owners[1] != b.opts.From {
+		t.Fatalf("got owners %v", owners)
+	}
+
+	if _, err := w.Submit(ctx, c.opts, c.opts.From, big.NewInt(1), nil); !errors.Is(err, ErrNotOwner) {
+		t.Fatalf("submit by not owner got %v", err)
+	}
+	tx, err := w.Submit(ctx, a.opts, c.opts.From, big.NewInt(1), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	backend.Commit()
+	receipt, err := w.Wait(ctx, tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if receipt.Status != types.ReceiptStatusSuccessful {
+		t.Fatal("submit failed")
+	}
+
+	status, err := w.Status(ctx, big.NewInt(0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status.Executed || status.Confirmed || len(status.Confirmations) != 1 || status.Required.Int64() != 2 {
+		t.Fatalf("got status %+v", status)
+	}
+	if _, err := w.Status(ctx, big.NewInt(1)); !errors.Is(err, ErrTxNotFound) {
+		t.Fatalf("status of unknown ID got %v", err)
+	}
+
+	if _, err := w.Confirm(ctx, a.opts, big.NewInt(0)); !errors.Is(err, ErrAlreadyConfirmed) {
+		t.Fatalf("confirm twice got %v", err)
+	}
+	if _, err := w.Revoke(ctx, b.opts, big.NewInt(0)); !errors.Is(err, ErrNotConfirmed) {
+		t.Fatalf("revoke not confirmed got %v", err)
+	}
+
+	if err := w.CheckConfirm(ctx, b.opts.From, big.NewInt(0)); err != nil {
+		t.Fatalf("check confirm got %v", err)
+	}
+	if err := w.CheckRevoke(ctx, a.opts.From, big.NewInt(0)); err != nil {
+		t.Fatalf("check revoke got %v", err)
+	}
+	if err := w.CheckExecute(ctx, c.opts.From, big.NewInt(0)); !errors.Is(err, ErrNotOwner) {
+		t.Fatalf("check execute by not owner got %v", err)
+	}
+
+	if _, err := w.Confirm(ctx, b.opts, big.NewInt(0)); err != nil {
+		t.Fatal(err)
+	}
+	backend.Commit()
+
+	status, err = w.Status(ctx, big.NewInt(0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !status.Executed || !status.Confirmed || len(status.Confirmations) != 2 {
+		t.Fatalf("got status %+v", status)
+	}
+	if _, err := w.Execute(ctx, a.opts, big.NewInt(0)); !errors.Is(err, ErrAlreadyExecuted) {
+		t.Fatalf("execute twice got %v", err)
+	}
+}
+
+func TestBuildOffline(t *testing.T) {
+	ctx := context.Background()
+	a, b, c := newTestAccount(t), newTestAccount(t), newTestAccount(t)
+	w, _ := newTestWallet(t, a, b, c)
+
+	data, err := Pack("submitTransaction", c.opts.From, big.NewInt(1), []byte{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	offline, err := w.BuildOffline(ctx, a.opts.From, data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if offline.To != w.Address() || offline.ChainID.Int64() != 1337 || offline.GasLimit == 0 || offline.Nonce != 1 {
+		t.Fatalf("got offline transaction %+v", offline)
+	}
+
+	call, err := DecodeCall(offline.Transaction().Data())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if call.Method != "submitTransaction" || len(call.Args) != 3 || call.Args[0].Value.(common.Address) != c.opts.From {
+		t.Fatalf("got call %+v", call)
+	}
+	if _, err := DecodeCall([]byte("hello")); err != ErrUnknownMethod {
+		t.Fatalf("decode unknown got %v", err)
+	}
+}